@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"time"
+
+	_ "github.com/lib/pq"
+	"github.com/san-data-systems/common/config"
+	"github.com/san-data-systems/project-management-api/services"
+)
+
+// runCheck verifies that the dependencies of the service can be reached and prints the result of each
+// check. MinIO and Redis are only checked when they are enabled.
+func runCheck(args []string) error {
+	flags := flag.NewFlagSet("check", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "check [--timeout <duration>]", "Verify connectivity to Postgres, MinIO and Redis.")
+	timeout := flags.Duration("timeout", 5*time.Second, "timeout of each check")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	checks := []struct {
+		name    string
+		enabled bool
+		check   func(ctx context.Context) error
+	}{
+		{"postgres", true, pingConfiguredPostgres},
+		{"minio", config.Config.UseMinIO, services.PingMinIO},
+		{"redis", config.Config.UseRedis, services.PingRedis},
+	}
+
+	failed, run := 0, 0
+	for _, c := range checks {
+		if !c.enabled {
+			fmt.Printf("%-9s skipped (disabled)\n", c.name)
+			continue
+		}
+		run++
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		start := time.Now()
+		err := c.check(ctx)
+		cancel()
+		if err != nil {
+			failed++
+			fmt.Printf("%-9s FAILED after %s: %v\n", c.name, time.Since(start).Round(time.Millisecond), err)
+			continue
+		}
+		fmt.Printf("%-9s ok (%s)\n", c.name, time.Since(start).Round(time.Millisecond))
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, run)
+	}
+	return nil
+}
+
+// pingConfiguredPostgres opens a connection to the configured database and pings it, with the SSL mode
+// of DATABASE_SSLMODE (disable by default). It does not use the shared connection pool, whose
+// initialization exits the process when Postgres cannot be reached.
+func pingConfiguredPostgres(ctx context.Context) error {
+	dsn := url.URL{
+		Scheme: "postgres",
+		User:   url.UserPassword(config.Config.DatabaseUser, config.Config.DatabasePassword),
+		Host:   net.JoinHostPort(config.Config.DatabaseHost, config.Config.DatabasePort),
+		Path:   config.Config.DatabaseName,
+	}
+	sslMode := os.Getenv("DATABASE_SSLMODE")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	dsn.RawQuery = url.Values{"sslmode": {sslMode}}.Encode()
+	db, err := sql.Open("postgres", dsn.String())
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	return services.PingPostgres(ctx, db)
+}
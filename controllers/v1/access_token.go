@@ -0,0 +1,629 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListAccessTokens retrieves the access tokens of the current user, newest first.
+func ListAccessTokens(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !rejectAccessTokenAuthentication(c) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	response, ok := accessTokenResponses(c, tx, email, nil)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Access tokens retrieved successfully.")
+}
+
+// CreateAccessToken creates an access token for the current user. The token is only returned in this
+// response.
+func CreateAccessToken(c *gin.Context) {
+	var req pmv1.AccessTokenRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !rejectAccessTokenAuthentication(c) {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	response, ok := createAccessToken(c, tx, req, email, nil, email)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, response, "Access token created successfully.")
+}
+
+// RevokeAccessToken revokes an access token of the current user.
+func RevokeAccessToken(c *gin.Context) {
+	tokenID := c.Param("token_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !rejectAccessTokenAuthentication(c) {
+		return
+	}
+
+	parsedTokenID, err := utils.ConvertID(tokenID, c, email, "token id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !revokeAccessToken(c, tx, parsedTokenID, email, email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Access token revoked successfully.")
+}
+
+// ListServiceAccounts retrieves the service accounts of the organization. Only organization admins can
+// manage service accounts.
+func ListServiceAccounts(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	serviceAccounts := []pmv1.ServiceAccount{}
+	if err := tx.Where("organization_id = ?", pmmiddlewares.OrganizationID(c)).Order("name ASC").Find(&serviceAccounts).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch service accounts.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, serviceAccounts, "Service accounts retrieved successfully.")
+}
+
+// CreateServiceAccount creates a service account in the organization and makes it a member of the
+// organization, so that it can be added to projects.
+func CreateServiceAccount(c *gin.Context) {
+	var req pmv1.ServiceAccountRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Service account name must not be empty.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	var organization pmv1.Organization
+	if err := tx.Where("id = ?", pmmiddlewares.OrganizationID(c)).First(&organization).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	serviceAccountEmail := services.ServiceAccountEmail(name, organization.Slug)
+	var existing int64
+	if err := tx.Model(&pmv1.ServiceAccount{}).Where("LOWER(email) = LOWER(?)", serviceAccountEmail).Count(&existing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check service account email.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if existing > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("A service account named %s already exists.", name))
+		return
+	}
+
+	serviceAccount := pmv1.ServiceAccount{
+		OrganizationID: organization.ID,
+		Name:           name,
+		Email:          serviceAccountEmail,
+		Description:    req.Description,
+		CreatedBy:      email,
+	}
+	if err := tx.Create(&serviceAccount).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create service account.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.JoinOrganization(tx, organization.ID, serviceAccount.Email, email); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add service account to organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, serviceAccount, "Service account created successfully.")
+}
+
+// DeleteServiceAccount deletes a service account, revoking its tokens and removing it from its projects.
+func DeleteServiceAccount(c *gin.Context) {
+	serviceAccountID := c.Param("service_account_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedServiceAccountID, err := utils.ConvertID(serviceAccountID, c, email, "service account id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	serviceAccount, ok := serviceAccountByID(c, tx, parsedServiceAccountID, email)
+	if !ok {
+		return
+	}
+
+	if err := services.DeleteServiceAccount(tx, serviceAccount); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete service account.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Service account deleted successfully.")
+}
+
+// ListServiceAccountTokens retrieves the access tokens of a service account, newest first.
+func ListServiceAccountTokens(c *gin.Context) {
+	serviceAccountID := c.Param("service_account_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedServiceAccountID, err := utils.ConvertID(serviceAccountID, c, email, "service account id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	serviceAccount, ok := serviceAccountByID(c, tx, parsedServiceAccountID, email)
+	if !ok {
+		return
+	}
+
+	response, ok := accessTokenResponses(c, tx, serviceAccount.Email, &serviceAccount.ID)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Access tokens retrieved successfully.")
+}
+
+// CreateServiceAccountToken creates an access token for a service account. The token is only returned
+// in this response.
+func CreateServiceAccountToken(c *gin.Context) {
+	var req pmv1.AccessTokenRequest
+	serviceAccountID := c.Param("service_account_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !rejectAccessTokenAuthentication(c) {
+		return
+	}
+
+	parsedServiceAccountID, err := utils.ConvertID(serviceAccountID, c, email, "service account id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	serviceAccount, ok := serviceAccountByID(c, tx, parsedServiceAccountID, email)
+	if !ok {
+		return
+	}
+
+	response, ok := createAccessToken(c, tx, req, serviceAccount.Email, &serviceAccount.ID, email)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, response, "Access token created successfully.")
+}
+
+// RevokeServiceAccountToken revokes an access token of a service account.
+func RevokeServiceAccountToken(c *gin.Context) {
+	serviceAccountID := c.Param("service_account_id")
+	tokenID := c.Param("token_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedServiceAccountID, err := utils.ConvertID(serviceAccountID, c, email, "service account id")
+	if err != nil {
+		return
+	}
+
+	parsedTokenID, err := utils.ConvertID(tokenID, c, email, "token id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	serviceAccount, ok := serviceAccountByID(c, tx, parsedServiceAccountID, email)
+	if !ok {
+		return
+	}
+
+	if !revokeAccessToken(c, tx, parsedTokenID, serviceAccount.Email, email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Access token revoked successfully.")
+}
+
+// AddProjectServiceAccount adds a service account of the organization to a project with a role. Service
+// accounts cannot own projects.
+func AddProjectServiceAccount(c *gin.Context) {
+	var req pmv1.ProjectServiceAccountRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	parsedServiceAccountID, err := utils.ConvertID(req.ServiceAccountID, c, email, "service account id")
+	if err != nil {
+		return
+	}
+
+	if req.Role == pmv1.RoleOwner {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Service accounts cannot be given the Owner role.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeRoleGrant(c, tx, req.Role, email) {
+		return
+	}
+
+	serviceAccount, ok := serviceAccountByID(c, tx, parsedServiceAccountID, email)
+	if !ok {
+		return
+	}
+
+	role, err := services.ProjectRole(tx, parsedProjectID, serviceAccount.Email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if role != "" {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Service account %s is already part of the project.", serviceAccount.Name))
+		return
+	}
+
+	if err := services.SetProjectRole(tx, parsedProjectID, serviceAccount.Email, req.Role); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add service account to project.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, pmv1.ProjectServiceAccountResponse{Email: serviceAccount.Email, Role: req.Role}, "Service account added to project successfully.")
+}
+
+// rejectAccessTokenAuthentication responds with a 403 to requests authenticated with an access token,
+// so that a leaked token cannot be used to mint further tokens. It returns false when the request was
+// rejected.
+func rejectAccessTokenAuthentication(c *gin.Context) bool {
+	if !pmmiddlewares.AuthenticatedByAccessToken(c) {
+		return true
+	}
+	models.SendErrorResponse(c, http.StatusForbidden, "Access tokens cannot be managed with an access token.")
+	return false
+}
+
+// createAccessToken creates an access token for a user or service account, rolling the transaction back
+// on failure.
+func createAccessToken(c *gin.Context, tx *gorm.DB, req pmv1.AccessTokenRequest, owner string, serviceAccountID *uuid.UUID, email string) (pmv1.AccessTokenResponse, bool) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Access token name must not be empty.")
+		return pmv1.AccessTokenResponse{}, false
+	}
+
+	scopes := []string{pmv1.AccessTokenScopeRead}
+	for _, scope := range req.Scopes {
+		if scope == pmv1.AccessTokenScopeWrite {
+			scopes = append(scopes, pmv1.AccessTokenScopeWrite)
+			break
+		}
+	}
+
+	token, hash, hint, err := services.NewAccessToken()
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to generate access token.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return pmv1.AccessTokenResponse{}, false
+	}
+
+	accessToken := pmv1.AccessToken{
+		Name:             name,
+		Email:            owner,
+		ServiceAccountID: serviceAccountID,
+		TokenHash:        hash,
+		Hint:             hint,
+		Scopes:           scopes,
+		ExpiresAt:        services.AccessTokenExpiry(req.ExpiresInDays),
+		CreatedBy:        email,
+	}
+	if err := tx.Create(&accessToken).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create access token.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return pmv1.AccessTokenResponse{}, false
+	}
+
+	response := accessTokenResponse(accessToken)
+	response.Token = token
+	return response, true
+}
+
+// accessTokenResponses loads the access tokens of a user, or of a service account when serviceAccountID
+// is set, rolling the transaction back on failure.
+func accessTokenResponses(c *gin.Context, tx *gorm.DB, owner string, serviceAccountID *uuid.UUID) ([]pmv1.AccessTokenResponse, bool) {
+	query := tx.Where("LOWER(email) = LOWER(?)", owner)
+	if serviceAccountID != nil {
+		query = query.Where("service_account_id = ?", *serviceAccountID)
+	} else {
+		query = query.Where("service_account_id IS NULL")
+	}
+
+	var accessTokens []pmv1.AccessToken
+	if err := query.Order("created_at DESC").Find(&accessTokens).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch access tokens.", logrus.Fields{"error": err.Error(), "email": owner})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return nil, false
+	}
+
+	response := make([]pmv1.AccessTokenResponse, 0, len(accessTokens))
+	for _, accessToken := range accessTokens {
+		response = append(response, accessTokenResponse(accessToken))
+	}
+	return response, true
+}
+
+// revokeAccessToken revokes an access token belonging to owner, responding with a 404 when there is
+// none. Revoking a revoked token succeeds.
+func revokeAccessToken(c *gin.Context, tx *gorm.DB, tokenID uuid.UUID, owner, email string) bool {
+	var accessToken pmv1.AccessToken
+	err := tx.Where("id = ? AND LOWER(email) = LOWER(?)", tokenID, owner).First(&accessToken).Error
+	if err == gorm.ErrRecordNotFound {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return false
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch access token.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if accessToken.RevokedAt != nil {
+		return true
+	}
+
+	if err := tx.Model(&accessToken).Update("revoked_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to revoke access token.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	return true
+}
+
+// serviceAccountByID loads a service account of the organization, responding with a 404 when there is
+// none.
+func serviceAccountByID(c *gin.Context, tx *gorm.DB, serviceAccountID uuid.UUID, email string) (pmv1.ServiceAccount, bool) {
+	serviceAccount, err := services.ServiceAccountInOrganization(tx, pmmiddlewares.OrganizationID(c), serviceAccountID)
+	if err == gorm.ErrRecordNotFound {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return serviceAccount, false
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch service account.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return serviceAccount, false
+	}
+	return serviceAccount, true
+}
+
+// accessTokenResponse converts an access token to its API representation, without the token itself.
+func accessTokenResponse(accessToken pmv1.AccessToken) pmv1.AccessTokenResponse {
+	response := pmv1.AccessTokenResponse{
+		ID:         utils.ConvertUUIDToString(accessToken.ID),
+		Name:       accessToken.Name,
+		Email:      accessToken.Email,
+		Hint:       accessToken.Hint,
+		Scopes:     accessToken.Scopes,
+		ExpiresAt:  accessToken.ExpiresAt,
+		Expired:    !time.Now().Before(accessToken.ExpiresAt),
+		LastUsedAt: accessToken.LastUsedAt,
+		LastUsedIP: accessToken.LastUsedIP,
+		RevokedAt:  accessToken.RevokedAt,
+		CreatedBy:  accessToken.CreatedBy,
+		CreatedAt:  accessToken.CreatedAt,
+	}
+	if accessToken.ServiceAccountID != nil {
+		response.ServiceAccountID = utils.ConvertUUIDToString(*accessToken.ServiceAccountID)
+	}
+	return response
+}
@@ -0,0 +1,127 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ArchiveProject archives a project, hiding it from the project list and making it read-only.
+func ArchiveProject(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	project, found := archivableProject(c, tx, projectID, email)
+	if !found {
+		return
+	}
+
+	if project.Status == pmv1.ProjectStatusArchived {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Project is already archived.")
+		return
+	}
+
+	archive, err := services.ArchiveProject(tx, &project, email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to archive project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.ProjectArchiveResponse{
+		ProjectID:  project.ID.String(),
+		Archived:   true,
+		Status:     project.Status,
+		ArchivedBy: archive.ArchivedBy,
+		ArchivedAt: &archive.ArchivedAt,
+	}, "Project archived successfully.")
+}
+
+// UnarchiveProject restores an archived project to its previous status.
+func UnarchiveProject(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	project, found := archivableProject(c, tx, projectID, email)
+	if !found {
+		return
+	}
+
+	if project.Status != pmv1.ProjectStatusArchived {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Project is not archived.")
+		return
+	}
+
+	if err := services.UnarchiveProject(tx, &project); err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to unarchive project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.ProjectArchiveResponse{
+		ProjectID: project.ID.String(),
+		Archived:  false,
+		Status:    project.Status,
+	}, "Project unarchived successfully.")
+}
+
+// archivableProject loads a project owned by the user, rolling back and responding when it cannot.
+func archivableProject(c *gin.Context, tx *gorm.DB, projectID, email string) (v1.Project, bool) {
+	var project v1.Project
+
+	if err := tx.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return project, false
+		}
+		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return project, false
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return project, false
+	}
+	return project, true
+}
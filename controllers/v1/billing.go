@@ -0,0 +1,556 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// canManageClientBilling reports whether the user created the client or is one of its managers.
+func canManageClientBilling(client v1.Client, email string) bool {
+	if strings.EqualFold(client.CreatedBy, email) {
+		return true
+	}
+	for _, managerEmail := range client.ManagerEmails {
+		if strings.EqualFold(managerEmail, email) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetClientBilling retrieves the billing settings of a client.
+func GetClientBilling(c *gin.Context) {
+	id := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		} else {
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		}
+		return
+	}
+
+	if !canManageClientBilling(client, email) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	billing := pmv1.ClientBilling{ClientID: client.ID, Currency: pmv1.DefaultCurrency}
+	if err := tx.Where("client_id = ?", client.ID).First(&billing).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch client billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.ClientBillingResponse{
+		ClientID:   client.ID.String(),
+		HourlyRate: billing.HourlyRate,
+		Currency:   billing.Currency,
+		UpdatedAt:  billing.UpdatedAt,
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Client billing retrieved successfully.")
+}
+
+// UpdateClientBilling creates or updates the hourly rate and currency of a client.
+func UpdateClientBilling(c *gin.Context) {
+	var req pmv1.ClientBillingRequest
+	id := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		} else {
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		}
+		return
+	}
+
+	if !canManageClientBilling(client, email) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	billing := pmv1.ClientBilling{ClientID: client.ID, Currency: pmv1.DefaultCurrency, CreatedBy: email}
+	if err := tx.Where("client_id = ?", client.ID).First(&billing).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch client billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if req.HourlyRate != nil {
+		billing.HourlyRate = *req.HourlyRate
+	}
+	if req.Currency != "" {
+		billing.Currency = strings.ToUpper(req.Currency)
+	}
+
+	if err := tx.Save(&billing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to update billing for client with ID: %s.", id), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.ClientBillingResponse{
+		ClientID:   client.ID.String(),
+		HourlyRate: billing.HourlyRate,
+		Currency:   billing.Currency,
+		UpdatedAt:  billing.UpdatedAt,
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Client billing updated successfully.")
+}
+
+// GetProjectBilling retrieves the project rate, the inherited currency and the member rate overrides of a project.
+func GetProjectBilling(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	response, err := buildProjectBillingResponse(tx, parsedProjectID.String())
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project billing retrieved successfully.")
+}
+
+// UpdateProjectBilling creates or updates the hourly rate of a project. A null rate falls back to the client rate.
+func UpdateProjectBilling(c *gin.Context) {
+	var req pmv1.ProjectBillingRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	billing := pmv1.ProjectBilling{ProjectID: parsedProjectID, CreatedBy: email}
+	if err := tx.Where("project_id = ?", parsedProjectID).First(&billing).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	billing.HourlyRate = req.HourlyRate
+	if err := tx.Save(&billing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to update billing for project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	response, err := buildProjectBillingResponse(tx, projectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project billing updated successfully.")
+}
+
+// SetProjectMemberRate creates or updates the hourly rate override of a project member.
+func SetProjectMemberRate(c *gin.Context) {
+	var req pmv1.ProjectMemberRateRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	// The rate can only be set for an existing member of the project
+	if isMember, _ := isProjectMember(tx, projectID, req.Email); !isMember {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("User %s is not a member of project %s.", req.Email, projectID), logrus.Fields{"email": email})
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	rate := pmv1.ProjectMemberRate{ProjectID: parsedProjectID, Email: req.Email, CreatedBy: email}
+	if err := tx.Where("project_id = ? AND email = ?", parsedProjectID, req.Email).First(&rate).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch member rate.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	rate.HourlyRate = req.HourlyRate
+	if err := tx.Save(&rate).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to set member rate for %s.", req.Email), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.ProjectMemberRateItem{
+		Email:      rate.Email,
+		HourlyRate: rate.HourlyRate,
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Member rate updated successfully.")
+}
+
+// DeleteProjectMemberRate removes the hourly rate override of a project member.
+func DeleteProjectMemberRate(c *gin.Context) {
+	projectID := c.Param("project_id")
+	memberEmail := c.Param("email")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	result := tx.Where("project_id = ? AND email = ?", projectID, memberEmail).Delete(&pmv1.ProjectMemberRate{})
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete member rate for %s.", memberEmail), logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusNoContent, nil, "Member rate deleted successfully.")
+}
+
+// UpdateTimeEntryBilling marks a time entry as billable or non-billable. Invoiced entries cannot be changed.
+func UpdateTimeEntryBilling(c *gin.Context) {
+	var req pmv1.TimeEntryBillingRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, te, billing, ok := loadTimeEntryBilling(c, email)
+	if !ok {
+		return
+	}
+
+	// Billable flags can be changed by the author of the entry or an approver
+	if te.CreatedBy != email && !requirePermission(c, tx, pmv1.PermissionTimeApprove) {
+		return
+	}
+
+	if billing.InvoiceID != nil {
+		tx.Rollback()
+		logger.LogWarning(fmt.Sprintf("Time entry %s is already invoiced.", te.ID), logrus.Fields{"email": email})
+		models.SendErrorResponse(c, http.StatusConflict, "Time entry is already invoiced.")
+		return
+	}
+
+	billing.IsBillable = req.IsBillable
+	if err := tx.Save(&billing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update time entry billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, timeEntryBillingResponse(te, billing), "Time entry billing updated successfully.")
+}
+
+// ApproveTimeEntry approves a time entry so that it can be invoiced.
+func ApproveTimeEntry(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, te, billing, ok := loadTimeEntryBilling(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, te.ProjectID.String(), email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if billing.InvoiceID != nil {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Time entry is already invoiced.")
+		return
+	}
+
+	now := time.Now()
+	billing.ApprovedBy = email
+	billing.ApprovedAt = &now
+	if err := tx.Save(&billing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to approve time entry.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, timeEntryBillingResponse(te, billing), "Time entry approved successfully.")
+}
+
+// loadTimeEntryBilling starts a transaction and loads the time entry from the URL together with its billing row.
+// A missing billing row is returned as a new, billable and unapproved record.
+func loadTimeEntryBilling(c *gin.Context, email string) (*gorm.DB, v1.TimeEntry, pmv1.TimeEntryBilling, bool) {
+	var te v1.TimeEntry
+	var billing pmv1.TimeEntryBilling
+
+	parsedTeID, err := utils.ConvertID(c.Param("te_id"), c, email, "time entry id")
+	if err != nil {
+		return nil, te, billing, false
+	}
+	parsedIssueID, err := utils.ConvertID(c.Param("issue_id"), c, email, "issue id")
+	if err != nil {
+		return nil, te, billing, false
+	}
+	parsedProjectID, err := utils.ConvertID(c.Param("project_id"), c, email, "project id")
+	if err != nil {
+		return nil, te, billing, false
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return nil, te, billing, false
+	}
+
+	if err := tx.Where("id = ? AND issue_id = ? AND project_id = ?", parsedTeID, parsedIssueID, parsedProjectID).First(&te).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to retrieve time entry", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return nil, te, billing, false
+	}
+
+	billing = pmv1.TimeEntryBilling{TimeEntryID: te.ID, ProjectID: te.ProjectID, IsBillable: true}
+	if err := tx.Where("time_entry_id = ?", te.ID).First(&billing).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to retrieve time entry billing", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return nil, te, billing, false
+	}
+
+	return tx, te, billing, true
+}
+
+// timeEntryBillingResponse converts the billing state of a time entry to its API representation.
+func timeEntryBillingResponse(te v1.TimeEntry, billing pmv1.TimeEntryBilling) pmv1.TimeEntryBillingResponse {
+	response := pmv1.TimeEntryBillingResponse{
+		TimeEntryID: te.ID.String(),
+		IsBillable:  billing.IsBillable,
+		ApprovedBy:  billing.ApprovedBy,
+		ApprovedAt:  billing.ApprovedAt,
+	}
+	if billing.InvoiceID != nil {
+		invoiceID := billing.InvoiceID.String()
+		response.InvoiceID = &invoiceID
+	}
+	return response
+}
+
+// buildProjectBillingResponse loads the billing settings of a project into its API representation.
+func buildProjectBillingResponse(tx *gorm.DB, projectID string) (pmv1.ProjectBillingResponse, error) {
+	response := pmv1.ProjectBillingResponse{
+		ProjectID:   projectID,
+		Currency:    pmv1.DefaultCurrency,
+		MemberRates: []pmv1.ProjectMemberRateItem{},
+	}
+
+	var project v1.Project
+	if err := tx.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		return response, err
+	}
+
+	var clientBilling pmv1.ClientBilling
+	if err := tx.Where("client_id = ?", project.ClientID).First(&clientBilling).Error; err == nil {
+		response.Currency = clientBilling.Currency
+	} else if err != gorm.ErrRecordNotFound {
+		return response, err
+	}
+
+	var projectBilling pmv1.ProjectBilling
+	if err := tx.Where("project_id = ?", projectID).First(&projectBilling).Error; err == nil {
+		response.HourlyRate = projectBilling.HourlyRate
+	} else if err != gorm.ErrRecordNotFound {
+		return response, err
+	}
+
+	var memberRates []pmv1.ProjectMemberRate
+	if err := tx.Where("project_id = ?", projectID).Order("email ASC").Find(&memberRates).Error; err != nil {
+		return response, err
+	}
+	for _, rate := range memberRates {
+		response.MemberRates = append(response.MemberRates, pmv1.ProjectMemberRateItem{
+			Email:      rate.Email,
+			HourlyRate: rate.HourlyRate,
+		})
+	}
+
+	return response, nil
+}
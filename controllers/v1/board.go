@@ -0,0 +1,248 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetProjectBoard retrieves the kanban board of a project: its states in order with their issues
+// and work-in-progress limits, flagging the columns that are over their limit.
+func GetProjectBoard(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	columns, err := services.LoadStateColumns(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load board columns.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var issues []v1.Issue
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL AND is_draft = ?", parsedProjectID, false).
+		Order("sequence_id ASC").
+		Find(&issues).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch board issues.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	issuesByState := map[string][]pmv1.BoardIssue{}
+	for _, issue := range issues {
+		stateID := issue.StateID.String()
+		issuesByState[stateID] = append(issuesByState[stateID], pmv1.BoardIssue{
+			ID:          issue.ID.String(),
+			SequenceID:  issue.SequenceID,
+			Title:       issue.Title,
+			Priority:    issue.Priority,
+			Point:       issue.Point,
+			EndDate:     issue.EndDate,
+			CompletedAt: issue.CompletedAt,
+		})
+	}
+
+	response := pmv1.BoardResponse{
+		ProjectID:       parsedProjectID.String(),
+		Columns:         make([]pmv1.BoardColumn, 0, len(columns)),
+		OverLimitStates: services.OverLimitStates(columns),
+	}
+	for _, column := range columns {
+		boardIssues := issuesByState[column.StateID]
+		if boardIssues == nil {
+			boardIssues = []pmv1.BoardIssue{}
+		}
+		response.Columns = append(response.Columns, pmv1.BoardColumn{StateColumn: column, Issues: boardIssues})
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project board retrieved successfully.")
+}
+
+// SetStateWIPLimit sets the work-in-progress limit of a project state.
+func SetStateWIPLimit(c *gin.Context) {
+	var req pmv1.StateWIPLimitRequest
+	projectID := c.Param("project_id")
+	stateID := c.Param("state_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedStateID, err := utils.ConvertID(stateID, c, email, "state id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var state v1.ProjectState
+	if err := tx.Where("id = ? AND project_id = ? AND deleted_at IS NULL", parsedStateID, parsedProjectID).First(&state).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project state with ID: %s.", stateID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	limit := pmv1.StateWIPLimit{StateID: state.ID, ProjectID: state.ProjectID}
+	if err := tx.Where("state_id = ?", state.ID).First(&limit).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch WIP limit.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	limit.Limit = req.Limit
+	limit.Mode = req.Mode
+	limit.UpdatedBy = email
+
+	if err := tx.Save(&limit).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update WIP limit.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, limit, "WIP limit updated successfully.")
+}
+
+// DeleteStateWIPLimit removes the work-in-progress limit of a project state.
+func DeleteStateWIPLimit(c *gin.Context) {
+	projectID := c.Param("project_id")
+	stateID := c.Param("state_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedStateID, err := utils.ConvertID(stateID, c, email, "state id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	result := tx.Where("state_id = ? AND project_id = ?", parsedStateID, parsedProjectID).Delete(&pmv1.StateWIPLimit{})
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete WIP limit.", logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "WIP limit deleted successfully.")
+}
+
+// sendWIPLimitError rolls back and responds to a move refused by a hard work-in-progress limit, or to a failed check.
+func sendWIPLimitError(c *gin.Context, tx *gorm.DB, err error, email string) {
+	tx.Rollback()
+	if limitErr, ok := err.(*services.WIPLimitError); ok {
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Work-in-progress limit reached: %s.", limitErr.Error()))
+		return
+	}
+	logger.LogError("Failed to check WIP limit.", logrus.Fields{"error": err.Error(), "email": email})
+	models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+}
+
+// withWarnings sets a Warning header for each warning and appends them to a response message.
+func withWarnings(c *gin.Context, message string, warnings ...string) string {
+	var collected []string
+	for _, warning := range warnings {
+		if warning == "" {
+			continue
+		}
+		c.Writer.Header().Add("Warning", fmt.Sprintf("299 - %q", warning))
+		collected = append(collected, warning)
+	}
+	if len(collected) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s. Warning: %s.", strings.TrimSuffix(message, "."), strings.Join(collected, "; "))
+}
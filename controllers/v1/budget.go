@@ -0,0 +1,195 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetProjectBudget retrieves the hours and money budget of a project.
+func GetProjectBudget(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	budget, err := services.LoadProjectBudget(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project budget.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if budget == nil {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	card, err := services.LoadRateCard(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load project rates.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, projectBudgetResponse(*budget, card.Currency), "Project budget retrieved successfully.")
+}
+
+// UpdateProjectBudget creates or updates the budget of a project and re-evaluates its alert thresholds.
+func UpdateProjectBudget(c *gin.Context) {
+	var req pmv1.ProjectBudgetRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	budget := pmv1.ProjectBudget{ProjectID: parsedProjectID, CreatedBy: email, Thresholds: pmv1.DefaultBudgetThresholds}
+	if err := tx.Where("project_id = ?", parsedProjectID).First(&budget).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project budget.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	budget.BudgetHours = req.BudgetHours
+	budget.BudgetAmount = req.BudgetAmount
+	if len(req.Thresholds) > 0 {
+		budget.Thresholds = pq.Int64Array(req.Thresholds)
+	}
+
+	if err := tx.Save(&budget).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to update budget for project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.CheckBudgetThresholds(tx, parsedProjectID, email); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check budget thresholds.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	card, err := services.LoadRateCard(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load project rates.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, projectBudgetResponse(budget, card.Currency), "Project budget updated successfully.")
+}
+
+// GetProjectBudgetConsumption reports the hours and money logged on a project against its budget.
+func GetProjectBudgetConsumption(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	consumption, err := services.ComputeBudgetConsumption(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to compute budget consumption.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, consumption, "Project budget consumption retrieved successfully.")
+}
+
+// projectBudgetResponse converts a project budget to its API representation.
+func projectBudgetResponse(budget pmv1.ProjectBudget, currency string) pmv1.ProjectBudgetResponse {
+	return pmv1.ProjectBudgetResponse{
+		ProjectID:    budget.ProjectID.String(),
+		BudgetHours:  budget.BudgetHours,
+		BudgetAmount: budget.BudgetAmount,
+		Currency:     currency,
+		Thresholds:   []int64(budget.Thresholds),
+		UpdatedAt:    budget.UpdatedAt,
+	}
+}
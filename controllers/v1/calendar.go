@@ -0,0 +1,601 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// GetOrganizationCalendar retrieves the working days and hours per day of the current organization.
+func GetOrganizationCalendar(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	calendar, configured, err := services.LoadOrganizationCalendar(tx, pmmiddlewares.OrganizationID(c))
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, workingCalendarResponse(calendar, configured), "Organization calendar retrieved successfully.")
+}
+
+// UpdateOrganizationCalendar sets the working days and hours per day of the organization.
+// Only users managing at least one project of the organization can change it.
+func UpdateOrganizationCalendar(c *gin.Context) {
+	var req pmv1.WorkingCalendarRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeScheduleManager(c, tx, email) {
+		return
+	}
+
+	calendar, _, err := services.LoadOrganizationCalendar(tx, pmmiddlewares.OrganizationID(c))
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	calendar.Email = ""
+	calendar.WorkingDays = pq.Int64Array(req.WorkingDays)
+	calendar.HoursPerDay = *req.HoursPerDay
+	calendar.UpdatedBy = email
+
+	if err := tx.Save(&calendar).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update organization calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, workingCalendarResponse(calendar, true), "Organization calendar updated successfully.")
+}
+
+// GetMemberCalendar retrieves the calendar of the current user, or of the user given by the email query parameter.
+// Members without their own calendar follow the organization calendar.
+func GetMemberCalendar(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	target := c.DefaultQuery("email", email)
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organizationID := pmmiddlewares.OrganizationID(c)
+	var calendar pmv1.WorkingCalendar
+	configured := true
+	err := tx.Where("organization_id = ? AND LOWER(email) = ? AND email <> ''", organizationID, strings.ToLower(target)).First(&calendar).Error
+	if err == gorm.ErrRecordNotFound {
+		calendar, _, err = services.LoadOrganizationCalendar(tx, organizationID)
+		configured = false
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch member calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := workingCalendarResponse(calendar, configured)
+	response.Email = target
+	models.SendSuccessResponse(c, http.StatusOK, response, "Member calendar retrieved successfully.")
+}
+
+// UpdateMemberCalendar sets the working days and hours per day of a user, overriding the organization calendar.
+func UpdateMemberCalendar(c *gin.Context) {
+	var req pmv1.WorkingCalendarRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	target := req.Email
+	if target == "" {
+		target = email
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeMemberSchedule(c, tx, email, target) {
+		return
+	}
+
+	organizationID := pmmiddlewares.OrganizationID(c)
+	calendar := pmv1.WorkingCalendar{OrganizationID: organizationID, Email: target}
+	if err := tx.Where("organization_id = ? AND LOWER(email) = ? AND email <> ''", organizationID, strings.ToLower(target)).First(&calendar).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch member calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	calendar.WorkingDays = pq.Int64Array(req.WorkingDays)
+	calendar.HoursPerDay = *req.HoursPerDay
+	calendar.UpdatedBy = email
+
+	if err := tx.Save(&calendar).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update member calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, workingCalendarResponse(calendar, true), "Member calendar updated successfully.")
+}
+
+// DeleteMemberCalendar removes the calendar of a user so that the organization calendar applies again.
+func DeleteMemberCalendar(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	target := c.DefaultQuery("email", email)
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeMemberSchedule(c, tx, email, target) {
+		return
+	}
+
+	if err := tx.Where("organization_id = ? AND LOWER(email) = ? AND email <> ''", pmmiddlewares.OrganizationID(c), strings.ToLower(target)).
+		Delete(&pmv1.WorkingCalendar{}).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete member calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Member calendar deleted successfully.")
+}
+
+// ListHolidays lists the public holidays of the organization, optionally restricted to a year.
+func ListHolidays(c *gin.Context) {
+	var holidays []pmv1.Holiday
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	year := c.Query("year")
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	query := tx.Model(&pmv1.Holiday{}).Where("organization_id = ?", pmmiddlewares.OrganizationID(c)).Order("date ASC")
+	if year != "" {
+		query = query.Where("EXTRACT(YEAR FROM date) = ?", year)
+	}
+
+	if err := query.Find(&holidays).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to list holidays.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	responses := []pmv1.HolidayResponse{}
+	for _, holiday := range holidays {
+		responses = append(responses, holidayResponse(holiday))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, responses, "Holidays retrieved successfully.")
+}
+
+// CreateHoliday adds a public holiday to the organization calendar.
+func CreateHoliday(c *gin.Context) {
+	var req pmv1.HolidayRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		logger.LogError("Failed to parse date.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeScheduleManager(c, tx, email) {
+		return
+	}
+
+	organizationID := pmmiddlewares.OrganizationID(c)
+	var count int64
+	if err := tx.Model(&pmv1.Holiday{}).Where("organization_id = ? AND date = ?", organizationID, date).Count(&count).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check holiday.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if count > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+		return
+	}
+
+	holiday := pmv1.Holiday{OrganizationID: organizationID, Date: date, Name: req.Name, CreatedBy: email}
+	if err := tx.Create(&holiday).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create holiday.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, holidayResponse(holiday), "Holiday created successfully.")
+}
+
+// DeleteHoliday removes a public holiday from the organization calendar.
+func DeleteHoliday(c *gin.Context) {
+	id := c.Param("holiday_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	holidayID, err := utils.ConvertID(id, c, email, "holiday id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeScheduleManager(c, tx, email) {
+		return
+	}
+
+	result := tx.Where("id = ? AND organization_id = ?", holidayID, pmmiddlewares.OrganizationID(c)).Delete(&pmv1.Holiday{})
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete holiday with ID: %s.", id), logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Holiday deleted successfully.")
+}
+
+// ListTimeOff lists the time off of the current user, or of the user given by the email query parameter.
+func ListTimeOff(c *gin.Context) {
+	var entries []pmv1.TimeOff
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	target := c.DefaultQuery("email", email)
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if err := tx.Where("LOWER(email) = ?", strings.ToLower(target)).Order("start_date ASC").Find(&entries).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to list time off.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	responses := []pmv1.TimeOffResponse{}
+	for _, entry := range entries {
+		responses = append(responses, timeOffResponse(entry))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, responses, "Time off retrieved successfully.")
+}
+
+// CreateTimeOff records time off for the current user or, for Managers and Owners, for a member of their projects.
+func CreateTimeOff(c *gin.Context) {
+	var req pmv1.TimeOffRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	startDate, err := time.Parse("2006-01-02", req.StartDate)
+	if err != nil {
+		logger.LogError("Failed to parse start date.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	endDate, err := time.Parse("2006-01-02", req.EndDate)
+	if err != nil || endDate.Before(startDate) {
+		logger.LogError("Invalid end date.", logrus.Fields{"email": email, "start_date": req.StartDate, "end_date": req.EndDate})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	target := req.Email
+	if target == "" {
+		target = email
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeMemberSchedule(c, tx, email, target) {
+		return
+	}
+
+	entry := pmv1.TimeOff{Email: target, StartDate: startDate, EndDate: endDate, Reason: req.Reason, CreatedBy: email}
+	if err := tx.Create(&entry).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create time off.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, timeOffResponse(entry), "Time off created successfully.")
+}
+
+// DeleteTimeOff removes a time off entry.
+func DeleteTimeOff(c *gin.Context) {
+	id := c.Param("time_off_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	timeOffID, err := utils.ConvertID(id, c, email, "time off id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var entry pmv1.TimeOff
+	if err := tx.Where("id = ?", timeOffID).First(&entry).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch time off with ID: %s.", id), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !authorizeMemberSchedule(c, tx, email, entry.Email) {
+		return
+	}
+
+	if err := tx.Delete(&entry).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete time off with ID: %s.", id), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Time off deleted successfully.")
+}
+
+// GetCalendarDays lists the effective working days and hours of a user over a date range,
+// taking the organization calendar, the member calendar, holidays and time off into account.
+func GetCalendarDays(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	target := c.DefaultQuery("email", email)
+
+	from, to, ok := parseWorkloadRange(c, email)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	calendar, err := services.LoadCalendar(tx, pmmiddlewares.OrganizationID(c), target, from, to)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load working calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.CalendarDaysResponse{Email: target, From: from, To: to, Days: calendar.Days(from, to)}
+	for _, day := range response.Days {
+		if day.Working {
+			response.WorkingDays++
+			response.WorkingHours += day.Hours
+		}
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Calendar retrieved successfully.")
+}
+
+// authorizeScheduleManager checks that the user manages at least one project, which is required to change
+// organization-wide calendar settings. It rolls back and responds on failure.
+func authorizeScheduleManager(c *gin.Context, tx *gorm.DB, email string) bool {
+	projectIDs, err := managedProjectIDs(tx, pmmiddlewares.OrganizationID(c), email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch managed projects.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if len(projectIDs) == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return false
+	}
+	return true
+}
+
+// workingCalendarResponse converts a calendar to its API representation.
+func workingCalendarResponse(calendar pmv1.WorkingCalendar, configured bool) pmv1.WorkingCalendarResponse {
+	response := pmv1.WorkingCalendarResponse{
+		Email:       calendar.Email,
+		WorkingDays: []int64(calendar.WorkingDays),
+		HoursPerDay: calendar.HoursPerDay,
+		IsDefault:   !configured,
+	}
+	if configured {
+		response.UpdatedAt = &calendar.UpdatedAt
+	}
+	return response
+}
+
+// holidayResponse converts a holiday to its API representation.
+func holidayResponse(holiday pmv1.Holiday) pmv1.HolidayResponse {
+	return pmv1.HolidayResponse{
+		ID:        holiday.ID.String(),
+		Date:      holiday.Date,
+		Name:      holiday.Name,
+		CreatedBy: holiday.CreatedBy,
+	}
+}
+
+// timeOffResponse converts a time off entry to its API representation.
+func timeOffResponse(entry pmv1.TimeOff) pmv1.TimeOffResponse {
+	return pmv1.TimeOffResponse{
+		ID:        entry.ID.String(),
+		Email:     entry.Email,
+		StartDate: entry.StartDate,
+		EndDate:   entry.EndDate,
+		Reason:    entry.Reason,
+		CreatedBy: entry.CreatedBy,
+	}
+}
@@ -10,6 +10,8 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	"github.com/san-data-systems/project-management-api/services"
 	"gorm.io/gorm"
 	"net/http"
 	"strings"
@@ -31,7 +33,7 @@ func CreateClient(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -47,6 +49,13 @@ func CreateClient(c *gin.Context) {
 		return
 	}
 
+	if err := services.AssignClient(tx, pmmiddlewares.OrganizationID(c), client.ID); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to assign client to organization.", map[string]interface{}{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	if !utils.CommitTransaction(tx, c, email) {
 		return
 	}
@@ -75,14 +84,16 @@ func GetClientByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	var client v1.Client
 
-	if err := tx.Where("id = ? AND deleted_at IS NULL", id).First(&client).Error; err != nil {
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), map[string]interface{}{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -121,14 +132,16 @@ func UpdateClientByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	var client v1.Client
 
-	if err := tx.Where("id = ? AND created_by = ? AND deleted_at IS NULL", id, email).First(&client).Error; err != nil {
+	if err := tx.Where("id = ? AND created_by = ? AND deleted_at IS NULL", id, email).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), map[string]interface{}{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -188,7 +201,7 @@ func DeleteClientByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -196,7 +209,9 @@ func DeleteClientByID(c *gin.Context) {
 	// Soft delete the project state
 	now := time.Now()
 
-	if err := tx.Model(&v1.Client{}).Where("id = ? AND created_by = ? AND deleted_at IS NULL", id, email).Update("deleted_at", now).Error; err != nil {
+	if err := tx.Model(&v1.Client{}).Where("id = ? AND created_by = ? AND deleted_at IS NULL", id, email).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		Update("deleted_at", now).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Failed to archive client with ID: %s.", id), map[string]interface{}{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -214,7 +229,7 @@ func DeleteClientByID(c *gin.Context) {
 	models.SendSuccessResponse(c, http.StatusNoContent, nil, "Client deleted successfully.")
 }
 
-// ListClients retrieves all clients based on pagination and optional filters.
+// ListClients retrieves the clients of the organization based on pagination and optional filters.
 // Ensures the data is accessible to the requesting user.
 // Responds with a paginated list of clients.
 func ListClients(c *gin.Context) {
@@ -237,13 +252,14 @@ func ListClients(c *gin.Context) {
 	name := c.Query("name")
 	country := c.Query("country")
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Start building the query
-	query := tx.Model(&v1.Client{}).Where("deleted_at IS NULL")
+	query := tx.Model(&v1.Client{}).Where("deleted_at IS NULL").
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c)))
 
 	// If manager_emails is provided, filter by it
 	if managerEmails != "" {
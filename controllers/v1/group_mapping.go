@@ -0,0 +1,164 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// ListGroupMappings retrieves the identity provider groups mapped to teams of the organization. Only
+// organization admins can manage group mappings.
+func ListGroupMappings(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	mappings := []pmv1.GroupMapping{}
+	if err := tx.Where("organization_id = ?", pmmiddlewares.OrganizationID(c)).Order("\"group\" ASC").Find(&mappings).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch group mappings.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, mappings, "Group mappings retrieved successfully.")
+}
+
+// CreateGroupMapping maps an identity provider group to a team of the organization. Users in the group
+// join the team the next time they sign in with OIDC.
+func CreateGroupMapping(c *gin.Context) {
+	var req pmv1.GroupMappingRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	group := strings.TrimSpace(req.Group)
+	if group == "" {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Group must not be empty.")
+		return
+	}
+
+	parsedTeamID, err := utils.ConvertID(req.TeamID, c, email, "team id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	team, ok := teamByID(c, tx, parsedTeamID, email)
+	if !ok {
+		return
+	}
+
+	var existing int64
+	if err := tx.Model(&pmv1.GroupMapping{}).Where("\"group\" = ? AND team_id = ?", group, team.ID).Count(&existing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check group mapping.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if existing > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Group %s is already mapped to team %s.", group, team.Name))
+		return
+	}
+
+	mapping := pmv1.GroupMapping{
+		OrganizationID: team.OrganizationID,
+		Group:          group,
+		TeamID:         team.ID,
+		CreatedBy:      email,
+	}
+	if err := tx.Create(&mapping).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create group mapping.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, mapping, "Group mapping created successfully.")
+}
+
+// DeleteGroupMapping removes a group mapping. Team memberships granted through it are removed the next
+// time their users sign in with OIDC.
+func DeleteGroupMapping(c *gin.Context) {
+	mappingID := c.Param("group_mapping_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedMappingID, err := utils.ConvertID(mappingID, c, email, "group mapping id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	result := tx.Where("id = ? AND organization_id = ?", parsedMappingID, pmmiddlewares.OrganizationID(c)).Delete(&pmv1.GroupMapping{})
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete group mapping.", logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Group mapping deleted successfully.")
+}
@@ -0,0 +1,415 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// sentInvitation is an invitation together with the token to email once the transaction is committed.
+type sentInvitation struct {
+	invitation pmv1.ProjectInvitation
+	token      string
+}
+
+// InviteProjectMember invites a user to join a project with a role. The membership is created when the
+// invitee accepts the invitation emailed to them.
+func InviteProjectMember(c *gin.Context) {
+	var req pmv1.ProjectInvitationRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	project, ok := invitingProject(c, tx, parsedProjectID, email)
+	if !ok {
+		return
+	}
+
+	sent, ok := inviteMember(c, tx, parsedProjectID, email, req.Email, req.Role, services.InvitationExpiry(req.ExpiresInDays))
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	mailInvitations(c, project, email, sent)
+
+	models.SendSuccessResponse(c, http.StatusCreated, invitationResponse(sent.invitation, ""), "Invitation sent successfully.")
+}
+
+// ListProjectInvitations retrieves the invitations of a project. Only pending invitations are listed
+// unless the status query parameter asks for another status, or for "all".
+func ListProjectInvitations(c *gin.Context) {
+	projectID := c.Param("project_id")
+	status := c.DefaultQuery("status", pmv1.InvitationStatusPending)
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	switch status {
+	case "all", pmv1.InvitationStatusPending, pmv1.InvitationStatusAccepted, pmv1.InvitationStatusDeclined, pmv1.InvitationStatusRevoked:
+	default:
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	query := tx.Where("project_id = ?", parsedProjectID)
+	if status != "all" {
+		query = query.Where("status = ?", status)
+	}
+
+	var invitations []pmv1.ProjectInvitation
+	if err := query.Order("created_at DESC").Find(&invitations).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project invitations.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := make([]pmv1.ProjectInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		response = append(response, invitationResponse(invitation, ""))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project invitations retrieved successfully.")
+}
+
+// ResendProjectInvitation emails a pending invitation again with a new token and a new expiry. The
+// previous token can no longer be used.
+func ResendProjectInvitation(c *gin.Context) {
+	projectID := c.Param("project_id")
+	invitationID := c.Param("invitation_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedInvitationID, err := utils.ConvertID(invitationID, c, email, "invitation id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	project, ok := invitingProject(c, tx, parsedProjectID, email)
+	if !ok {
+		return
+	}
+
+	invitation, ok := projectInvitation(c, tx, parsedProjectID, parsedInvitationID, email)
+	if !ok {
+		return
+	}
+
+	token, err := services.RenewInvitation(tx, &invitation, services.InvitationExpiry(nil))
+	if err != nil {
+		sendInvitationError(c, tx, err, "Failed to renew invitation.", email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	mailInvitations(c, project, email, sentInvitation{invitation: invitation, token: token})
+
+	models.SendSuccessResponse(c, http.StatusOK, invitationResponse(invitation, ""), "Invitation resent successfully.")
+}
+
+// RevokeProjectInvitation withdraws a pending invitation. Its token can no longer be used.
+func RevokeProjectInvitation(c *gin.Context) {
+	projectID := c.Param("project_id")
+	invitationID := c.Param("invitation_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedInvitationID, err := utils.ConvertID(invitationID, c, email, "invitation id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	invitation, ok := projectInvitation(c, tx, parsedProjectID, parsedInvitationID, email)
+	if !ok {
+		return
+	}
+
+	if err := services.RevokeInvitation(tx, &invitation); err != nil {
+		sendInvitationError(c, tx, err, "Failed to revoke invitation.", email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, invitationResponse(invitation, ""), "Invitation revoked successfully.")
+}
+
+// ListMyInvitations retrieves the pending invitations sent to the user.
+func ListMyInvitations(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var invitations []pmv1.ProjectInvitation
+	if err := tx.Where("LOWER(email) = LOWER(?) AND status = ? AND expires_at > ?", email, pmv1.InvitationStatusPending, time.Now()).
+		Order("created_at DESC").
+		Find(&invitations).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch invitations.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	projectIDs := make([]uuid.UUID, 0, len(invitations))
+	for _, invitation := range invitations {
+		projectIDs = append(projectIDs, invitation.ProjectID)
+	}
+	var projects []v1.Project
+	if len(projectIDs) > 0 {
+		if err := tx.Select("id, name").Where("id IN ? AND deleted_at IS NULL", projectIDs).Find(&projects).Error; err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to fetch invited projects.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	names := make(map[uuid.UUID]string, len(projects))
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+
+	response := make([]pmv1.ProjectInvitationResponse, 0, len(invitations))
+	for _, invitation := range invitations {
+		// Invitations to trashed projects cannot be accepted
+		name, live := names[invitation.ProjectID]
+		if !live {
+			continue
+		}
+		response = append(response, invitationResponse(invitation, name))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Invitations retrieved successfully.")
+}
+
+// AcceptInvitation makes the user a member of the project they were invited to. The token must have been
+// sent to the email of the user and can be used once.
+func AcceptInvitation(c *gin.Context) {
+	answerInvitation(c, services.AcceptInvitation, "Failed to accept invitation.", "Invitation accepted successfully.")
+}
+
+// DeclineInvitation declines an invitation sent to the user.
+func DeclineInvitation(c *gin.Context) {
+	answerInvitation(c, services.DeclineInvitation, "Failed to decline invitation.", "Invitation declined successfully.")
+}
+
+// answerInvitation answers an invitation with its token on behalf of the user.
+func answerInvitation(c *gin.Context, answer func(*gorm.DB, string, string) (pmv1.ProjectInvitation, error), failure, success string) {
+	var req pmv1.InvitationTokenRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	invitation, err := answer(tx, req.Token, email)
+	if err != nil {
+		sendInvitationError(c, tx, err, failure, email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, invitationResponse(invitation, ""), success)
+}
+
+// inviteMember invites target to a project with a role after checking that the user may grant it.
+// It rolls back and responds when the invitation cannot be created.
+func inviteMember(c *gin.Context, tx *gorm.DB, projectID uuid.UUID, email, target, role string, expiresAt time.Time) (sentInvitation, bool) {
+	if !authorizeRoleGrant(c, tx, role, email) {
+		return sentInvitation{}, false
+	}
+
+	// Only users holding project.admin may grant the Owner role
+	if !guardOwnerChange(c, tx, projectID, email, v1.ProjectMember{}, role) {
+		return sentInvitation{}, false
+	}
+
+	invitation, token, err := services.CreateInvitation(tx, projectID, strings.TrimSpace(target), role, email, expiresAt)
+	if err != nil {
+		sendInvitationError(c, tx, err, "Failed to create invitation.", email)
+		return sentInvitation{}, false
+	}
+	return sentInvitation{invitation: invitation, token: token}, true
+}
+
+// mailInvitations emails invitations once they are committed. Failures are logged rather than returned:
+// the invitation exists and can be resent.
+func mailInvitations(c *gin.Context, project v1.Project, email string, sent ...sentInvitation) {
+	for _, s := range sent {
+		mail := services.InvitationMail(s.invitation, project.Name, s.token)
+		if err := services.SendMail(c.Request.Context(), mail); err != nil {
+			logger.LogWarning(fmt.Sprintf("Failed to email invitation %s.", s.invitation.ID), logrus.Fields{"error": err.Error(), "email": email})
+		}
+	}
+}
+
+// invitingProject loads a live project to invite members to. It rolls back and responds when it cannot.
+func invitingProject(c *gin.Context, tx *gorm.DB, projectID uuid.UUID, email string) (v1.Project, bool) {
+	var project v1.Project
+	if err := tx.Where("id = ? AND deleted_at IS NULL", projectID).First(&project).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return project, false
+		}
+		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return project, false
+	}
+	return project, true
+}
+
+// projectInvitation loads an invitation of a project. It rolls back and responds when it cannot.
+func projectInvitation(c *gin.Context, tx *gorm.DB, projectID, invitationID uuid.UUID, email string) (pmv1.ProjectInvitation, bool) {
+	var invitation pmv1.ProjectInvitation
+	if err := tx.Where("id = ? AND project_id = ?", invitationID, projectID).First(&invitation).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return invitation, false
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch invitation with ID: %s.", invitationID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return invitation, false
+	}
+	return invitation, true
+}
+
+// sendInvitationError rolls back and responds to an invitation that could not be created or answered.
+func sendInvitationError(c *gin.Context, tx *gorm.DB, err error, message, email string) {
+	tx.Rollback()
+	if invitationErr, ok := err.(*services.InvitationError); ok {
+		if invitationErr.Expired {
+			models.SendErrorResponse(c, http.StatusGone, invitationErr.Error())
+			return
+		}
+		models.SendErrorResponse(c, http.StatusConflict, invitationErr.Error())
+		return
+	}
+	if err == gorm.ErrRecordNotFound {
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+	logger.LogError(message, logrus.Fields{"error": err.Error(), "email": email})
+	models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+}
+
+// invitationResponse converts an invitation to its API representation.
+func invitationResponse(invitation pmv1.ProjectInvitation, projectName string) pmv1.ProjectInvitationResponse {
+	return pmv1.ProjectInvitationResponse{
+		ID:          invitation.ID.String(),
+		ProjectID:   invitation.ProjectID.String(),
+		ProjectName: projectName,
+		Email:       invitation.Email,
+		Role:        invitation.Role,
+		Status:      invitation.Status,
+		InvitedBy:   invitation.InvitedBy,
+		ExpiresAt:   invitation.ExpiresAt,
+		Expired:     invitation.Status == pmv1.InvitationStatusPending && time.Now().After(invitation.ExpiresAt),
+		RespondedAt: invitation.RespondedAt,
+		CreatedAt:   invitation.CreatedAt,
+	}
+}
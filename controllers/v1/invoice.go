@@ -0,0 +1,477 @@
+package v1
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// CreateClientInvoice creates a draft invoice for a client from the approved, billable and unbilled
+// time entries logged on the client's projects within the requested period, and marks those entries as billed.
+func CreateClientInvoice(c *gin.Context) {
+	var req pmv1.InvoiceRequest
+	id := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	layout := "2006-01-02"
+	periodStart, err := time.Parse(layout, req.PeriodStart)
+	if err != nil {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Period start is not in correct format.")
+		return
+	}
+	periodEnd, err := time.Parse(layout, req.PeriodEnd)
+	if err != nil {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Period end is not in correct format.")
+		return
+	}
+	if periodEnd.Before(periodStart) {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Period end must not be before period start.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		} else {
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		}
+		return
+	}
+
+	if !canManageClientBilling(client, email) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	// Lock the billing rows of every candidate entry so that concurrent drafts cannot bill them twice
+	billings, err := services.LockBillableTimeEntries(tx, client.ID, periodStart, periodEnd)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch billable time entries.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if len(billings) == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "No approved, unbilled time entries found for the period.")
+		return
+	}
+
+	timeEntryIDs := make([]uuid.UUID, len(billings))
+	for i, billing := range billings {
+		timeEntryIDs[i] = billing.TimeEntryID
+	}
+
+	var timeEntries []v1.TimeEntry
+	if err := tx.Where("id IN ?", timeEntryIDs).Order("date ASC, start_time ASC").Find(&timeEntries).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch time entries.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	number, err := services.NextInvoiceNumber(tx, time.Now())
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to number invoice.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	invoice := pmv1.Invoice{
+		ClientID:    client.ID,
+		Number:      number,
+		Status:      pmv1.InvoiceStatusDraft,
+		Currency:    pmv1.DefaultCurrency,
+		PeriodStart: periodStart,
+		PeriodEnd:   periodEnd,
+		CreatedBy:   email,
+	}
+
+	// Rate cards are resolved once per project
+	rateCards := map[uuid.UUID]*services.RateCard{}
+	for _, te := range timeEntries {
+		card, found := rateCards[te.ProjectID]
+		if !found {
+			card, err = services.LoadRateCard(tx, te.ProjectID)
+			if err != nil {
+				tx.Rollback()
+				logger.LogError(fmt.Sprintf("Failed to load rates for project %s.", te.ProjectID), logrus.Fields{"error": err.Error(), "email": email})
+				models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+				return
+			}
+			rateCards[te.ProjectID] = card
+			invoice.Currency = card.Currency
+		}
+
+		hours := services.TimeEntryHours(te)
+		rate := card.RateFor(te.CreatedBy)
+		amount := services.RoundAmount(hours * rate)
+
+		invoice.Lines = append(invoice.Lines, pmv1.InvoiceLine{
+			TimeEntryID: te.ID,
+			ProjectID:   te.ProjectID,
+			IssueID:     te.IssueID,
+			Email:       te.CreatedBy,
+			Date:        te.Date,
+			Description: te.Notes,
+			Hours:       hours,
+			HourlyRate:  rate,
+			Amount:      amount,
+		})
+		invoice.TotalHours += hours
+		invoice.TotalAmount += amount
+	}
+	invoice.TotalHours = services.RoundAmount(invoice.TotalHours)
+	invoice.TotalAmount = services.RoundAmount(invoice.TotalAmount)
+
+	if !utils.CreateWithRollback(tx, c, &invoice, "Failed to create invoice.", email) {
+		return
+	}
+
+	// Mark the time entries as billed on this invoice
+	if err := services.MarkTimeEntriesInvoiced(tx, timeEntryIDs, invoice.ID); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to mark time entries as billed.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, invoiceResponse(invoice, true), "Invoice draft created successfully.")
+}
+
+// ListClientInvoices lists the invoices of a client with pagination and an optional status filter.
+func ListClientInvoices(c *gin.Context) {
+	var invoices []pmv1.Invoice
+	id := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	pagination, err := utils.ParsePagination(c)
+	if err != nil {
+		logger.LogError("Invalid pagination parameters.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	status := c.Query("status")
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Client with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !canManageClientBilling(client, email) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	query := tx.Model(&pmv1.Invoice{}).Where("client_id = ?", client.ID).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Scopes(utils.Paginate(query, pagination)).Scan(&invoices).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to list invoices.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	responses := []pmv1.InvoiceResponse{}
+	for _, invoice := range invoices {
+		responses = append(responses, invoiceResponse(invoice, false))
+	}
+
+	meta := models.PaginationMeta{
+		Total: pagination.TotalCount,
+		Page:  pagination.Page,
+		Limit: pagination.PageSize,
+	}
+
+	models.SendPaginatedSuccessResponse(c, responses, meta, "Invoices retrieved successfully.")
+}
+
+// GetInvoiceByID retrieves an invoice with its lines as JSON.
+func GetInvoiceByID(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, invoice, _, ok := loadInvoice(c, email)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, invoiceResponse(invoice, true), "Invoice retrieved successfully.")
+}
+
+// ExportInvoice exports an invoice as a printable HTML document, a downloadable PDF document or a
+// downloadable JSON file, depending on the format query parameter (html by default).
+func ExportInvoice(c *gin.Context) {
+	format := c.DefaultQuery("format", "html")
+	if format != "html" && format != "pdf" && format != "json" {
+		models.SendErrorResponse(c, http.StatusBadRequest, "Format must be either html, pdf or json.")
+		return
+	}
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, invoice, client, ok := loadInvoice(c, email)
+	if !ok {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := invoiceResponse(invoice, true)
+	if format == "json" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.json", invoice.Number))
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	document := services.InvoiceDocument{
+		Invoice:    response,
+		ClientName: client.Name,
+		Country:    client.Country,
+	}
+	render, contentType := services.RenderInvoiceHTML, "text/html; charset=utf-8"
+	if format == "pdf" {
+		render, contentType = services.RenderInvoicePDF, "application/pdf"
+	}
+
+	var buf bytes.Buffer
+	if err := render(&buf, document); err != nil {
+		logger.LogError(fmt.Sprintf("Failed to render invoice %s.", invoice.Number), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if format == "pdf" {
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s.pdf", invoice.Number))
+	}
+	c.Data(http.StatusOK, contentType, buf.Bytes())
+}
+
+// IssueInvoice finalises a draft invoice.
+func IssueInvoice(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, invoice, _, ok := loadInvoice(c, email)
+	if !ok {
+		return
+	}
+
+	if invoice.Status != pmv1.InvoiceStatusDraft {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Only draft invoices can be issued.")
+		return
+	}
+
+	now := time.Now()
+	if err := tx.Model(&invoice).Updates(map[string]interface{}{
+		"status":    pmv1.InvoiceStatusIssued,
+		"issued_at": now,
+	}).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to issue invoice %s.", invoice.Number), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	invoice.Status = pmv1.InvoiceStatusIssued
+	invoice.IssuedAt = &now
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, invoiceResponse(invoice, true), "Invoice issued successfully.")
+}
+
+// VoidInvoice voids an invoice and releases its time entries so that they can be billed again.
+func VoidInvoice(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, invoice, _, ok := loadInvoice(c, email)
+	if !ok {
+		return
+	}
+
+	if invoice.Status == pmv1.InvoiceStatusVoid {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Invoice is already void.")
+		return
+	}
+
+	if err := tx.Model(&invoice).Update("status", pmv1.InvoiceStatusVoid).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to void invoice %s.", invoice.Number), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.ReleaseInvoicedTimeEntries(tx, invoice.ID); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to release invoiced time entries.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	invoice.Status = pmv1.InvoiceStatusVoid
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, invoiceResponse(invoice, true), "Invoice voided successfully.")
+}
+
+// loadInvoice starts a transaction and loads the invoice from the URL with its lines and client,
+// ensuring the user can manage the client's billing.
+func loadInvoice(c *gin.Context, email string) (*gorm.DB, pmv1.Invoice, v1.Client, bool) {
+	var invoice pmv1.Invoice
+	var client v1.Client
+
+	invoiceID, err := utils.ConvertID(c.Param("invoice_id"), c, email, "invoice id")
+	if err != nil {
+		return nil, invoice, client, false
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return nil, invoice, client, false
+	}
+
+	if err := tx.Preload("Lines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("date ASC, email ASC")
+	}).Where("id = ?", invoiceID).First(&invoice).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Invoice with ID: %s not found.", invoiceID), logrus.Fields{"error": err.Error(), "email": email})
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		} else {
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		}
+		return nil, invoice, client, false
+	}
+
+	if err := tx.Where("id = ?", invoice.ClientID).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		First(&client).Error; err != nil || !canManageClientBilling(client, email) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return nil, invoice, client, false
+	}
+
+	return tx, invoice, client, true
+}
+
+// invoiceResponse converts an invoice to its API representation, optionally including its lines.
+func invoiceResponse(invoice pmv1.Invoice, withLines bool) pmv1.InvoiceResponse {
+	response := pmv1.InvoiceResponse{
+		ID:          invoice.ID.String(),
+		ClientID:    invoice.ClientID.String(),
+		Number:      invoice.Number,
+		Status:      invoice.Status,
+		Currency:    invoice.Currency,
+		PeriodStart: invoice.PeriodStart,
+		PeriodEnd:   invoice.PeriodEnd,
+		TotalHours:  invoice.TotalHours,
+		TotalAmount: invoice.TotalAmount,
+		CreatedBy:   invoice.CreatedBy,
+		IssuedAt:    invoice.IssuedAt,
+		CreatedAt:   invoice.CreatedAt,
+	}
+	if !withLines {
+		return response
+	}
+
+	response.Lines = []pmv1.InvoiceLineResponse{}
+	for _, line := range invoice.Lines {
+		response.Lines = append(response.Lines, pmv1.InvoiceLineResponse{
+			TimeEntryID: line.TimeEntryID.String(),
+			ProjectID:   line.ProjectID.String(),
+			IssueID:     line.IssueID.String(),
+			Email:       line.Email,
+			Date:        line.Date,
+			Description: line.Description,
+			Hours:       line.Hours,
+			HourlyRate:  line.HourlyRate,
+			Amount:      line.Amount,
+		})
+	}
+	return response
+}
@@ -12,6 +12,8 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/metrics"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -40,14 +42,13 @@ func CreateIssue(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, convertID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, convertID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -158,6 +159,29 @@ func CreateIssue(c *gin.Context) {
 		EstimatedHours:      req.EstimatedHours,
 	}
 
+	// Issues can only be created in the states the workflow lets the creator's role enter
+	if err := services.CheckEntry(tx, parsedrojectID, stateID, memberRole(tx, parsedrojectID.String(), email)); err != nil {
+		sendTransitionError(c, tx, err, email)
+		return
+	}
+
+	// Issues created directly in a completed or cancelled state are completed straight away
+	category, err := services.StateCategory(tx, stateID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch state category.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	services.ApplyStateCategory(&issue, category, time.Now())
+
+	// Enforce the work-in-progress limit of the target state
+	wipWarning, err := services.CheckWIPLimit(tx, stateID, 1)
+	if err != nil {
+		sendWIPLimitError(c, tx, err, email)
+		return
+	}
+
 	// Create the Issue in the database
 	if !utils.CreateWithRollback(tx, c, &issue, "Failed to create Issue", email) {
 		return
@@ -203,6 +227,8 @@ func CreateIssue(c *gin.Context) {
 		return
 	}
 
+	metrics.IssuesCreated(1)
+
 	// Prepare the response
 	response := v1.IssueResponse{
 		ID:                  utils.ConvertUUIDToString(issue.ID),
@@ -227,7 +253,7 @@ func CreateIssue(c *gin.Context) {
 	if issue.CompletedAt != nil {
 		response.CompletedAt = *issue.CompletedAt
 	}
-	models.SendSuccessResponse(c, http.StatusCreated, response, "Issue created successfully")
+	models.SendSuccessResponse(c, http.StatusCreated, response, withWarnings(c, "Issue created successfully", wipWarning))
 
 }
 
@@ -254,14 +280,13 @@ func UpdateIssueByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, convertID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, convertID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -340,6 +365,7 @@ func UpdateIssueByID(c *gin.Context) {
 	}
 
 	var state v1.ProjectState
+	var wipWarning string
 	if req.StateID != nil {
 
 		stateID, _ := utils.ConvertID(*req.StateID, c, email, "state")
@@ -356,7 +382,20 @@ func UpdateIssueByID(c *gin.Context) {
 			return
 		}
 
-		Issue.StateID = stateID
+		// Enforce the work-in-progress limit of the target state
+		if Issue.StateID != stateID {
+			wipWarning, err = services.CheckWIPLimit(tx, stateID, 1)
+			if err != nil {
+				sendWIPLimitError(c, tx, err, email)
+				return
+			}
+		}
+
+		// Enforce the workflow rules of the project and keep CompletedAt in line with the state category
+		if err := services.MoveIssueToState(tx, &Issue, stateID, email, memberRole(tx, projectID, email)); err != nil {
+			sendTransitionError(c, tx, err, email)
+			return
+		}
 	}
 
 	if err := tx.Save(&Issue).Error; err != nil {
@@ -423,7 +462,7 @@ func UpdateIssueByID(c *gin.Context) {
 		response.CompletedAt = *Issue.CompletedAt
 	}
 
-	models.SendSuccessResponse(c, http.StatusOK, response, "Issue updated successfully.")
+	models.SendSuccessResponse(c, http.StatusOK, response, withWarnings(c, "Issue updated successfully.", wipWarning))
 
 }
 
@@ -440,7 +479,7 @@ func ListIssues(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -471,8 +510,7 @@ func ListIssues(c *gin.Context) {
 	comletedPercentage := c.Query("competed_percentage")
 
 	// Check if the user is authorized to list Issues
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -686,14 +724,13 @@ func GetIssueByID(c *gin.Context) {
 	}
 
 	// start transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Check if the user is authorized to view the Issue
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -821,7 +858,7 @@ func DeleteIssue(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -832,8 +869,7 @@ func DeleteIssue(c *gin.Context) {
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, convertID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, convertID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -852,7 +888,8 @@ func DeleteIssue(c *gin.Context) {
 		return
 	}
 
-	if err := tx.Model(&Issue).Update("deleted_at", time.Now()).Error; err != nil {
+	// Sub-issues are trashed with the issue so that restoring it revives them
+	if err := services.SoftDeleteIssueTree(tx, Issue.ID, services.DeletionTime()); err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Failed to delete Issue with ID: %s for user: %s", id, email), logrus.Fields{"error": err.Error(), "email": email})
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrBadRequest)
@@ -32,12 +32,12 @@ func ListIssueActivitiesByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
-	authorized, _ := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, _ := isProjectMember(tx, projectID, email)
 	if !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
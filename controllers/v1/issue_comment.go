@@ -0,0 +1,196 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListIssueComments retrieves the comments of an issue, oldest first.
+func ListIssueComments(c *gin.Context) {
+	projectID := c.Param("project_id")
+	issueID := c.Param("issue_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedIssueID, err := utils.ConvertID(issueID, c, email, "issue id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !commentableIssue(c, tx, parsedProjectID, parsedIssueID, email) {
+		return
+	}
+
+	var comments []pmv1.IssueComment
+	if err := tx.Where("project_id = ? AND issue_id = ? AND deleted_at IS NULL", parsedProjectID, parsedIssueID).
+		Order("created_at ASC").
+		Find(&comments).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch issue comments.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, comments, "Issue comments retrieved successfully.")
+}
+
+// CreateIssueComment adds a comment to an issue.
+func CreateIssueComment(c *gin.Context) {
+	var req pmv1.IssueCommentRequest
+	projectID := c.Param("project_id")
+	issueID := c.Param("issue_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedIssueID, err := utils.ConvertID(issueID, c, email, "issue id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !commentableIssue(c, tx, parsedProjectID, parsedIssueID, email) {
+		return
+	}
+
+	comment := pmv1.IssueComment{
+		ProjectID: parsedProjectID,
+		IssueID:   parsedIssueID,
+		Body:      req.Body,
+		CreatedBy: email,
+	}
+	if err := tx.Create(&comment).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create issue comment.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, comment, "Issue comment created successfully.")
+}
+
+// DeleteIssueComment deletes a comment. Comments can be deleted by their author or by users who may edit the issue.
+func DeleteIssueComment(c *gin.Context) {
+	projectID := c.Param("project_id")
+	issueID := c.Param("issue_id")
+	commentID := c.Param("comment_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedIssueID, err := utils.ConvertID(issueID, c, email, "issue id")
+	if err != nil {
+		return
+	}
+
+	parsedCommentID, err := utils.ConvertID(commentID, c, email, "comment id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var comment pmv1.IssueComment
+	if err := tx.Where("id = ? AND project_id = ? AND issue_id = ? AND deleted_at IS NULL", parsedCommentID, parsedProjectID, parsedIssueID).
+		First(&comment).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch issue comment with ID: %s.", commentID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if comment.CreatedBy != email && !requirePermission(c, tx, pmv1.PermissionIssueEdit) {
+		return
+	}
+
+	if err := tx.Model(&comment).Update("deleted_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete issue comment.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Issue comment deleted successfully.")
+}
+
+// commentableIssue checks that the issue exists in the project and is not trashed. It rolls back and
+// responds when it does not.
+func commentableIssue(c *gin.Context, tx *gorm.DB, projectID, issueID uuid.UUID, email string) bool {
+	if err := tx.Where("id = ? AND project_id = ? AND deleted_at IS NULL", issueID, projectID).First(&v1.Issue{}).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return false
+		}
+		logger.LogError("Failed to fetch issue.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	return true
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -41,14 +42,13 @@ func UploadIssueFiles(c *gin.Context) {
 	}
 
 	// Start a database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, convertedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, convertedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -121,7 +121,7 @@ func UploadIssueFiles(c *gin.Context) {
 		fileName := fmt.Sprintf("issues/%s/files/%s%s", issueID, fileID.String(), ext)
 
 		// Upload file to MinIO
-		err = mcclient.UploadFile(c, projectID, fileName, fileContent)
+		err = services.UploadObject(c, mcclient, projectID, fileName, fileContent)
 		if err != nil {
 			tx.Rollback()
 			logger.LogError(fmt.Sprintf("Failed to upload file: %s", fileName), logrus.Fields{"error": err.Error(), "email": email})
@@ -130,7 +130,7 @@ func UploadIssueFiles(c *gin.Context) {
 		}
 
 		// Get presigned URL for the uploaded file
-		fileURL, err := mcclient.GetPresignedURL(c, projectID, fileName, time.Duration(24)*time.Hour)
+		fileURL, err := services.ObjectURL(c, mcclient, projectID, fileName, int64(len(fileContent)), time.Duration(24)*time.Hour)
 		if err != nil {
 			tx.Rollback()
 			logger.LogError(fmt.Sprintf("Failed to get presigned URL for file: %s", fileName), logrus.Fields{"error": err.Error(), "email": email})
@@ -200,14 +200,13 @@ func GetIssueFiles(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, convertedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, convertedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -263,7 +262,7 @@ func GetIssueFiles(c *gin.Context) {
 	var fileResponses []v1.IssueFileResponse
 	for _, file := range issueFiles {
 		// presignedURL, err := mcclient.GetPresignedURL(c, projectID, "files/"+file.FileName, time.Duration(24)*time.Hour)
-		presignedURL, err := mcclient.GetPresignedURL(c, projectID, fmt.Sprintf("issues/%s/files/", issueID)+file.FileName, time.Duration(24)*time.Hour)
+		presignedURL, err := services.DownloadURL(c, mcclient, projectID, fmt.Sprintf("issues/%s/files/", issueID)+file.FileName, file.FileSize, time.Duration(24)*time.Hour)
 		if err != nil {
 			logger.LogError("Failed to generate pre-signed URL", logrus.Fields{"error": err.Error(), "file_name": file.FileName, "email": email})
 			continue // Skip this file, but proceed with others
@@ -314,14 +313,13 @@ func DeleteIssueFileByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -45,14 +45,13 @@ func CreateIssueLink(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -119,14 +118,13 @@ func ListIssueLinks(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -205,7 +203,7 @@ func GetIssueLinkByID(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -265,7 +263,7 @@ func UpdateIssueLinkByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -278,8 +276,7 @@ func UpdateIssueLinkByID(c *gin.Context) {
 		return
 	}
 	// Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -342,7 +339,7 @@ func DeleteIssueLink(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -355,8 +352,7 @@ func DeleteIssueLink(c *gin.Context) {
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
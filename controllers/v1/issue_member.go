@@ -31,21 +31,19 @@ func AddAssigneeToIssue(c *gin.Context) {
 	}
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// check authorization to add assignee
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
 
 	// Check if the assignee is part of the project
-	authorized, role = utils.IsUserPartOfRole(tx, projectID, req.Email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, req.Email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -116,7 +114,7 @@ func GetAssignees(c *gin.Context) {
 	}
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -184,7 +182,7 @@ func DeleteAssigneeByID(c *gin.Context) {
 	AssigneeID := c.Param("assignee_id")
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
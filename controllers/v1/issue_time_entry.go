@@ -11,6 +11,9 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/metrics"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -39,14 +42,13 @@ func CreateIssueTimeEntry(c *gin.Context) {
 	}
 
 	// Start a database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -139,11 +141,35 @@ func CreateIssueTimeEntry(c *gin.Context) {
 		return
 	}
 
+	// Re-evaluate the project budget alerts
+	if err := services.CheckBudgetThresholds(tx, parsedProjectID, email); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check budget thresholds.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	// Warn when the hours are logged on a non-working day of the member's calendar
+	calendar, err := services.LoadCalendar(tx, pmmiddlewares.OrganizationID(c), email, parsedDate, parsedDate)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load working calendar.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	// Commit the transaction
 	if !utils.CommitTransaction(tx, c, email) {
 		return
 	}
 
+	metrics.TimeLogged(endDateTime.Sub(startDateTime))
+
+	message := "Time entry created successfully"
+	if reason := calendar.NonWorkingReason(parsedDate); reason != "" {
+		message = withWarnings(c, message, fmt.Sprintf("%s is not a working day (%s)", request.Date, reason))
+	}
+
 	res := v1.TimeEntryResponse{
 		ID:                  issueTimeEntry.ID.String(),
 		ProjectID:           issueTimeEntry.ProjectID.String(),
@@ -159,7 +185,7 @@ func CreateIssueTimeEntry(c *gin.Context) {
 	}
 
 	// Send the response
-	models.SendSuccessResponse(c, http.StatusCreated, res, "Time entry created successfully")
+	models.SendSuccessResponse(c, http.StatusCreated, res, message)
 
 }
 
@@ -175,14 +201,13 @@ func ListIssueTimeEntries(c *gin.Context) {
 	projectID := c.Param("project_id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -313,13 +338,12 @@ func GetIssueTimeEntryByID(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -379,14 +403,13 @@ func UpdateIssueTimeEntryByID(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -411,6 +434,20 @@ func UpdateIssueTimeEntryByID(c *gin.Context) {
 		return
 	}
 
+	// Invoiced entries are frozen, approved ones need a new approval once edited
+	billing, err := services.LockEditableTimeEntryBilling(tx, te.ID)
+	if err == services.ErrTimeEntryInvoiced {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Time entry is already invoiced.")
+		return
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to retrieve time entry billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	if request.Date != "" {
 		// Định nghĩa layout cho chuỗi thời gian
 		dateLayout := "2006-01-02" // Layout cho ngày (năm-tháng-ngày)
@@ -475,6 +512,23 @@ func UpdateIssueTimeEntryByID(c *gin.Context) {
 		return
 	}
 
+	if billing != nil && billing.ApprovedAt != nil {
+		if err := tx.Model(billing).Updates(map[string]interface{}{"approved_by": "", "approved_at": nil}).Error; err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to reset time entry approval.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+	}
+
+	// Re-evaluate the project budget alerts
+	if err := services.CheckBudgetThresholds(tx, parsedProjectID, email); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check budget thresholds.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	// Commit the transaction
 	if !utils.CommitTransaction(tx, c, email) {
 		return
@@ -516,14 +570,13 @@ func DeleteIssueTimeEntry(c *gin.Context) {
 	parsedProjectID, _ := utils.ConvertID(projectID, c, email, "project id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	//	Check if the user is authorized to create an Issue
-	authorized := utils.CanUserCreateIssue(tx, parsedProjectID, email)
-	if !authorized {
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -536,6 +589,19 @@ func DeleteIssueTimeEntry(c *gin.Context) {
 		return
 	}
 
+	_, err := services.LockEditableTimeEntryBilling(tx, te.ID)
+	if err == services.ErrTimeEntryInvoiced {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Time entry is already invoiced.")
+		return
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to retrieve time entry billing.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	// Soft delete the time entry
 	if err := tx.Delete(&te).Error; err != nil {
 		tx.Rollback()
@@ -544,6 +610,14 @@ func DeleteIssueTimeEntry(c *gin.Context) {
 		return
 	}
 
+	// Re-evaluate the project budget alerts
+	if err := services.CheckBudgetThresholds(tx, parsedProjectID, email); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check budget thresholds.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
 	// Commit the transaction
 	if !utils.CommitTransaction(tx, c, email) {
 		return
@@ -0,0 +1,150 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/sirupsen/logrus"
+)
+
+// ListNotifications lists the notifications of the current user, newest first.
+// The unread query parameter restricts the list to unread notifications.
+func ListNotifications(c *gin.Context) {
+	var notifications []pmv1.Notification
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	pagination, err := utils.ParsePagination(c)
+	if err != nil {
+		logger.LogError("Invalid pagination parameters.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return
+	}
+
+	unread := c.Query("unread")
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	query := tx.Model(&pmv1.Notification{}).Where("email = ?", email).Order("created_at DESC")
+	if unread == "true" {
+		query = query.Where("read_at IS NULL")
+	}
+
+	if err := query.Scopes(utils.Paginate(query, pagination)).Scan(&notifications).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to list notifications.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	responses := []pmv1.NotificationResponse{}
+	for _, notification := range notifications {
+		response := pmv1.NotificationResponse{
+			ID:        notification.ID.String(),
+			Type:      notification.Type,
+			Title:     notification.Title,
+			Message:   notification.Message,
+			ReadAt:    notification.ReadAt,
+			CreatedAt: notification.CreatedAt,
+		}
+		if notification.ProjectID != nil {
+			projectID := notification.ProjectID.String()
+			response.ProjectID = &projectID
+		}
+		responses = append(responses, response)
+	}
+
+	meta := models.PaginationMeta{
+		Total: pagination.TotalCount,
+		Page:  pagination.Page,
+		Limit: pagination.PageSize,
+	}
+
+	models.SendPaginatedSuccessResponse(c, responses, meta, "Notifications retrieved successfully.")
+}
+
+// MarkNotificationRead marks a single notification of the current user as read.
+func MarkNotificationRead(c *gin.Context) {
+	id := c.Param("notification_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	notificationID, err := utils.ConvertID(id, c, email, "notification id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	result := tx.Model(&pmv1.Notification{}).
+		Where("id = ? AND email = ? AND read_at IS NULL", notificationID, email).
+		Update("read_at", time.Now())
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to mark notification %s as read.", id), logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Notification marked as read.")
+}
+
+// MarkAllNotificationsRead marks every unread notification of the current user as read.
+func MarkAllNotificationsRead(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if err := tx.Model(&pmv1.Notification{}).
+		Where("email = ? AND read_at IS NULL", email).
+		Update("read_at", time.Now()).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to mark notifications as read.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Notifications marked as read.")
+}
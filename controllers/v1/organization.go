@@ -0,0 +1,526 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListOrganizations retrieves the organizations the user belongs to and their role in each.
+func ListOrganizations(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organizations, err := services.UserOrganizations(tx, email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organizations.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	response := make([]pmv1.OrganizationResponse, 0, len(organizations))
+	for _, organization := range organizations {
+		role, err := services.OrganizationRole(tx, organization.ID, email)
+		if err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to fetch organization role.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		response = append(response, organizationResponse(organization, role))
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Organizations retrieved successfully.")
+}
+
+// CreateOrganization creates an organization with the user as its first admin.
+func CreateOrganization(c *gin.Context) {
+	var req pmv1.OrganizationRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	name, slug, ok := organizationNameAndSlug(c, req)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !organizationSlugAvailable(c, tx, slug, nil, email) {
+		return
+	}
+
+	organization := pmv1.Organization{Name: name, Slug: slug, CreatedBy: email}
+	if err := tx.Create(&organization).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := tx.Create(&pmv1.OrganizationMember{
+		OrganizationID: organization.ID,
+		Email:          email,
+		Role:           pmv1.OrganizationRoleAdmin,
+		AddedBy:        email,
+	}).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add organization admin.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, organizationResponse(organization, pmv1.OrganizationRoleAdmin), "Organization created successfully.")
+}
+
+// GetOrganization retrieves an organization the user belongs to with its members.
+func GetOrganization(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organization, role, ok := memberOrganization(c, tx, email)
+	if !ok {
+		return
+	}
+
+	var members []pmv1.OrganizationMember
+	if err := tx.Where("organization_id = ?", organization.ID).Order("email ASC").Find(&members).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization members.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := organizationResponse(organization, role)
+	response.Members = make([]pmv1.OrganizationMemberResponse, 0, len(members))
+	for _, member := range members {
+		response.Members = append(response.Members, pmv1.OrganizationMemberResponse{
+			Email:     member.Email,
+			Role:      member.Role,
+			AddedBy:   member.AddedBy,
+			CreatedAt: member.CreatedAt,
+		})
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Organization retrieved successfully.")
+}
+
+// UpdateOrganization renames an organization or changes its slug. Only admins can update it.
+func UpdateOrganization(c *gin.Context) {
+	var req pmv1.OrganizationRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	name, slug, ok := organizationNameAndSlug(c, req)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organization, ok := adminOrganization(c, tx, email)
+	if !ok {
+		return
+	}
+
+	if !organizationSlugAvailable(c, tx, slug, &organization, email) {
+		return
+	}
+
+	organization.Name = name
+	organization.Slug = slug
+	if err := tx.Save(&organization).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, organizationResponse(organization, pmv1.OrganizationRoleAdmin), "Organization updated successfully.")
+}
+
+// AddOrganizationMember adds a user to an organization with a role. Only admins can add members.
+func AddOrganizationMember(c *gin.Context) {
+	var req pmv1.OrganizationMemberRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	if req.Email == "" {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Email is required.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organization, ok := adminOrganization(c, tx, email)
+	if !ok {
+		return
+	}
+
+	existing, err := services.OrganizationRole(tx, organization.ID, req.Email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if existing != "" {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("%s is already a member of the organization.", req.Email))
+		return
+	}
+
+	member := pmv1.OrganizationMember{
+		OrganizationID: organization.ID,
+		Email:          req.Email,
+		Role:           req.Role,
+		AddedBy:        email,
+	}
+	if err := tx.Create(&member).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add organization member.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, pmv1.OrganizationMemberResponse{
+		Email:     member.Email,
+		Role:      member.Role,
+		AddedBy:   member.AddedBy,
+		CreatedAt: member.CreatedAt,
+	}, "Organization member added successfully.")
+}
+
+// UpdateOrganizationMember changes the role of an organization member. Only admins can change roles,
+// and the last admin cannot be demoted.
+func UpdateOrganizationMember(c *gin.Context) {
+	var req pmv1.OrganizationMemberRequest
+	target := c.Param("email")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	organization, ok := adminOrganization(c, tx, email)
+	if !ok {
+		return
+	}
+
+	var member pmv1.OrganizationMember
+	if err := tx.Where("organization_id = ? AND LOWER(email) = LOWER(?)", organization.ID, target).First(&member).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch organization member %s.", target), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if member.Role == pmv1.OrganizationRoleAdmin && req.Role != pmv1.OrganizationRoleAdmin && !guardLastOrganizationAdmin(c, tx, organization.ID, email) {
+		return
+	}
+
+	member.Role = req.Role
+	if err := tx.Save(&member).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update organization member role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.OrganizationMemberResponse{
+		Email:     member.Email,
+		Role:      member.Role,
+		AddedBy:   member.AddedBy,
+		CreatedAt: member.CreatedAt,
+	}, "Organization member role updated successfully.")
+}
+
+// RemoveOrganizationMember removes a user from an organization and its teams. Admins can remove anyone
+// and members can leave; the last admin cannot leave. The user immediately loses access to the clients
+// and projects of the organization.
+func RemoveOrganizationMember(c *gin.Context) {
+	target := c.Param("email")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var organization pmv1.Organization
+	if strings.EqualFold(target, email) {
+		organization, _, ok = memberOrganization(c, tx, email)
+	} else {
+		organization, ok = adminOrganization(c, tx, email)
+	}
+	if !ok {
+		return
+	}
+
+	role, err := services.OrganizationRole(tx, organization.ID, target)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if role == "" {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+	if role == pmv1.OrganizationRoleAdmin && !guardLastOrganizationAdmin(c, tx, organization.ID, email) {
+		return
+	}
+
+	if _, err := services.LeaveOrganization(tx, organization.ID, target); err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to remove %s from organization.", target), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Organization member removed successfully.")
+}
+
+// authorizeOrganizationAdmin checks that the user is an admin of the organization resolved for the
+// request. It rolls back and responds when they are not.
+func authorizeOrganizationAdmin(c *gin.Context, tx *gorm.DB) bool {
+	if c.GetString(pmmiddlewares.OrganizationRoleKey) == pmv1.OrganizationRoleAdmin {
+		return true
+	}
+	tx.Rollback()
+	models.SendErrorResponse(c, http.StatusForbidden, "You do not have permission to perform this action.")
+	return false
+}
+
+// authorizeOrganizationClient checks that a client belongs to the organization resolved for the request.
+// Clients of other organizations are reported as missing. It rolls back and responds on failure.
+func authorizeOrganizationClient(c *gin.Context, tx *gorm.DB, clientID uuid.UUID, email string) bool {
+	inOrganization, err := services.ClientInOrganization(tx, pmmiddlewares.OrganizationID(c), clientID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch client organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if !inOrganization {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return false
+	}
+	return true
+}
+
+// memberOrganization loads the organization of the organization_id parameter and the role of the user
+// in it. Organizations the user does not belong to are reported as missing. It rolls back and responds
+// on failure.
+func memberOrganization(c *gin.Context, tx *gorm.DB, email string) (pmv1.Organization, string, bool) {
+	organization, err := services.FindOrganization(tx, c.Param("organization_id"))
+	if err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return organization, "", false
+	}
+
+	var role string
+	if err == nil {
+		if role, err = services.OrganizationRole(tx, organization.ID, email); err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to fetch organization role.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return organization, "", false
+		}
+	}
+	if role == "" {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return organization, "", false
+	}
+	return organization, role, true
+}
+
+// adminOrganization loads the organization of the organization_id parameter, checking that the user is
+// one of its admins. It rolls back and responds on failure.
+func adminOrganization(c *gin.Context, tx *gorm.DB, email string) (pmv1.Organization, bool) {
+	organization, role, ok := memberOrganization(c, tx, email)
+	if !ok {
+		return organization, false
+	}
+	if role != pmv1.OrganizationRoleAdmin {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusForbidden, "You do not have permission to perform this action.")
+		return organization, false
+	}
+	return organization, true
+}
+
+// guardLastOrganizationAdmin checks that an organization keeps at least one admin when one is demoted or
+// removed. It rolls back and responds when the admin is the last one.
+func guardLastOrganizationAdmin(c *gin.Context, tx *gorm.DB, organizationID uuid.UUID, email string) bool {
+	admins, err := services.CountOrganizationAdmins(tx, organizationID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to count organization admins.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if admins <= 1 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "An organization must keep at least one admin.")
+		return false
+	}
+	return true
+}
+
+// organizationNameAndSlug validates an organization request, responding when it is invalid.
+func organizationNameAndSlug(c *gin.Context, req pmv1.OrganizationRequest) (string, string, bool) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Organization name must not be empty.")
+		return "", "", false
+	}
+	slug := strings.ToLower(strings.TrimSpace(req.Slug))
+	if !services.ValidOrganizationSlug(slug) {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Organization slug must contain only lowercase letters, digits and hyphens.")
+		return "", "", false
+	}
+	return name, slug, true
+}
+
+// organizationSlugAvailable checks that no other organization uses a slug. It rolls back and responds
+// when the slug is taken.
+func organizationSlugAvailable(c *gin.Context, tx *gorm.DB, slug string, current *pmv1.Organization, email string) bool {
+	query := tx.Model(&pmv1.Organization{}).Where("slug = ?", slug)
+	if current != nil {
+		query = query.Where("id <> ?", current.ID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check organization slug.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if count > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+		return false
+	}
+	return true
+}
+
+// organizationResponse converts an organization to its API representation.
+func organizationResponse(organization pmv1.Organization, role string) pmv1.OrganizationResponse {
+	return pmv1.OrganizationResponse{
+		ID:        organization.ID.String(),
+		Name:      organization.Name,
+		Slug:      organization.Slug,
+		Role:      role,
+		CreatedBy: organization.CreatedBy,
+		CreatedAt: organization.CreatedAt,
+	}
+}
@@ -0,0 +1,120 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TransferProjectOwnership makes another user an Owner of a project and demotes, or removes, the previous Owner.
+// Only users holding project.admin may transfer ownership, and a project always keeps at least one Owner.
+func TransferProjectOwnership(c *gin.Context) {
+	var req pmv1.TransferOwnershipRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return
+	}
+
+	from := req.FromEmail
+	if from == "" {
+		from = email
+	}
+	if fromRole, err := services.ProjectRole(tx, parsedProjectID, from); err != nil || fromRole != pmv1.RoleOwner {
+		tx.Rollback()
+		if err != nil {
+			logger.LogError("Failed to fetch project member role.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, fmt.Sprintf("%s is not an Owner of the project.", from))
+		return
+	}
+
+	if err := services.TransferOwnership(tx, parsedProjectID, from, req.Email, req.PreviousRole, req.RemovePrevious); err != nil {
+		sendOwnershipError(c, tx, err, email)
+		return
+	}
+
+	owners, err := services.ProjectOwners(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project owners.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.ProjectOwnersResponse{
+		ProjectID: parsedProjectID.String(),
+		Owners:    owners,
+	}, "Project ownership transferred successfully.")
+}
+
+// sendOwnershipError rolls back and responds to a membership change refused to protect the last Owner, or to a failed change.
+func sendOwnershipError(c *gin.Context, tx *gorm.DB, err error, email string) {
+	tx.Rollback()
+	if lastOwner, ok := err.(*services.LastOwnerError); ok {
+		models.SendErrorResponse(c, http.StatusConflict, lastOwner.Error())
+		return
+	}
+	logger.LogError("Failed to update project members.", logrus.Fields{"error": err.Error(), "email": email})
+	models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+}
+
+// guardOwnerChange checks a change of the role of target to newRole, an empty newRole meaning removal.
+// Only users holding project.admin may grant or take away the Owner role, and the last Owner cannot be
+// removed or demoted. It rolls back and responds when the change is refused.
+func guardOwnerChange(c *gin.Context, tx *gorm.DB, projectID uuid.UUID, email string, target v1.ProjectMember, newRole string) bool {
+	if target.Role != pmv1.RoleOwner && newRole != pmv1.RoleOwner {
+		return true
+	}
+	if target.Role == newRole {
+		return true
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return false
+	}
+
+	if target.Role == pmv1.RoleOwner {
+		if err := services.GuardLastOwner(tx, projectID, target.Email); err != nil {
+			sendOwnershipError(c, tx, err, email)
+			return false
+		}
+	}
+	return true
+}
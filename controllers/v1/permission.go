@@ -0,0 +1,454 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListRoles retrieves the built-in roles and the custom roles of the organization with the permissions
+// they grant.
+func ListRoles(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var custom []pmv1.CustomRole
+	if err := tx.Where("organization_id = ?", pmmiddlewares.OrganizationID(c)).Order("name ASC").Find(&custom).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch custom roles.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	builtIn := []string{pmv1.RoleOwner, pmv1.RoleManager, pmv1.RoleContributor, pmv1.RoleWatcher, pmv1.RoleClientReviewer}
+	response := make([]pmv1.RoleResponse, 0, len(builtIn)+len(custom))
+	for _, role := range builtIn {
+		response = append(response, pmv1.RoleResponse{
+			Name:        role,
+			Permissions: pmv1.BuiltInRolePermissions[role],
+			BuiltIn:     true,
+		})
+	}
+	for _, role := range custom {
+		response = append(response, customRoleResponse(role))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Roles retrieved successfully.")
+}
+
+// ListPermissions retrieves every permission that roles can grant.
+func ListPermissions(c *gin.Context) {
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.Permissions, "Permissions retrieved successfully.")
+}
+
+// CreateCustomRole defines a new role of the organization from a set of permissions. Only organization
+// admins can define roles.
+func CreateCustomRole(c *gin.Context) {
+	var req pmv1.CustomRoleRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	permissions, ok := customRolePermissions(c, req)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if !customRoleNameAvailable(c, tx, name, nil, email) {
+		return
+	}
+
+	role := pmv1.CustomRole{
+		OrganizationID: pmmiddlewares.OrganizationID(c),
+		Name:           name,
+		Description:    req.Description,
+		Permissions:    permissions,
+		CreatedBy:      email,
+		UpdatedBy:      email,
+	}
+	if err := tx.Create(&role).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to create custom role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, customRoleResponse(role), "Custom role created successfully.")
+}
+
+// UpdateCustomRole replaces the name, description and permissions of a custom role. Members holding the
+// role are granted the new permissions immediately.
+func UpdateCustomRole(c *gin.Context) {
+	var req pmv1.CustomRoleRequest
+	roleID := c.Param("role_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedRoleID, err := utils.ConvertID(roleID, c, email, "role id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	permissions, ok := customRolePermissions(c, req)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	var role pmv1.CustomRole
+	if err := tx.Where("id = ? AND organization_id = ?", parsedRoleID, pmmiddlewares.OrganizationID(c)).First(&role).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch custom role with ID: %s.", roleID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name != role.Name {
+		if !customRoleNameAvailable(c, tx, name, &role, email) {
+			return
+		}
+		// Memberships refer to roles by name
+		projects := services.OrganizationProjects(tx, role.OrganizationID)
+		if err := tx.Model(&v1.ProjectMember{}).Where("role = ? AND project_id IN (?)", role.Name, projects).Update("role", name).Error; err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to rename custom role of project members.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		if err := tx.Model(&pmv1.ProjectTeam{}).Where("role = ? AND project_id IN (?)", role.Name, projects).Update("role", name).Error; err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to rename custom role of project teams.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+	}
+
+	role.Name = name
+	role.Description = req.Description
+	role.Permissions = permissions
+	role.UpdatedBy = email
+	if err := tx.Save(&role).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update custom role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, customRoleResponse(role), "Custom role updated successfully.")
+}
+
+// DeleteCustomRole deletes a custom role. Roles still held by project members or teams cannot be deleted.
+func DeleteCustomRole(c *gin.Context) {
+	roleID := c.Param("role_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedRoleID, err := utils.ConvertID(roleID, c, email, "role id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeOrganizationAdmin(c, tx) {
+		return
+	}
+
+	var role pmv1.CustomRole
+	if err := tx.Where("id = ? AND organization_id = ?", parsedRoleID, pmmiddlewares.OrganizationID(c)).First(&role).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch custom role with ID: %s.", roleID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	projects := services.OrganizationProjects(tx, role.OrganizationID)
+	var holders int64
+	if err := tx.Model(&v1.ProjectMember{}).Where("role = ? AND project_id IN (?)", role.Name, projects).Count(&holders).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to count project members holding custom role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if holders > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Role is held by %d project members. Change their role before deleting it.", holders))
+		return
+	}
+
+	var teams int64
+	if err := tx.Model(&pmv1.ProjectTeam{}).Where("role = ? AND project_id IN (?)", role.Name, projects).Count(&teams).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to count project teams holding custom role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if teams > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("Role is held by %d project teams. Change their role before deleting it.", teams))
+		return
+	}
+
+	if err := tx.Delete(&role).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to delete custom role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Custom role deleted successfully.")
+}
+
+// GetProjectPermissions retrieves the role and permissions of the user in a project.
+func GetProjectPermissions(c *gin.Context) {
+	role := c.GetString(pmmiddlewares.ProjectRoleKey)
+	permissions, _ := c.Get(pmmiddlewares.ProjectPermissionsKey)
+	granted, _ := permissions.([]string)
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.ProjectPermissionsResponse{
+		ProjectID:   c.Param("project_id"),
+		Role:        role,
+		Permissions: granted,
+	}, "Project permissions retrieved successfully.")
+}
+
+// UpdateProjectMemberRole gives a project member a built-in or custom role. Users cannot grant a role
+// carrying permissions they do not hold themselves.
+func UpdateProjectMemberRole(c *gin.Context) {
+	var req pmv1.ProjectMemberRoleRequest
+	projectID := c.Param("project_id")
+	target := c.Param("email")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeRoleGrant(c, tx, req.Role, email) {
+		return
+	}
+
+	var member v1.ProjectMember
+	if err := tx.Where("project_id = ? AND LOWER(email) = LOWER(?)", parsedProjectID, target).First(&member).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project member %s.", target), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !guardOwnerChange(c, tx, parsedProjectID, email, member, req.Role) {
+		return
+	}
+
+	if err := services.SetProjectRole(tx, parsedProjectID, member.Email, req.Role); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update project member role.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	member.Role = req.Role
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, member, "Project member role updated successfully.")
+}
+
+// hasPermission reports whether the user holds a permission in the project of the request, as resolved
+// by the RequirePermission middleware.
+func hasPermission(c *gin.Context, permission string) bool {
+	permissions, exists := c.Get(pmmiddlewares.ProjectPermissionsKey)
+	if !exists {
+		return false
+	}
+	granted, _ := permissions.([]string)
+	return services.Grants(granted, permission)
+}
+
+// requirePermission checks a permission the handler needs on top of the one enforced on its route.
+// It rolls back and responds when the user does not hold it.
+func requirePermission(c *gin.Context, tx *gorm.DB, permission string) bool {
+	if hasPermission(c, permission) {
+		return true
+	}
+	tx.Rollback()
+	models.SendErrorResponse(c, http.StatusForbidden, "You do not have permission to perform this action.")
+	return false
+}
+
+// authorizeRoleGrant checks that a role exists and that the user holds every permission it grants, so
+// that nobody can hand out more than they have. It rolls back and responds when they may not grant it.
+func authorizeRoleGrant(c *gin.Context, tx *gorm.DB, role, email string) bool {
+	permissions, exists, err := services.RolePermissions(tx, pmmiddlewares.OrganizationID(c), role)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch role permissions.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if !exists {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, fmt.Sprintf("Role %q does not exist.", role))
+		return false
+	}
+	for _, permission := range permissions {
+		if !requirePermission(c, tx, permission) {
+			return false
+		}
+	}
+	return true
+}
+
+// customRolePermissions validates the permissions of a custom role request, responding when they are invalid.
+func customRolePermissions(c *gin.Context, req pmv1.CustomRoleRequest) ([]string, bool) {
+	permissions, err := services.NormalizePermissions(req.Permissions)
+	if err != nil {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, err.Error())
+		return nil, false
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Role name must not be empty.")
+		return nil, false
+	}
+	return permissions, true
+}
+
+// customRoleNameAvailable checks that no built-in role or other custom role of the organization uses a
+// name. It rolls back and responds when the name is taken.
+func customRoleNameAvailable(c *gin.Context, tx *gorm.DB, name string, current *pmv1.CustomRole, email string) bool {
+	if services.IsBuiltInRole(name) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, fmt.Sprintf("%s is a built-in role.", name))
+		return false
+	}
+
+	query := tx.Model(&pmv1.CustomRole{}).Where("organization_id = ? AND LOWER(name) = LOWER(?)", pmmiddlewares.OrganizationID(c), name)
+	if current != nil {
+		query = query.Where("id <> ?", current.ID)
+	}
+	var count int64
+	if err := query.Count(&count).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check custom role name.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	if count > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+		return false
+	}
+	return true
+}
+
+// customRoleResponse converts a custom role to its API representation.
+func customRoleResponse(role pmv1.CustomRole) pmv1.RoleResponse {
+	return pmv1.RoleResponse{
+		ID:          role.ID.String(),
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: []string(role.Permissions),
+		CreatedBy:   role.CreatedBy,
+		UpdatedAt:   &role.UpdatedAt,
+	}
+}
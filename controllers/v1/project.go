@@ -15,6 +15,10 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/metrics"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -45,7 +49,7 @@ func CreateProject(c *gin.Context) {
 	}
 
 	// Start transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -69,18 +73,73 @@ func CreateProject(c *gin.Context) {
 	}
 
 	var client v1.Client
-	if err := tx.First(&client, "id = ?", req.ClientID).Error; err != nil {
+	if err := tx.Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).First(&client, "id = ?", req.ClientID).Error; err != nil {
 		logger.LogError(fmt.Sprintf("Client not found with ID: %s.", req.ClientID), logrus.Fields{"error": err.Error(), "email": email})
 		tx.Rollback()
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
 
+	// Load the template the project is created from, if any
+	var definition *pmv1.TemplateDefinition
+	if templateID := c.Query("template_id"); templateID != "" {
+		parsedTemplateID, err := utils.ConvertID(templateID, c, email, "template id")
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+
+		var template pmv1.ProjectTemplate
+		if err := tx.Where("id = ? AND organization_id = ? AND deleted_at IS NULL", parsedTemplateID, pmmiddlewares.OrganizationID(c)).First(&template).Error; err != nil {
+			tx.Rollback()
+			if err == gorm.ErrRecordNotFound {
+				models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+				return
+			}
+			logger.LogError(fmt.Sprintf("Failed to fetch project template with ID: %s.", templateID), logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+
+		decoded, err := services.DecodeTemplateDefinition(template)
+		if err != nil {
+			tx.Rollback()
+			logger.LogError(fmt.Sprintf("Project template with ID: %s has an invalid definition.", templateID), logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		definition = &decoded
+	}
+
 	// Save the project to the database
 	if !utils.CreateWithRollback(tx, c, &project, "Failed to create project.", email) {
 		return
 	}
 
+	if err := services.AssignProject(tx, pmmiddlewares.OrganizationID(c), project.ID); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to assign project to organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	// The creator is the first Owner of the project
+	if err := services.SetProjectRole(tx, project.ID, email, pmv1.RoleOwner); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add project owner.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if definition != nil {
+		if err := services.ApplyProjectDefinition(tx, project, *definition, email); err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to apply project template.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+	}
+
 	// Preload project data
 	var projectr v1.Project
 	if err := tx.Preload("Client").Where("id = ?", project.ID).First(&projectr).Error; err != nil {
@@ -126,6 +185,10 @@ func CreateProject(c *gin.Context) {
 		return
 	}
 
+	if definition != nil {
+		metrics.IssuesCreated(len(definition.Issues))
+	}
+
 	// Prepare response
 	response := v1.ProjectResponse{
 		ID:          projectr.ID,
@@ -165,13 +228,13 @@ func GetProjectByID(c *gin.Context) {
 	}
 
 	// Start a transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		// Rollback and return if the transaction could not be started
 		return
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, id, email)
+	authorized, role := isProjectMember(tx, id, email)
 	if !authorized && role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -251,13 +314,13 @@ func UpdateProjectByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
 	var project v1.Project
-	if err := tx.Where("id = ? AND deleted_at IS NULL AND created_by = ?", id, email).First(&project).Error; err != nil {
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).First(&project).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -268,16 +331,34 @@ func UpdateProjectByID(c *gin.Context) {
 		return
 	}
 
+	if !requirePermission(c, tx, pmv1.PermissionProjectEdit) {
+		return
+	}
+
+	if project.Status == pmv1.ProjectStatusArchived {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, "Project is archived and read-only. Unarchive it to make changes.")
+		return
+	}
+
 	var req v1.ProjectRequest
 	if !utils.BindJSONRequest(c, &req, email) {
 		tx.Rollback()
 		return
 	}
+	if req.Status == pmv1.ProjectStatusArchived {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Use the archive endpoint to archive a project.")
+		return
+	}
 	if req.ClientID != "" {
 		userID, err := utils.ConvertID(req.ClientID, c, email, "client id")
 		if err != nil {
 			return
 		}
+		if !authorizeOrganizationClient(c, tx, userID, email) {
+			return
+		}
 		project.ClientID = userID
 	}
 
@@ -381,14 +462,14 @@ func DeleteProjectByID(c *gin.Context) {
 		return // The response is already sent by the helper, so just return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Find the project by ID and check permissions
 	var project v1.Project
-	if err := tx.Where("id = ? AND deleted_at IS NULL AND created_by = ?", id, email).First(&project).Error; err != nil {
+	if err := tx.Where("id = ? AND deleted_at IS NULL", id).First(&project).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", id), logrus.Fields{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -399,9 +480,12 @@ func DeleteProjectByID(c *gin.Context) {
 		return
 	}
 
-	now := time.Now()
-	project.DeletedAt = &now
-	if err := tx.Save(&project).Error; err != nil {
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return
+	}
+
+	// Issues, states and labels are trashed with the project so that restoring it revives them
+	if err := services.SoftDeleteProject(tx, &project, services.DeletionTime()); err != nil {
 		tx.Rollback()
 		logger.LogError("Failed to archive project.", logrus.Fields{"error": err.Error(), "email": email})
 		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
@@ -442,7 +526,7 @@ func ListProjects(c *gin.Context) {
 	endDate := c.Query("end_date")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -450,10 +534,10 @@ func ListProjects(c *gin.Context) {
 	query := tx.Model(&v1.Project{}).
 		Select("projects.id, projects.name, projects.slug, projects.description, projects.client_id, projects.start_date, projects.end_date, projects.status, projects.tags, projects.created_by, projects.created_at, projects.updated_at, projects.cover_page_id, "+
 			"STRING_AGG(project_members.email, ',') AS member_emails, STRING_AGG(project_members.role, ',') AS member_roles").
-		Joins("LEFT JOIN project_members ON project_members.project_id = projects.id").
+		Joins("LEFT JOIN project_members ON project_members.project_id = projects.id AND project_members.email = ?", email).
 		Where("projects.deleted_at IS NULL").
-		Where("projects.created_by = ? OR project_members.email = ?", email, email).
-		Where("project_members.role IN (?) OR project_members.role IS NULL", []string{"Manager", "Watcher", "Contributor"}).
+		Where("projects.id IN (?)", services.MemberProjects(tx, email)).
+		Where("projects.id IN (?)", services.OrganizationProjects(tx, pmmiddlewares.OrganizationID(c))).
 		Group("projects.id")
 
 	// Log the raw SQL query
@@ -478,6 +562,11 @@ func ListProjects(c *gin.Context) {
 		query = query.Where("projects.status = ?", status)
 	}
 
+	// Archived projects are hidden unless requested explicitly
+	if c.Query("include_archived") != "true" && status != pmv1.ProjectStatusArchived {
+		query = query.Where("projects.status IS DISTINCT FROM ?", pmv1.ProjectStatusArchived)
+	}
+
 	if priority != "" {
 		query = query.Where("projects.priority = ?", priority)
 	}
@@ -586,6 +675,81 @@ func ListProjects(c *gin.Context) {
 
 // GetProjectStatsByID retrieves statistical data for a specific project by its ID.
 // This includes metrics like contributions, activity levels, and related stats.
+// Columns over their work-in-progress limit are reported in over_limit_states.
 func GetProjectStatsByID(c *gin.Context) {
-	// TODO: Implement logic to fetch project statistics by ID.
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	columns, err := services.LoadStateColumns(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to load project states.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var overdue int64
+	if err := tx.Model(&v1.Issue{}).
+		Where("project_id = ? AND deleted_at IS NULL AND is_draft = ? AND completed_at IS NULL AND end_date < ?", parsedProjectID, false, services.TruncateDay(time.Now())).
+		Count(&overdue).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to count overdue issues.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	hoursByMember, err := services.ProjectHoursByMember(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to sum logged hours.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.ProjectStatsResponse{
+		ProjectID:       parsedProjectID.String(),
+		OverdueIssues:   overdue,
+		ByCategory:      map[string]int64{},
+		States:          columns,
+		OverLimitStates: services.OverLimitStates(columns),
+	}
+	for _, column := range columns {
+		response.TotalIssues += column.IssueCount
+		response.ByCategory[column.Category] += column.IssueCount
+		if pmv1.IsClosedStateCategory(column.Category) {
+			response.ClosedIssues += column.IssueCount
+		} else {
+			response.OpenIssues += column.IssueCount
+		}
+	}
+	for _, hours := range hoursByMember {
+		response.LoggedHours += hours
+	}
+	response.LoggedHours = services.RoundHours(response.LoggedHours)
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project stats retrieved successfully.")
 }
@@ -30,13 +30,12 @@ func ListProjectActivitiesByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, id, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, id, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
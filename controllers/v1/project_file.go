@@ -10,6 +10,8 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"io"
@@ -28,20 +30,24 @@ func ChangeProjectCoverImageByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Retrieve the project from the database
 	var project v1.Project
-	if err := tx.Where("id = ? AND created_by = ?", id, email).First(&project).Error; err != nil {
+	if err := tx.Where("id = ?", id).First(&project).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Project not found with ID: %s", id), logrus.Fields{"error": err.Error(), "email": email})
 		models.SendErrorResponse(c, http.StatusNotFound, "Project not found")
 		return
 	}
 
+	if !requirePermission(c, tx, pmv1.PermissionProjectEdit) {
+		return
+	}
+
 	ProjectID, err := utils.ConvertID(id, c, email, "project id")
 	if err != nil {
 		return // Early return if conversion fails, error response is already handled
@@ -97,12 +103,12 @@ func UploadProjectFiles(c *gin.Context) {
 	}
 
 	// Start a database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
-	authorized, _ := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, _ := isProjectMember(tx, projectID, email)
 	if !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -167,7 +173,7 @@ func UploadProjectFiles(c *gin.Context) {
 		fileName := "files/" + fmt.Sprintf("%s%s", fileID.String(), ext)
 
 		// Upload file to MinIO
-		err = mcclient.UploadFile(c, projectID, fileName, fileContent)
+		err = services.UploadObject(c, mcclient, projectID, fileName, fileContent)
 		if err != nil {
 			tx.Rollback()
 			logger.LogError(fmt.Sprintf("Failed to upload file: %s", fileName), logrus.Fields{"error": err.Error(), "email": email})
@@ -176,7 +182,7 @@ func UploadProjectFiles(c *gin.Context) {
 		}
 
 		// Get presigned URL for the uploaded file
-		fileURL, err := mcclient.GetPresignedURL(c, projectID, fileName, time.Duration(24)*time.Hour)
+		fileURL, err := services.ObjectURL(c, mcclient, projectID, fileName, int64(len(fileContent)), time.Duration(24)*time.Hour)
 		if err != nil {
 			tx.Rollback()
 			logger.LogError(fmt.Sprintf("Failed to get presigned URL for file: %s", fileName), logrus.Fields{"error": err.Error(), "email": email})
@@ -238,12 +244,12 @@ func GetProjectFiles(c *gin.Context) {
 		return // Error response is already handled in ConvertID
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
-	authorized, _ := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, _ := isProjectMember(tx, projectID, email)
 	if !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -295,7 +301,7 @@ func GetProjectFiles(c *gin.Context) {
 
 	var fileResponses []v1.ProjectFileResponse
 	for _, file := range projectFiles {
-		presignedURL, err := mcclient.GetPresignedURL(c, projectID, "files/"+file.FileName, time.Duration(24)*time.Hour)
+		presignedURL, err := services.DownloadURL(c, mcclient, projectID, "files/"+file.FileName, file.FileSize, time.Duration(24)*time.Hour)
 		if err != nil {
 			logger.LogError("Failed to generate pre-signed URL", logrus.Fields{"error": err.Error(), "file_name": file.FileName, "email": email})
 			continue // Skip this file, but proceed with others
@@ -339,7 +345,7 @@ func DeleteProjectFileByID(c *gin.Context) {
 		return
 	}
 
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
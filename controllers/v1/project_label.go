@@ -36,12 +36,12 @@ func CreateProjectLabel(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, role := isProjectMember(tx, projectID, email)
 	if !authorized || role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -99,12 +99,12 @@ func GetProjectLabelByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, role := isProjectMember(tx, projectID, email)
 	if !authorized || role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -166,13 +166,12 @@ func UpdateProjectLabelByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -250,13 +249,12 @@ func DeleteProjectLabelByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -316,12 +314,12 @@ func ListProjectLabels(c *gin.Context) {
 	projectID := c.Param("project_id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, role := isProjectMember(tx, projectID, email)
 	if !authorized || role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
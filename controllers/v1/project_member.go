@@ -9,12 +9,14 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"net/http"
 )
 
-// AddSingleProjectMembers adds a single member to a project.
+// AddSingleProjectMembers changes the role of a project member, or invites the user when they are not a member yet.
 func AddSingleProjectMembers(c *gin.Context) {
 	var req v1.ProjectMemberRequest
 
@@ -37,14 +39,13 @@ func AddSingleProjectMembers(c *gin.Context) {
 	}
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Check user authorization to add members
-	if !utils.IsUserAuthorizedToAddMember(tx, ProjectID, email) {
-		models.SendSuccessResponse(c, http.StatusForbidden, nil, "User is not authorized to add members.")
+	if !requirePermission(c, tx, pmv1.PermissionMemberManage) {
 		return
 	}
 
@@ -67,6 +68,33 @@ func AddSingleProjectMembers(c *gin.Context) {
 		}
 	}
 
+	// New members are invited and only join the project once they accept
+	if existingMember.ID == uuid.Nil {
+		project, ok := invitingProject(c, tx, ProjectID, email)
+		if !ok {
+			return
+		}
+		sent, ok := inviteMember(c, tx, ProjectID, email, projectMember.Email, projectMember.Role, services.InvitationExpiry(nil))
+		if !ok {
+			return
+		}
+		if !utils.CommitTransaction(tx, c, email) {
+			return
+		}
+		mailInvitations(c, project, email, sent)
+		models.SendSuccessResponse(c, http.StatusAccepted, invitationResponse(sent.invitation, ""), "Invitation sent successfully.")
+		return
+	}
+
+	if !authorizeRoleGrant(c, tx, projectMember.Role, email) {
+		return
+	}
+
+	// Only Owners may grant or take away the Owner role, and the last Owner must stay
+	if !guardOwnerChange(c, tx, ProjectID, email, existingMember, projectMember.Role) {
+		return
+	}
+
 	// If the member already exists with the same role, return a conflict response
 	if existingMember.ID != uuid.Nil && existingMember.Role == projectMember.Role {
 		tx.Rollback()
@@ -75,24 +103,15 @@ func AddSingleProjectMembers(c *gin.Context) {
 		return
 	}
 
-	// If the member exists with a different role, update the role
-	if existingMember.ID != uuid.Nil && existingMember.Role != projectMember.Role {
-		existingMember.Role = projectMember.Role
-		if err := tx.Save(&existingMember).Error; err != nil {
-			tx.Rollback()
-			logger.LogError("Failed to update project member role.", logrus.Fields{"error": err.Error(), "email": projectMember.Email})
-			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
-			return
-		}
-	}
-
-	// If no existing member, create a new project member
-	if existingMember.ID == uuid.Nil {
-		// Save the project to the database
-		if !utils.CreateWithRollback(tx, c, &projectMember, "Failed to create project memebr.", email) {
-			return
-		}
+	// The member exists with a different role, update the role
+	existingMember.Role = projectMember.Role
+	if err := tx.Save(&existingMember).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update project member role.", logrus.Fields{"error": err.Error(), "email": projectMember.Email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
 	}
+	projectMember = existingMember
 
 	// Commit transaction
 	if !utils.CommitTransaction(tx, c, email) {
@@ -131,15 +150,25 @@ func DeleteProjectMemberByID(c *gin.Context) {
 	memberID := c.Param("member_id")
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Check user authorization to delete members
-	if !utils.IsUserAuthorizedToAddMember(tx, ProjectID, email) {
-		// Send forbidden response
-		models.SendSuccessResponse(c, http.StatusForbidden, nil, "User is not authorized to delete member.")
+	if !requirePermission(c, tx, pmv1.PermissionMemberManage) {
+		return
+	}
+
+	// The last Owner cannot be removed
+	var member v1.ProjectMember
+	if err := tx.Where("id = ? AND project_id = ?", memberID, projectID).First(&member).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to fetch project member with ID: %s.", memberID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if !guardOwnerChange(c, tx, ProjectID, email, member, "") {
 		return
 	}
 
@@ -185,15 +214,25 @@ func DeleteProjectMemberByEmail(c *gin.Context) {
 	memberEmail := c.Param("email")
 
 	// Start database transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
 	// Check user authorization to delete members
-	if !utils.IsUserAuthorizedToAddMember(tx, ProjectID, email) {
-		// Send forbidden response
-		models.SendSuccessResponse(c, http.StatusForbidden, nil, "User is not authorized to delete member.")
+	if !requirePermission(c, tx, pmv1.PermissionMemberManage) {
+		return
+	}
+
+	// The last Owner cannot be removed
+	var member v1.ProjectMember
+	if err := tx.Where("email = ? AND project_id = ?", memberEmail, projectID).First(&member).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to fetch project member with email: %s.", memberEmail), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if !guardOwnerChange(c, tx, ProjectID, email, member, "") {
 		return
 	}
 
@@ -236,7 +275,7 @@ func GetProjectMemberByID(c *gin.Context) {
 	memberID := c.Param("member_id")
 
 	// Start a new transaction
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -293,7 +332,7 @@ func GetProjectMemberByEmail(c *gin.Context) {
 	memberEmail := c.Param("email")
 
 	// Start a transaction for the current operation
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -358,7 +397,7 @@ func GetProjectMembers(c *gin.Context) {
 	}
 
 	// Start a transaction for the current operation
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
@@ -415,7 +454,8 @@ func GetProjectMembers(c *gin.Context) {
 }
 
 // AddORRemoveProjectMembers handles batch operations (add/remove) for project members.
-// It processes the provided operations, updates the project members accordingly, and returns a success response.
+// It processes the provided operations, updating the role of existing members and inviting new ones, and
+// returns what was updated, removed and invited.
 func AddORRemoveProjectMembers(c *gin.Context) {
 	// Extract the email from the context
 	email, valid := utils.GetEmailFromContext(c)
@@ -439,11 +479,25 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 	fmt.Println(req)
 
 	// Start a transaction for the current operation
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
+	// Check user authorization to change members
+	if !requirePermission(c, tx, pmv1.PermissionMemberManage) {
+		return
+	}
+	requester := email
+
+	project, ok := invitingProject(c, tx, ProjectID, email)
+	if !ok {
+		return
+	}
+
+	var sent []sentInvitation
+	response := pmv1.ProjectMemberOperationsResponse{Updated: []string{}, Removed: []string{}, Invited: []pmv1.ProjectInvitationResponse{}}
+
 	// Iterate over the operations and apply them
 	for _, operation := range req.Operations {
 		// Validate operation type
@@ -466,19 +520,23 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 						models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
 						return
 					}
-					// Create a new member if not found
-					newMember := v1.ProjectMember{
-						Email:     email,
-						Role:      operation.Role,
-						ProjectID: ProjectID,
-					}
-					if err := tx.Create(&newMember).Error; err != nil {
-						tx.Rollback()
-						logger.LogError("Failed to add new project member.", logrus.Fields{"error": err.Error(), "email": email})
-						models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+					// Users who are not members yet are invited and join once they accept
+					invitation, ok := inviteMember(c, tx, ProjectID, requester, email, operation.Role, services.InvitationExpiry(nil))
+					if !ok {
 						return
 					}
+					sent = append(sent, invitation)
+					response.Invited = append(response.Invited, invitationResponse(invitation.invitation, ""))
 				} else {
+					if !authorizeRoleGrant(c, tx, operation.Role, requester) {
+						return
+					}
+
+					// Only Owners may grant or take away the Owner role, and the last Owner must stay
+					if !guardOwnerChange(c, tx, ProjectID, requester, existingMember, operation.Role) {
+						return
+					}
+
 					// If the member exists, update the role
 					existingMember.Role = operation.Role
 					if err := tx.Save(&existingMember).Error; err != nil {
@@ -487,6 +545,7 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 						models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
 						return
 					}
+					response.Updated = append(response.Updated, existingMember.Email)
 				}
 			}
 
@@ -505,6 +564,11 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 					return
 				}
 
+				// The last Owner cannot be removed
+				if !guardOwnerChange(c, tx, ProjectID, requester, projectMember, "") {
+					return
+				}
+
 				// Remove the member from the project
 				if err := tx.Delete(&projectMember).Error; err != nil {
 					tx.Rollback()
@@ -512,6 +576,7 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 					models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
 					return
 				}
+				response.Removed = append(response.Removed, projectMember.Email)
 			}
 		}
 	}
@@ -521,6 +586,8 @@ func AddORRemoveProjectMembers(c *gin.Context) {
 		return
 	}
 
+	mailInvitations(c, project, email, sent...)
+
 	// Send success response
-	models.SendSuccessResponse(c, http.StatusOK, nil, "Project members updated successfully.")
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project members updated successfully.")
 }
@@ -8,6 +8,8 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 	"net/http"
@@ -24,7 +26,7 @@ func CheckProjectSlugExists(c *gin.Context) {
 
 	var project v1.Project
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
@@ -52,12 +54,14 @@ func GetProjectBySlug(c *gin.Context) {
 	}
 	var project v1.Project
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	if err := tx.Where("slug = ? AND deleted_at IS NULL", slug).First(&project).Error; err != nil {
+	if err := tx.Where("slug = ? AND deleted_at IS NULL", slug).
+		Where("id IN (?)", services.OrganizationProjects(tx, pmmiddlewares.OrganizationID(c))).
+		First(&project).Error; err != nil {
 		tx.Rollback()
 		logger.LogError(fmt.Sprintf("Project with slug: %s not found.", slug), logrus.Fields{"error": err.Error(), "email": email})
 		if err == gorm.ErrRecordNotFound {
@@ -67,7 +71,7 @@ func GetProjectBySlug(c *gin.Context) {
 		}
 		return
 	}
-	authorized, role := utils.IsUserPartOfRole(tx, project.ID.String(), email)
+	authorized, role := isProjectMember(tx, project.ID.String(), email)
 	if !authorized && role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -11,8 +11,11 @@ import (
 	"github.com/san-data-systems/common/models"
 	v1 "github.com/san-data-systems/common/models/v1"
 	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // CreateProjectState handles the creation of a new project state.
@@ -35,13 +38,12 @@ func CreateProjectState(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -113,12 +115,12 @@ func GetProjectStateByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, role := isProjectMember(tx, projectID, email)
 	if !authorized || role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -182,13 +184,12 @@ func UpdateProjectStateByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -248,9 +249,14 @@ func UpdateProjectStateByID(c *gin.Context) {
 }
 
 // DeleteProjectStateByID handles the deletion of a project state by its ID for a specific project.
+// Issues still in the state are moved to the state given by the move_to_state_id query parameter;
+// without it, deleting a state that is in use fails with a conflict. With dry_run=true the affected
+// issues are listed and nothing is changed.
 func DeleteProjectStateByID(c *gin.Context) {
 	projectID := c.Param("project_id")
 	stateID := c.Param("state_id")
+	moveToStateID := c.Query("move_to_state_id")
+	dryRun := c.Query("dry_run") == "true"
 
 	email, valid := utils.GetEmailFromContext(c)
 	if !valid {
@@ -269,13 +275,12 @@ func DeleteProjectStateByID(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
@@ -294,22 +299,98 @@ func DeleteProjectStateByID(c *gin.Context) {
 		return
 	}
 
-	// Check if the state ID is used in the Issue table
-	var issueCount int64
-	if err := tx.Debug().Model(&v1.Issue{}).Where("state_id = ? AND deleted_at IS NULL", parsedStateID).Count(&issueCount).Error; err != nil {
+	// Lock the issues referencing the state so that none is added while it is being deleted
+	var affectedIssues []v1.Issue
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("state_id = ? AND deleted_at IS NULL", parsedStateID).
+		Order("sequence_id ASC").
+		Find(&affectedIssues).Error; err != nil {
 		tx.Rollback()
 		logger.LogError("Failed to check issues for the state.", logrus.Fields{"error": err.Error(), "email": email})
 		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
 		return
 	}
 
-	if issueCount > 0 {
+	// Validate the state the affected issues are moved to
+	var moveToState *v1.ProjectState
+	if moveToStateID != "" {
+		var target v1.ProjectState
+		if err := tx.Where("id = ? AND project_id = ? AND deleted_at IS NULL", moveToStateID, parsedProjectID).First(&target).Error; err != nil || target.ID == parsedStateID {
+			tx.Rollback()
+			logger.LogError(fmt.Sprintf("Target state with ID: %s not found for project ID: %s.", moveToStateID, projectID), logrus.Fields{"email": email})
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		moveToState = &target
+	}
+
+	response := pmv1.StateDeletionResponse{
+		StateID:        projectState.ID.String(),
+		DryRun:         dryRun,
+		AffectedIssues: []pmv1.BoardIssue{},
+	}
+	if moveToState != nil {
+		target := moveToState.ID.String()
+		response.MoveToStateID = &target
+	}
+	for _, issue := range affectedIssues {
+		response.AffectedIssues = append(response.AffectedIssues, pmv1.BoardIssue{
+			ID:          issue.ID.String(),
+			SequenceID:  issue.SequenceID,
+			Title:       issue.Title,
+			Priority:    issue.Priority,
+			Point:       issue.Point,
+			EndDate:     issue.EndDate,
+			CompletedAt: issue.CompletedAt,
+		})
+	}
+
+	// Moving the affected issues must respect the work-in-progress limit of the target state
+	if moveToState != nil {
+		incoming := 0
+		for _, issue := range affectedIssues {
+			if !issue.IsDraft {
+				incoming++
+			}
+		}
+		warning, err := services.CheckWIPLimit(tx, moveToState.ID, incoming)
+		limitErr, hard := err.(*services.WIPLimitError)
+		switch {
+		case err != nil && (!hard || !dryRun):
+			sendWIPLimitError(c, tx, err, email)
+			return
+		case hard:
+			response.WIPLimitViolation = limitErr.Error()
+			response.WIPLimitHard = true
+		default:
+			response.WIPLimitViolation = warning
+		}
+	}
+
+	// A dry run only reports the issues that would be affected and the limit they would break
+	if dryRun {
 		tx.Rollback()
-		logger.LogWarning(fmt.Sprintf("State with ID: %s cannot be deleted as it is referenced in %d issues.", stateID, issueCount), logrus.Fields{"email": email})
-		models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+		models.SendSuccessResponse(c, http.StatusOK, response, "Project state deletion preview retrieved successfully.")
 		return
 	}
 
+	if len(affectedIssues) > 0 {
+		if moveToState == nil {
+			tx.Rollback()
+			logger.LogWarning(fmt.Sprintf("State with ID: %s cannot be deleted as it is referenced in %d issues.", stateID, len(affectedIssues)), logrus.Fields{"email": email})
+			models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+			return
+		}
+
+		// Move the affected issues to the target state, recording an activity for each
+		if err := services.MigrateIssuesToState(tx, affectedIssues, moveToState.ID, email); err != nil {
+			tx.Rollback()
+			logger.LogError(fmt.Sprintf("Failed to move issues from state %s to state %s.", stateID, moveToStateID), logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+	}
+
 	// Soft delete the project state
 	now := time.Now()
 	projectState.DeletedAt = &now
@@ -348,6 +429,10 @@ func DeleteProjectStateByID(c *gin.Context) {
 	}
 
 	// Send success response
+	if moveToState != nil {
+		models.SendSuccessResponse(c, http.StatusOK, response, withWarnings(c, "Project state deleted successfully, issues moved and sequence reset", response.WIPLimitViolation))
+		return
+	}
 	models.SendSuccessResponse(c, http.StatusNoContent, nil, "Project state deleted successfully and sequence reset")
 }
 
@@ -373,12 +458,12 @@ func ListProjectStates(c *gin.Context) {
 	projectID := c.Param("project_id")
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
+	authorized, role := isProjectMember(tx, projectID, email)
 	if !authorized || role == nil {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
@@ -453,13 +538,12 @@ func UpdateProjectStatesSequence(c *gin.Context) {
 	}
 
 	// Start a transaction using the helper
-	tx, ok := utils.StartTransaction(c, email)
+	tx, ok := startTransaction(c, email)
 	if !ok {
 		return // Early return if the transaction failed to start
 	}
 
-	authorized, role := utils.IsUserPartOfRole(tx, projectID, email)
-	if !authorized || (*role != "Manager" && *role != "Owner") {
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
 		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
 		return
 	}
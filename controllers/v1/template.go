@@ -0,0 +1,597 @@
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/metrics"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListSavedViews retrieves the saved views of a project.
+func ListSavedViews(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var views []pmv1.SavedView
+	if err := tx.Where("project_id = ?", parsedProjectID).Order("name ASC").Find(&views).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch saved views.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := make([]pmv1.SavedViewResponse, 0, len(views))
+	for _, view := range views {
+		response = append(response, savedViewResponse(view))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Saved views retrieved successfully.")
+}
+
+// CreateSavedView saves a named set of issue filters on a project.
+func CreateSavedView(c *gin.Context) {
+	var req pmv1.SavedViewRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	var filters map[string]interface{}
+	if err := json.Unmarshal(req.Filters, &filters); err != nil {
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Filters must be a JSON object.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	view := pmv1.SavedView{
+		ProjectID: parsedProjectID,
+		Name:      req.Name,
+		Filters:   string(req.Filters),
+		CreatedBy: email,
+	}
+	if !utils.CreateWithRollback(tx, c, &view, "Failed to create saved view.", email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, savedViewResponse(view), "Saved view created successfully.")
+}
+
+// DeleteSavedView deletes a saved view. Only its creator or a project Manager or Owner may delete it.
+func DeleteSavedView(c *gin.Context) {
+	projectID := c.Param("project_id")
+	viewID := c.Param("view_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedViewID, err := utils.ConvertID(viewID, c, email, "view id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var view pmv1.SavedView
+	if err := tx.Where("id = ? AND project_id = ?", parsedViewID, parsedProjectID).First(&view).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch saved view with ID: %s.", viewID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	// Saved views can be deleted by their author or by users who may edit the project
+	if view.CreatedBy != email && !requirePermission(c, tx, pmv1.PermissionProjectEdit) {
+		return
+	}
+
+	if err := tx.Delete(&view).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete saved view with ID: %s.", viewID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Saved view deleted successfully.")
+}
+
+// CreateProjectTemplate creates a project template from a definition.
+func CreateProjectTemplate(c *gin.Context) {
+	var req pmv1.ProjectTemplateRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	definition, err := services.EncodeTemplateDefinition(req.Definition)
+	if err != nil {
+		logger.LogError("Failed to encode template definition.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Template definition is not valid.")
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	template := pmv1.ProjectTemplate{
+		OrganizationID: pmmiddlewares.OrganizationID(c),
+		Name:           req.Name,
+		Description:    req.Description,
+		Definition:     definition,
+		CreatedBy:      email,
+	}
+	if !utils.CreateWithRollback(tx, c, &template, "Failed to create project template.", email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, projectTemplateResponse(template, req.Definition), "Project template created successfully.")
+}
+
+// CaptureProjectTemplate creates a project template from the structure of an existing project.
+func CaptureProjectTemplate(c *gin.Context) {
+	var req pmv1.CaptureTemplateRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	definition, err := services.CaptureProjectDefinition(tx, parsedProjectID, req.IncludeOpenIssues)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to capture project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	encoded, err := services.EncodeTemplateDefinition(definition)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to encode template definition.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	template := pmv1.ProjectTemplate{
+		OrganizationID:  pmmiddlewares.OrganizationID(c),
+		Name:            req.Name,
+		Description:     req.Description,
+		SourceProjectID: &parsedProjectID,
+		Definition:      encoded,
+		CreatedBy:       email,
+	}
+	if !utils.CreateWithRollback(tx, c, &template, "Failed to create project template.", email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, projectTemplateResponse(template, definition), "Project template created successfully.")
+}
+
+// ListProjectTemplates retrieves the available project templates.
+func ListProjectTemplates(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var templates []pmv1.ProjectTemplate
+	if err := tx.Where("organization_id = ? AND deleted_at IS NULL", pmmiddlewares.OrganizationID(c)).Order("name ASC").Find(&templates).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project templates.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := make([]pmv1.ProjectTemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		definition, err := services.DecodeTemplateDefinition(template)
+		if err != nil {
+			logger.LogError(fmt.Sprintf("Project template with ID: %s has an invalid definition.", template.ID), logrus.Fields{"error": err.Error(), "email": email})
+			continue
+		}
+		response = append(response, projectTemplateResponse(template, definition))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project templates retrieved successfully.")
+}
+
+// GetProjectTemplate retrieves a project template by ID.
+func GetProjectTemplate(c *gin.Context) {
+	templateID := c.Param("template_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedTemplateID, err := utils.ConvertID(templateID, c, email, "template id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var template pmv1.ProjectTemplate
+	if err := tx.Where("id = ? AND organization_id = ? AND deleted_at IS NULL", parsedTemplateID, pmmiddlewares.OrganizationID(c)).First(&template).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project template with ID: %s.", templateID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	definition, err := services.DecodeTemplateDefinition(template)
+	if err != nil {
+		logger.LogError(fmt.Sprintf("Project template with ID: %s has an invalid definition.", templateID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, projectTemplateResponse(template, definition), "Project template retrieved successfully.")
+}
+
+// DeleteProjectTemplate soft deletes a project template. Only its creator may delete it.
+func DeleteProjectTemplate(c *gin.Context) {
+	templateID := c.Param("template_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedTemplateID, err := utils.ConvertID(templateID, c, email, "template id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	result := tx.Model(&pmv1.ProjectTemplate{}).
+		Where("id = ? AND organization_id = ? AND created_by = ? AND deleted_at IS NULL", parsedTemplateID, pmmiddlewares.OrganizationID(c), email).
+		Update("deleted_at", time.Now())
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete project template with ID: %s.", templateID), logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Project template deleted successfully.")
+}
+
+// CloneProject duplicates the structure of a project, and optionally its members and open issues, under a new slug.
+func CloneProject(c *gin.Context) {
+	var req pmv1.CloneProjectRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	layout := "2006-01-02"
+	var startDate, endDate time.Time
+	if req.StartDate != "" {
+		if startDate, err = time.Parse(layout, req.StartDate); err != nil {
+			models.SendErrorResponse(c, http.StatusUnprocessableEntity, "Start date is not in correct format.")
+			return
+		}
+	}
+	if req.EndDate != "" {
+		if endDate, err = time.Parse(layout, req.EndDate); err != nil {
+			models.SendErrorResponse(c, http.StatusUnprocessableEntity, "End date is not in correct format.")
+			return
+		}
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var source v1.Project
+	if err := tx.Where("id = ? AND deleted_at IS NULL", parsedProjectID).First(&source).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var existing int64
+	if err := tx.Model(&v1.Project{}).Where("slug = ?", req.Slug).Count(&existing).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to check project slug.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if existing > 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusConflict, errors.ErrConflict)
+		return
+	}
+
+	definition, err := services.CaptureProjectDefinition(tx, source.ID, req.IncludeOpenIssues)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to capture project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if !req.IncludeMembers {
+		definition.Members = nil
+	}
+
+	if startDate.IsZero() {
+		startDate = source.StartDate
+	}
+	if endDate.IsZero() {
+		endDate = startDate.Add(source.EndDate.Sub(source.StartDate))
+	}
+
+	project := v1.Project{
+		Name:        req.Name,
+		Slug:        req.Slug,
+		Description: source.Description,
+		ClientID:    source.ClientID,
+		StartDate:   startDate,
+		EndDate:     endDate,
+		Status:      source.Status,
+		Tags:        source.Tags,
+		CreatedBy:   email,
+	}
+	if !utils.CreateWithRollback(tx, c, &project, "Failed to create project.", email) {
+		return
+	}
+
+	if err := services.AssignProject(tx, pmmiddlewares.OrganizationID(c), project.ID); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to assign project to organization.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.SetProjectRole(tx, project.ID, email, pmv1.RoleOwner); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to add project owner.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.ApplyProjectDefinition(tx, project, definition, email); err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to clone project with ID: %s.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var projectr v1.Project
+	if err := tx.Preload("Client").Where("id = ?", project.ID).First(&projectr).Error; err != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", project.ID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	metrics.IssuesCreated(len(definition.Issues))
+
+	response := v1.ProjectResponse{
+		ID:          projectr.ID,
+		Name:        projectr.Name,
+		Slug:        projectr.Slug,
+		Description: projectr.Description,
+		Client:      projectr.Client,
+		StartDate:   projectr.StartDate,
+		EndDate:     projectr.EndDate,
+		Status:      projectr.Status,
+		Tags:        projectr.Tags,
+		CreatedAt:   projectr.CreatedAt,
+		UpdatedAt:   projectr.UpdatedAt,
+		CreatedBy:   projectr.CreatedBy,
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, response, "Project cloned successfully.")
+}
+
+// savedViewResponse converts a saved view to its API representation.
+func savedViewResponse(view pmv1.SavedView) pmv1.SavedViewResponse {
+	return pmv1.SavedViewResponse{
+		ID:        view.ID.String(),
+		ProjectID: view.ProjectID.String(),
+		Name:      view.Name,
+		Filters:   json.RawMessage(view.Filters),
+		CreatedBy: view.CreatedBy,
+		CreatedAt: view.CreatedAt,
+	}
+}
+
+// projectTemplateResponse converts a project template and its decoded definition to its API representation.
+func projectTemplateResponse(template pmv1.ProjectTemplate, definition pmv1.TemplateDefinition) pmv1.ProjectTemplateResponse {
+	response := pmv1.ProjectTemplateResponse{
+		ID:          template.ID.String(),
+		Name:        template.Name,
+		Description: template.Description,
+		Definition:  definition,
+		CreatedBy:   template.CreatedBy,
+		CreatedAt:   template.CreatedAt,
+	}
+	if template.SourceProjectID != nil {
+		sourceProjectID := template.SourceProjectID.String()
+		response.SourceProjectID = &sourceProjectID
+	}
+	return response
+}
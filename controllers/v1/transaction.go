@@ -0,0 +1,17 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/utils"
+	"gorm.io/gorm"
+)
+
+// startTransaction starts the transaction of a request like utils.StartTransaction, bound to the
+// request's context so that its queries are traced as part of the request and stop when it is cancelled.
+func startTransaction(c *gin.Context, email string) (*gorm.DB, bool) {
+	tx, ok := utils.StartTransaction(c, email)
+	if !ok {
+		return tx, false
+	}
+	return tx.WithContext(c.Request.Context()), true
+}
@@ -0,0 +1,549 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// ListProjectTrash retrieves the trashed issues, states and labels of a project.
+func ListProjectTrash(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var issues []v1.Issue
+	if err := tx.Where("project_id = ? AND deleted_at IS NOT NULL", parsedProjectID).Order("deleted_at DESC").Find(&issues).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch trashed issues.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var states []v1.ProjectState
+	if err := tx.Where("project_id = ? AND deleted_at IS NOT NULL", parsedProjectID).Order("deleted_at DESC").Find(&states).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch trashed states.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var labels []v1.ProjectLabel
+	if err := tx.Where("project_id = ? AND deleted_at IS NOT NULL", parsedProjectID).Order("deleted_at DESC").Find(&labels).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch trashed labels.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.ProjectTrashResponse{
+		ProjectID: parsedProjectID.String(),
+		Issues:    make([]pmv1.TrashItem, 0, len(issues)),
+		States:    make([]pmv1.TrashItem, 0, len(states)),
+		Labels:    make([]pmv1.TrashItem, 0, len(labels)),
+	}
+	for _, issue := range issues {
+		item := trashItem(issue.ID.String(), pmv1.TrashKindIssue, issue.Title, *issue.DeletedAt)
+		if issue.ParentID != uuid.Nil {
+			parentID := issue.ParentID.String()
+			item.ParentID = &parentID
+		}
+		response.Issues = append(response.Issues, item)
+	}
+	for _, state := range states {
+		response.States = append(response.States, trashItem(state.ID.String(), pmv1.TrashKindState, state.Name, *state.DeletedAt))
+	}
+	for _, label := range labels {
+		response.Labels = append(response.Labels, trashItem(label.ID.String(), pmv1.TrashKindLabel, label.Name, *label.DeletedAt))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project trash retrieved successfully.")
+}
+
+// RestoreProjectTrashItem restores a trashed issue, state or label of a project. Restoring an issue also
+// restores the sub-issues deleted with it.
+func RestoreProjectTrashItem(c *gin.Context) {
+	projectID := c.Param("project_id")
+	kind := c.Param("kind")
+	itemID := c.Param("item_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedItemID, err := utils.ConvertID(itemID, c, email, "item id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	// Restoring an item requires the permission needed to delete it
+	response := pmv1.RestoreResponse{ID: parsedItemID.String(), Kind: kind}
+	switch kind {
+	case pmv1.TrashKindIssue:
+		if !requirePermission(c, tx, pmv1.PermissionIssueDelete) {
+			return
+		}
+		var issue v1.Issue
+		if !findTrashed(c, tx, &issue, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		response.Dependents, err = services.RestoreIssue(tx, issue)
+	case pmv1.TrashKindState:
+		if !requirePermission(c, tx, pmv1.PermissionStateManage) {
+			return
+		}
+		var state v1.ProjectState
+		if !findTrashed(c, tx, &state, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		err = services.RestoreState(tx, state)
+	case pmv1.TrashKindLabel:
+		if !requirePermission(c, tx, pmv1.PermissionLabelManage) {
+			return
+		}
+		var label v1.ProjectLabel
+		if !findTrashed(c, tx, &label, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		err = services.RestoreLabel(tx, label)
+	default:
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+	if err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to restore %s with ID: %s.", kind, itemID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Item restored successfully.")
+}
+
+// PurgeProjectTrashItem permanently deletes a trashed issue, state or label of a project. Only project Owners may purge.
+func PurgeProjectTrashItem(c *gin.Context) {
+	projectID := c.Param("project_id")
+	kind := c.Param("kind")
+	itemID := c.Param("item_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedItemID, err := utils.ConvertID(itemID, c, email, "item id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var project v1.Project
+	if err := tx.Where("id = ?", parsedProjectID).First(&project).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Project with ID: %s not found.", projectID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return
+	}
+
+	var objects []services.StoredObject
+	switch kind {
+	case pmv1.TrashKindIssue:
+		var issue v1.Issue
+		if !findTrashed(c, tx, &issue, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		objects, err = services.PurgeIssue(tx, issue)
+	case pmv1.TrashKindState:
+		var state v1.ProjectState
+		if !findTrashed(c, tx, &state, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		err = services.PurgeState(tx, state)
+	case pmv1.TrashKindLabel:
+		var label v1.ProjectLabel
+		if !findTrashed(c, tx, &label, parsedItemID, parsedProjectID, email) {
+			return
+		}
+		err = services.PurgeLabel(tx, label)
+	default:
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+	if err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to purge %s with ID: %s.", kind, itemID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+	services.RemoveStoredObjects(c, objects)
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Item purged successfully.")
+}
+
+// ListTrash retrieves the trashed projects the user administers and the trashed clients the user created.
+func ListTrash(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	administered, err := services.ProjectsWithPermission(tx, pmmiddlewares.OrganizationID(c), email, pmv1.PermissionProjectAdmin)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project roles.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var projects []v1.Project
+	if err := tx.Where("deleted_at IS NOT NULL").
+		Where("id IN (?)", administered).
+		Order("deleted_at DESC").
+		Find(&projects).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch trashed projects.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var clients []v1.Client
+	if err := tx.Where("deleted_at IS NOT NULL AND created_by = ?", email).
+		Where("id IN (?)", services.OrganizationClients(tx, pmmiddlewares.OrganizationID(c))).
+		Order("deleted_at DESC").
+		Find(&clients).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch trashed clients.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.TrashResponse{
+		Projects: make([]pmv1.TrashItem, 0, len(projects)),
+		Clients:  make([]pmv1.TrashItem, 0, len(clients)),
+	}
+	for _, project := range projects {
+		response.Projects = append(response.Projects, trashItem(project.ID.String(), pmv1.TrashKindProject, project.Name, *project.DeletedAt))
+	}
+	for _, client := range clients {
+		response.Clients = append(response.Clients, trashItem(client.ID.String(), pmv1.TrashKindClient, client.Name, *client.DeletedAt))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Trash retrieved successfully.")
+}
+
+// RestoreTrashedProject restores a trashed project with the issues, states and labels deleted with it.
+func RestoreTrashedProject(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var project v1.Project
+	if !findTrashed(c, tx, &project, parsedProjectID, nil, email) {
+		return
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return
+	}
+
+	dependents, err := services.RestoreProject(tx, project)
+	if err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to restore project with ID: %s.", projectID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.RestoreResponse{
+		ID:         project.ID.String(),
+		Kind:       pmv1.TrashKindProject,
+		Dependents: dependents,
+	}, "Project restored successfully.")
+}
+
+// PurgeTrashedProject permanently deletes a trashed project and everything it owns.
+func PurgeTrashedProject(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var project v1.Project
+	if !findTrashed(c, tx, &project, parsedProjectID, nil, email) {
+		return
+	}
+
+	if !requirePermission(c, tx, pmv1.PermissionProjectAdmin) {
+		return
+	}
+
+	objects, err := services.PurgeProject(tx, project)
+	if err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to purge project with ID: %s.", projectID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+	services.RemoveStoredObjects(c, objects)
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Project purged successfully.")
+}
+
+// RestoreTrashedClient restores a trashed client. Only its creator may restore it.
+func RestoreTrashedClient(c *gin.Context) {
+	clientID := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedClientID, err := utils.ConvertID(clientID, c, email, "client id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if !findTrashed(c, tx, &client, parsedClientID, nil, email) {
+		return
+	}
+
+	if !authorizeOrganizationClient(c, tx, client.ID, email) {
+		return
+	}
+
+	if client.CreatedBy != email {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if err := services.RestoreClient(tx, client); err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to restore client with ID: %s.", clientID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, pmv1.RestoreResponse{ID: client.ID.String(), Kind: pmv1.TrashKindClient}, "Client restored successfully.")
+}
+
+// PurgeTrashedClient permanently deletes a trashed client. Only its creator may purge it.
+func PurgeTrashedClient(c *gin.Context) {
+	clientID := c.Param("id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedClientID, err := utils.ConvertID(clientID, c, email, "client id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var client v1.Client
+	if !findTrashed(c, tx, &client, parsedClientID, nil, email) {
+		return
+	}
+
+	if !authorizeOrganizationClient(c, tx, client.ID, email) {
+		return
+	}
+
+	if client.CreatedBy != email {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if err := services.PurgeClient(tx, client); err != nil {
+		sendTrashError(c, tx, err, fmt.Sprintf("Failed to purge client with ID: %s.", clientID), email)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "Client purged successfully.")
+}
+
+// findTrashed loads a trashed row by ID, scoped to a project when projectID is not nil.
+// It rolls back and responds when the row is not in the trash.
+func findTrashed(c *gin.Context, tx *gorm.DB, dest interface{}, id, projectID interface{}, email string) bool {
+	query := tx.Where("id = ? AND deleted_at IS NOT NULL", id)
+	if projectID != nil {
+		query = query.Where("project_id = ?", projectID)
+	}
+	if err := query.First(dest).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return false
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch trashed item with ID: %v.", id), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+	return true
+}
+
+// sendTrashError rolls back and responds to a failed restore or purge.
+func sendTrashError(c *gin.Context, tx *gorm.DB, err error, message, email string) {
+	tx.Rollback()
+	if conflict, ok := err.(*services.TrashConflictError); ok {
+		models.SendErrorResponse(c, http.StatusConflict, conflict.Error())
+		return
+	}
+	logger.LogError(message, logrus.Fields{"error": err.Error(), "email": email})
+	models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+}
+
+// trashItem builds a trash listing entry, computing when the retention job will purge it.
+func trashItem(id, kind, name string, deletedAt time.Time) pmv1.TrashItem {
+	return pmv1.TrashItem{
+		ID:        id,
+		Kind:      kind,
+		Name:      name,
+		DeletedAt: deletedAt,
+		PurgeAt:   purgeAt(deletedAt),
+	}
+}
+
+// purgeAt returns when the retention job will purge a row trashed at the given time, nil when the job
+// is disabled.
+func purgeAt(deletedAt time.Time) *time.Time {
+	days := services.TrashRetentionDays()
+	if days == 0 {
+		return nil
+	}
+	at := deletedAt.AddDate(0, 0, days)
+	return &at
+}
@@ -0,0 +1,464 @@
+package v1
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetProjectWorkflow lists the states of a project with their categories, together with its transition rules.
+func GetProjectWorkflow(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var states []v1.ProjectState
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL", parsedProjectID).Order("sequence ASC").Find(&states).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project states.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	categories, err := services.LoadStateCategories(tx, parsedProjectID)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch state categories.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var transitions []pmv1.StateTransition
+	if err := tx.Where("project_id = ?", parsedProjectID).Order("created_at ASC").Find(&transitions).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch state transitions.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.WorkflowResponse{
+		States:      []pmv1.StateCategoryResponse{},
+		Transitions: []pmv1.StateTransitionResponse{},
+	}
+	for _, state := range states {
+		category, ok := categories[state.ID]
+		if !ok {
+			category = pmv1.DefaultStateCategory
+		}
+		response.States = append(response.States, pmv1.StateCategoryResponse{
+			StateID:  state.ID.String(),
+			Name:     state.Name,
+			Sequence: state.Sequence,
+			Category: category,
+		})
+	}
+	for _, transition := range transitions {
+		response.Transitions = append(response.Transitions, stateTransitionResponse(transition))
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Project workflow retrieved successfully.")
+}
+
+// UpdateProjectStateCategory sets the category of a project state and updates the CompletedAt of its issues.
+func UpdateProjectStateCategory(c *gin.Context) {
+	var req pmv1.StateCategoryRequest
+	projectID := c.Param("project_id")
+	stateID := c.Param("state_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedStateID, err := utils.ConvertID(stateID, c, email, "state id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var state v1.ProjectState
+	if err := tx.Where("id = ? AND project_id = ? AND deleted_at IS NULL", parsedStateID, parsedProjectID).First(&state).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project state with ID: %s.", stateID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	category := pmv1.ProjectStateCategory{StateID: state.ID, ProjectID: state.ProjectID}
+	if err := tx.Where("state_id = ?", state.ID).First(&category).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch state category.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	category.Category = req.Category
+	category.UpdatedBy = email
+
+	if err := tx.Save(&category).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update state category.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if err := services.SyncCompletedAt(tx, state.ID, req.Category); err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update completion of issues.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.StateCategoryResponse{
+		StateID:  state.ID.String(),
+		Name:     state.Name,
+		Sequence: state.Sequence,
+		Category: category.Category,
+	}
+	models.SendSuccessResponse(c, http.StatusOK, response, "State category updated successfully.")
+}
+
+// CreateStateTransition adds a transition rule to the workflow of a project.
+func CreateStateTransition(c *gin.Context) {
+	var req pmv1.StateTransitionRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	stateIDs := []string{req.ToStateID}
+	if req.FromStateID != "" {
+		stateIDs = append(stateIDs, req.FromStateID)
+	}
+
+	var count int64
+	if err := tx.Model(&v1.ProjectState{}).
+		Where("id IN ? AND project_id = ? AND deleted_at IS NULL", stateIDs, parsedProjectID).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project states.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if count != int64(len(stateIDs)) || req.FromStateID == req.ToStateID {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	transition := pmv1.StateTransition{
+		ProjectID: parsedProjectID,
+		ToStateID: uuid.MustParse(req.ToStateID),
+		Roles:     pq.StringArray(req.Roles),
+		CreatedBy: email,
+	}
+	if req.FromStateID != "" {
+		fromStateID := uuid.MustParse(req.FromStateID)
+		transition.FromStateID = &fromStateID
+	}
+
+	if !utils.CreateWithRollback(tx, c, &transition, "Failed to create state transition", email) {
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusCreated, stateTransitionResponse(transition), "State transition created successfully.")
+}
+
+// DeleteStateTransition removes a transition rule from the workflow of a project.
+func DeleteStateTransition(c *gin.Context) {
+	projectID := c.Param("project_id")
+	transitionID := c.Param("transition_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	parsedTransitionID, err := utils.ConvertID(transitionID, c, email, "transition id")
+	if err != nil {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	result := tx.Where("id = ? AND project_id = ?", parsedTransitionID, parsedProjectID).Delete(&pmv1.StateTransition{})
+	if result.Error != nil {
+		tx.Rollback()
+		logger.LogError(fmt.Sprintf("Failed to delete state transition with ID: %s.", transitionID), logrus.Fields{"error": result.Error.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, nil, "State transition deleted successfully.")
+}
+
+// BulkMoveIssues moves several issues of a project to one state.
+// Every move must be allowed by the workflow of the project; otherwise no issue is moved.
+func BulkMoveIssues(c *gin.Context) {
+	var req pmv1.BulkMoveIssuesRequest
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, parsedProjectID.String(), email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	var state v1.ProjectState
+	if err := tx.Where("id = ? AND project_id = ? AND deleted_at IS NULL", req.StateID, parsedProjectID).First(&state).Error; err != nil {
+		tx.Rollback()
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			return
+		}
+		logger.LogError(fmt.Sprintf("Failed to fetch project state with ID: %s.", req.StateID), logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	var issues []v1.Issue
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ? AND project_id = ? AND deleted_at IS NULL", req.IssueIDs, parsedProjectID).
+		Find(&issues).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch issues.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+	if len(issues) != len(uniqueStrings(req.IssueIDs)) {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	incoming := 0
+	for _, issue := range issues {
+		if issue.StateID != state.ID {
+			incoming++
+		}
+	}
+
+	// Enforce the work-in-progress limit of the target state for the whole batch
+	wipWarning, err := services.CheckWIPLimit(tx, state.ID, incoming)
+	if err != nil {
+		sendWIPLimitError(c, tx, err, email)
+		return
+	}
+
+	role := memberRole(tx, projectID, email)
+	response := pmv1.BulkMoveIssuesResponse{StateID: state.ID.String(), Moved: []string{}}
+	if wipWarning != "" {
+		response.Warnings = []string{wipWarning}
+	}
+	for i := range issues {
+		issue := &issues[i]
+		if issue.StateID == state.ID {
+			continue
+		}
+
+		if err := services.MoveIssueToState(tx, issue, state.ID, email, role); err != nil {
+			sendTransitionError(c, tx, err, email)
+			return
+		}
+		issue.UpdatedBy = email
+
+		if err := tx.Save(issue).Error; err != nil {
+			tx.Rollback()
+			logger.LogError(fmt.Sprintf("Failed to move issue with ID: %s.", issue.ID), logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		response.Moved = append(response.Moved, issue.ID.String())
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, withWarnings(c, "Issues moved successfully.", wipWarning))
+}
+
+// memberRole returns the role of the user in a project, or an empty string when they have none.
+func memberRole(tx *gorm.DB, projectID, email string) string {
+	if authorized, role := isProjectMember(tx, projectID, email); authorized && role != nil {
+		return *role
+	}
+	return ""
+}
+
+// sendTransitionError rolls back and responds to a refused or failed state transition.
+func sendTransitionError(c *gin.Context, tx *gorm.DB, err error, email string) {
+	tx.Rollback()
+	switch err {
+	case services.ErrTransitionNotAllowed:
+		models.SendErrorResponse(c, http.StatusUnprocessableEntity, "The workflow of this project does not allow this state transition.")
+	case services.ErrTransitionForbidden:
+		models.SendErrorResponse(c, http.StatusForbidden, "Your role is not allowed to make this state transition.")
+	default:
+		logger.LogError("Failed to move issue to state.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+	}
+}
+
+// stateTransitionResponse converts a transition rule to its API representation.
+func stateTransitionResponse(transition pmv1.StateTransition) pmv1.StateTransitionResponse {
+	response := pmv1.StateTransitionResponse{
+		ID:        transition.ID.String(),
+		ToStateID: transition.ToStateID.String(),
+		Roles:     []string(transition.Roles),
+		CreatedBy: transition.CreatedBy,
+		CreatedAt: transition.CreatedAt,
+	}
+	if response.Roles == nil {
+		response.Roles = []string{}
+	}
+	if transition.FromStateID != nil {
+		fromStateID := transition.FromStateID.String()
+		response.FromStateID = &fromStateID
+	}
+	return response
+}
+
+// uniqueStrings returns values without duplicates, keeping their order.
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
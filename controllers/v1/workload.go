@@ -0,0 +1,317 @@
+package v1
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/common/utils"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// defaultWorkloadDays is the length of the workload range when no end date is given.
+const defaultWorkloadDays = 14
+
+// GetProjectWorkload reports the workload of the members of a project against their capacity.
+// The optional from and to query parameters (YYYY-MM-DD) select the date range and email restricts the report to one member.
+func GetProjectWorkload(c *gin.Context) {
+	projectID := c.Param("project_id")
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	parsedProjectID, err := utils.ConvertID(projectID, c, email, "project id")
+	if err != nil {
+		return
+	}
+
+	from, to, ok := parseWorkloadRange(c, email)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if authorized, _ := isProjectMember(tx, projectID, email); !authorized {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return
+	}
+
+	projectIDs := []uuid.UUID{parsedProjectID}
+	members, err := services.ProjectMemberEmails(tx, projectIDs)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project members.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	workload, err := services.ComputeWorkload(tx, pmmiddlewares.OrganizationID(c), projectIDs, filterWorkloadMembers(members, c.Query("email")), from, to, time.Now())
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to compute project workload.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, workload, "Project workload retrieved successfully.")
+}
+
+// GetWorkload reports the workload of the members of every project the current user manages, across those projects.
+// It accepts the same query parameters as GetProjectWorkload.
+func GetWorkload(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	from, to, ok := parseWorkloadRange(c, email)
+	if !ok {
+		return
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	projectIDs, err := managedProjectIDs(tx, pmmiddlewares.OrganizationID(c), email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch managed projects.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	members, err := services.ProjectMemberEmails(tx, projectIDs)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project members.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	workload, err := services.ComputeWorkload(tx, pmmiddlewares.OrganizationID(c), projectIDs, filterWorkloadMembers(members, c.Query("email")), from, to, time.Now())
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to compute workload.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, workload, "Workload retrieved successfully.")
+}
+
+// GetMemberCapacity retrieves the weekly capacity of the current user, or of the user given by the email query parameter.
+func GetMemberCapacity(c *gin.Context) {
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	target := c.DefaultQuery("email", email)
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	var capacity pmv1.MemberCapacity
+	err := tx.Where("LOWER(email) = ?", strings.ToLower(target)).First(&capacity).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch member capacity.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	response := pmv1.MemberCapacityResponse{Email: capacity.Email, WeeklyHours: capacity.WeeklyHours, UpdatedAt: &capacity.UpdatedAt}
+	if err == gorm.ErrRecordNotFound {
+		today := time.Now()
+		calendar, err := services.LoadCalendar(tx, pmmiddlewares.OrganizationID(c), target, today, today)
+		if err != nil {
+			tx.Rollback()
+			logger.LogError("Failed to load working calendar.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			return
+		}
+		response = pmv1.MemberCapacityResponse{Email: target, WeeklyHours: calendar.WeeklyHours(), IsDefault: true}
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	models.SendSuccessResponse(c, http.StatusOK, response, "Member capacity retrieved successfully.")
+}
+
+// UpdateMemberCapacity sets the weekly capacity of a user.
+// Users can set their own capacity; Managers and Owners can set the capacity of the members of their projects.
+func UpdateMemberCapacity(c *gin.Context) {
+	var req pmv1.MemberCapacityRequest
+
+	email, valid := utils.GetEmailFromContext(c)
+	if !valid {
+		return
+	}
+
+	if !utils.BindJSONRequest(c, &req, email) {
+		return
+	}
+
+	target := req.Email
+	if target == "" {
+		target = email
+	}
+
+	tx, ok := startTransaction(c, email)
+	if !ok {
+		return
+	}
+
+	if !authorizeMemberSchedule(c, tx, email, target) {
+		return
+	}
+
+	capacity := pmv1.MemberCapacity{Email: target}
+	if err := tx.Where("LOWER(email) = ?", strings.ToLower(target)).First(&capacity).Error; err != nil && err != gorm.ErrRecordNotFound {
+		tx.Rollback()
+		logger.LogError("Failed to fetch member capacity.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	capacity.WeeklyHours = *req.WeeklyHours
+	capacity.UpdatedBy = email
+
+	if err := tx.Save(&capacity).Error; err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to update member capacity.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return
+	}
+
+	if !utils.CommitTransaction(tx, c, email) {
+		return
+	}
+
+	response := pmv1.MemberCapacityResponse{Email: capacity.Email, WeeklyHours: capacity.WeeklyHours, UpdatedAt: &capacity.UpdatedAt}
+	models.SendSuccessResponse(c, http.StatusOK, response, "Member capacity updated successfully.")
+}
+
+// parseWorkloadRange reads the from and to query parameters, defaulting to the next two weeks.
+func parseWorkloadRange(c *gin.Context, email string) (time.Time, time.Time, bool) {
+	from := services.TruncateDay(time.Now())
+	if value := c.Query("from"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			logger.LogError("Failed to parse from date.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+			return time.Time{}, time.Time{}, false
+		}
+		from = parsed
+	}
+
+	to := from.AddDate(0, 0, defaultWorkloadDays-1)
+	if value := c.Query("to"); value != "" {
+		parsed, err := time.Parse("2006-01-02", value)
+		if err != nil {
+			logger.LogError("Failed to parse to date.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+			return time.Time{}, time.Time{}, false
+		}
+		to = parsed
+	}
+
+	if to.Before(from) || to.Sub(from).Hours()/24 >= services.MaxWorkloadDays {
+		logger.LogError("Invalid workload date range.", logrus.Fields{"email": email, "from": from, "to": to})
+		models.SendErrorResponse(c, http.StatusBadRequest, errors.ErrBadRequest)
+		return time.Time{}, time.Time{}, false
+	}
+
+	return from, to, true
+}
+
+// managedProjectIDs returns the projects of an organization the user holds a role allowed to manage
+// members in.
+func managedProjectIDs(tx *gorm.DB, organizationID uuid.UUID, email string) ([]uuid.UUID, error) {
+	managed, err := services.ProjectsWithPermission(tx, organizationID, email, pmv1.PermissionMemberManage)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectIDs []uuid.UUID
+	err = tx.Model(&v1.Project{}).
+		Where("deleted_at IS NULL").
+		Where("id IN (?)", managed).
+		Pluck("id", &projectIDs).Error
+	return projectIDs, err
+}
+
+// authorizeMemberSchedule checks that the user may change the capacity, calendar or time off of target:
+// their own, or that of a member of a project they manage. It rolls back and responds on failure.
+func authorizeMemberSchedule(c *gin.Context, tx *gorm.DB, email, target string) bool {
+	if strings.EqualFold(target, email) {
+		return true
+	}
+
+	projectIDs, err := managedProjectIDs(tx, pmmiddlewares.OrganizationID(c), email)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch managed projects.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+
+	members, err := services.ProjectMemberEmails(tx, projectIDs)
+	if err != nil {
+		tx.Rollback()
+		logger.LogError("Failed to fetch project members.", logrus.Fields{"error": err.Error(), "email": email})
+		models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+		return false
+	}
+
+	if len(filterWorkloadMembers(members, target)) == 0 {
+		tx.Rollback()
+		models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+		return false
+	}
+	return true
+}
+
+// filterWorkloadMembers restricts members to the given email, when one is set.
+func filterWorkloadMembers(members []string, email string) []string {
+	if email == "" {
+		return members
+	}
+	for _, member := range members {
+		if strings.EqualFold(member, email) {
+			return []string{member}
+		}
+	}
+	return nil
+}
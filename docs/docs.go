@@ -0,0 +1,240 @@
+// Package docs holds the OpenAPI documentation of the API, embedded in the binary, and builds the
+// specification served by the API from it and from the routes actually registered, so that the spec
+// cannot describe routes the router does not serve.
+package docs
+
+import (
+	_ "embed"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BasePath is the prefix of the documented API routes, the path of the servers of the spec.
+const BasePath = "/api/v1"
+
+// openAPI is the hand-maintained documentation of the API.
+//
+//go:embed openapi.json
+var openAPI []byte
+
+// methods are the HTTP methods documented as operations.
+var methods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// routeParam matches the parameters of gin route paths.
+var routeParam = regexp.MustCompile(`[:*](\w+)`)
+
+// wordBoundary matches the start of each word of a camel case name.
+var wordBoundary = regexp.MustCompile(`([a-z0-9])([A-Z])`)
+
+// Spec is an OpenAPI document.
+type Spec map[string]interface{}
+
+// Reasons of drift between the documentation and the routes.
+const (
+	DriftNotServed   = "documented but not served"
+	DriftUndescribed = "served but neither documented nor described in docs/operations.go"
+)
+
+// Drift is a documented operation the router does not serve, or a served route whose handler is not
+// documented and has no operation describing its request and response.
+type Drift struct {
+	Method string
+	Path   string
+	Reason string
+}
+
+// Generate returns the spec of the given routes. Documented operations of served routes are kept as
+// documented, served routes under BasePath without documentation get an operation generated from the
+// route and the types of its handler, and documented operations without a route are left out. Both
+// the operations without a route and the generated ones without types are returned as drift.
+func Generate(routes gin.RoutesInfo) (Spec, []Drift, error) {
+	var spec Spec
+	if err := json.Unmarshal(openAPI, &spec); err != nil {
+		return nil, nil, err
+	}
+	documented, _ := spec["paths"].(map[string]interface{})
+	components, _ := spec["components"].(map[string]interface{})
+	if components == nil {
+		components = map[string]interface{}{}
+		spec["components"] = components
+	}
+	componentSchemas, _ := components["schemas"].(map[string]interface{})
+	if componentSchemas == nil {
+		componentSchemas = map[string]interface{}{}
+		components["schemas"] = componentSchemas
+	}
+	types := newSchemas(componentSchemas)
+	var drift []Drift
+
+	paths := map[string]interface{}{}
+	served := map[string]bool{}
+	for _, route := range routes {
+		if !methods[route.Method] || !strings.HasPrefix(route.Path, BasePath+"/") {
+			continue
+		}
+		path := routeParam.ReplaceAllString(strings.TrimPrefix(route.Path, BasePath), "{$1}")
+		method := strings.ToLower(route.Method)
+		served[method+" "+path] = true
+
+		item, ok := paths[path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[path] = item
+		}
+		if operation, ok := documentedOperation(documented, path, method); ok {
+			item[method] = operation
+			continue
+		}
+		operation, described := generatedOperation(route, path, types)
+		item[method] = operation
+		if !described {
+			drift = append(drift, Drift{Method: route.Method, Path: path, Reason: DriftUndescribed})
+		}
+	}
+
+	for path, item := range documented {
+		operations, _ := item.(map[string]interface{})
+		for method := range operations {
+			if methods[strings.ToUpper(method)] && !served[method+" "+path] {
+				drift = append(drift, Drift{Method: strings.ToUpper(method), Path: path, Reason: DriftNotServed})
+			}
+		}
+	}
+	sort.Slice(drift, func(i, j int) bool {
+		if drift[i].Path != drift[j].Path {
+			return drift[i].Path < drift[j].Path
+		}
+		return drift[i].Method < drift[j].Method
+	})
+
+	spec["paths"] = paths
+	return spec, drift, nil
+}
+
+// WithServers returns a copy of the spec listing the given servers before the documented ones.
+func (s Spec) WithServers(servers ...map[string]interface{}) Spec {
+	spec := make(Spec, len(s))
+	for key, value := range s {
+		spec[key] = value
+	}
+
+	list := make([]interface{}, 0, len(servers))
+	seen := map[interface{}]bool{}
+	for _, server := range servers {
+		list = append(list, server)
+		seen[server["url"]] = true
+	}
+	documented, _ := s["servers"].([]interface{})
+	for _, server := range documented {
+		if entry, ok := server.(map[string]interface{}); ok && seen[entry["url"]] {
+			continue
+		}
+		list = append(list, server)
+	}
+	spec["servers"] = list
+	return spec
+}
+
+// documentedOperation returns the documented operation of a path and method.
+func documentedOperation(documented map[string]interface{}, path, method string) (interface{}, bool) {
+	item, ok := documented[path].(map[string]interface{})
+	if !ok {
+		return nil, false
+	}
+	operation, ok := item[method]
+	return operation, ok
+}
+
+// generatedOperation describes an undocumented route with what the route and its handler tell: the
+// handler, path parameters, the request and response of the handler listed in operations and the error
+// responses shared by the API. It reports whether the handler is listed.
+func generatedOperation(route gin.RouteInfo, path string, types *schemas) (map[string]interface{}, bool) {
+	name := route.Handler[strings.LastIndex(route.Handler, ".")+1:]
+	if strings.HasPrefix(name, "func") {
+		name = strings.ToLower(route.Method) + strings.NewReplacer("/", "_", "{", "", "}", "", "-", "_").Replace(path)
+	}
+
+	parameters := []interface{}{}
+	for _, match := range routeParam.FindAllStringSubmatch(route.Path, -1) {
+		parameters = append(parameters, map[string]interface{}{
+			"name":     match[1],
+			"in":       "path",
+			"required": true,
+			"schema":   map[string]interface{}{"type": "string"},
+		})
+	}
+
+	generated := map[string]interface{}{
+		"operationId": name,
+		"summary":     strings.ToUpper(name[:1]) + strings.ToLower(wordBoundary.ReplaceAllString(name[1:], "$1 $2")),
+		"tags":        []interface{}{tag(path)},
+		"parameters":  parameters,
+	}
+	responses := map[string]interface{}{
+		"400": map[string]interface{}{"$ref": "#/components/responses/BadRequest"},
+		"401": map[string]interface{}{"$ref": "#/components/responses/Unauthorized"},
+		"403": map[string]interface{}{"$ref": "#/components/responses/Forbidden"},
+		"404": map[string]interface{}{"$ref": "#/components/responses/NotFound"},
+		"500": map[string]interface{}{"$ref": "#/components/responses/InternalServerError"},
+	}
+	generated["responses"] = responses
+
+	operation, described := operations[name]
+	if !described {
+		responses["2XX"] = map[string]interface{}{"description": "Successful response."}
+		return generated, false
+	}
+
+	if operation.request != nil {
+		generated["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(types.of(operation.request)),
+		}
+	}
+	status := operation.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := map[string]interface{}{"description": http.StatusText(status) + "."}
+	if len(operation.documents) > 0 {
+		content := map[string]interface{}{}
+		for _, mediaType := range operation.documents {
+			schema := map[string]interface{}{"type": "string", "format": "binary"}
+			if mediaType == "application/json" {
+				schema = types.of(operation.response)
+			}
+			content[mediaType] = map[string]interface{}{"schema": schema}
+		}
+		success["content"] = content
+	} else if status != http.StatusNoContent {
+		var data map[string]interface{}
+		if operation.response != nil {
+			data = types.of(operation.response)
+		}
+		success["content"] = jsonContent(envelope(data, operation.paginated))
+	}
+	responses[strconv.Itoa(status)] = success
+	return generated, true
+}
+
+// jsonContent returns the content of a JSON request or response body of the given schema.
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// tag returns the tag grouping an undocumented route: the resource it acts on, named by the first static
+// segment of its path after the project it may be nested in.
+func tag(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 2 && segments[0] == "project" && strings.HasPrefix(segments[1], "{") {
+		segments = segments[2:]
+	}
+	name := strings.ReplaceAll(segments[0], "-", " ")
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,127 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type testAudit struct {
+	CreatedAt time.Time `json:"created_at"`
+	CreatedBy string    `json:"created_by"`
+}
+
+type testNode struct {
+	testAudit
+	ID       uuid.UUID       `json:"id"`
+	Name     string          `json:"name" binding:"required,max=100"`
+	Kind     string          `json:"kind" binding:"required,oneof=epic story"`
+	Owner    string          `json:"owner" binding:"omitempty,email"`
+	Estimate *float64        `json:"estimate,omitempty"`
+	Labels   []string        `json:"labels" binding:"dive,oneof=red green"`
+	Settings json.RawMessage `json:"settings"`
+	Children []testNode      `json:"children"`
+	Counts   map[string]int  `json:"counts"`
+	secret   string
+	Hash     string `json:"-"`
+}
+
+func TestSchemas(t *testing.T) {
+	components := map[string]interface{}{"Meta": map[string]interface{}{"title": "Meta"}}
+	types := newSchemas(components)
+
+	if got := types.of([]testNode{}); !reflect.DeepEqual(got, map[string]interface{}{
+		"type":  "array",
+		"items": map[string]interface{}{"$ref": "#/components/schemas/testNode"},
+	}) {
+		t.Fatalf("schema of a list = %v", got)
+	}
+
+	node, _ := components["testNode"].(map[string]interface{})
+	properties, _ := node["properties"].(map[string]interface{})
+	want := map[string]interface{}{
+		"created_at": map[string]interface{}{"type": "string", "format": "date-time"},
+		"created_by": map[string]interface{}{"type": "string"},
+		"id":         map[string]interface{}{"type": "string", "format": "uuid"},
+		"name":       map[string]interface{}{"type": "string"},
+		"kind":       map[string]interface{}{"type": "string", "enum": []interface{}{"epic", "story"}},
+		"owner":      map[string]interface{}{"type": "string", "format": "email"},
+		"estimate":   map[string]interface{}{"type": []interface{}{"number", "null"}},
+		"labels":     map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		"settings":   map[string]interface{}{},
+		"children":   map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/components/schemas/testNode"}},
+		"counts":     map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer"}},
+	}
+	if !reflect.DeepEqual(properties, want) {
+		t.Errorf("properties = %v, want %v", properties, want)
+	}
+	if required := node["required"]; !reflect.DeepEqual(required, []interface{}{"name", "kind"}) {
+		t.Errorf("required = %v, want name and kind", required)
+	}
+
+	// Documented components are referenced as documented.
+	type Meta struct {
+		Page int `json:"page"`
+	}
+	if got := types.of(Meta{}); got["$ref"] != "#/components/schemas/Meta" || !reflect.DeepEqual(components["Meta"], map[string]interface{}{"title": "Meta"}) {
+		t.Errorf("documented component replaced: %v, %v", got, components["Meta"])
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	spec, drift, err := Generate(gin.RoutesInfo{
+		{Method: http.MethodPost, Path: BasePath + "/calendar/holiday", Handler: "github.com/san-data-systems/project-management-api/controllers/v1.CreateHoliday"},
+		{Method: http.MethodDelete, Path: BasePath + "/calendar/holiday/:holiday_id", Handler: "github.com/san-data-systems/project-management-api/controllers/v1.DeleteHoliday"},
+		{Method: http.MethodGet, Path: BasePath + "/calendar/export", Handler: "github.com/san-data-systems/project-management-api/controllers/v1.ExportCalendar"},
+		{Method: http.MethodGet, Path: BasePath + "/project/:project_id/labels", Handler: "github.com/san-data-systems/project-management-api/controllers/v1.ListProjectLabels"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	paths := spec["paths"].(map[string]interface{})
+	create := paths["/calendar/holiday"].(map[string]interface{})["post"].(map[string]interface{})
+	body := create["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"]
+	if !reflect.DeepEqual(body, map[string]interface{}{"$ref": "#/components/schemas/HolidayRequest"}) {
+		t.Errorf("request schema = %v", body)
+	}
+	created, ok := create["responses"].(map[string]interface{})["201"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("responses = %v, want a 201", create["responses"])
+	}
+	data := created["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})["properties"].(map[string]interface{})["data"]
+	if !reflect.DeepEqual(data, map[string]interface{}{"$ref": "#/components/schemas/HolidayResponse"}) {
+		t.Errorf("response data schema = %v", data)
+	}
+	schemas := spec["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+	for _, name := range []string{"HolidayRequest", "HolidayResponse", "ProjectLabelResponse"} {
+		if schemas[name] == nil {
+			t.Errorf("no %s component", name)
+		}
+	}
+
+	remove := paths["/calendar/holiday/{holiday_id}"].(map[string]interface{})["delete"].(map[string]interface{})
+	if _, ok := remove["requestBody"]; ok {
+		t.Error("operation without a request has a request body")
+	}
+
+	undescribed, notServed := 0, 0
+	for _, d := range drift {
+		switch {
+		case d.Reason == DriftUndescribed && d.Method == http.MethodGet && d.Path == "/calendar/export":
+			undescribed++
+		case d.Reason == DriftNotServed && d.Path != "/project/{project_id}/labels":
+			notServed++
+		default:
+			t.Errorf("unexpected drift %+v", d)
+		}
+	}
+	if undescribed != 1 || notServed == 0 {
+		t.Errorf("drift = %+v, want the undescribed route and the documented operations not served", drift)
+	}
+}
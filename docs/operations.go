@@ -0,0 +1,172 @@
+package docs
+
+import (
+	"net/http"
+
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// operation describes what an undocumented handler reads and sends, for the operation generated for
+// its routes.
+type operation struct {
+	// request is the JSON body the handler binds, nil when it reads none.
+	request interface{}
+	// response is the data of the success response, nil when it sends none.
+	response interface{}
+	// status is the status of the success response, 200 when unset.
+	status int
+	// paginated sends response as a list with pagination metadata.
+	paginated bool
+	// documents are the media types of a document sent as is instead of the success response, with
+	// response as its JSON form.
+	documents []string
+}
+
+// operations are the operations of the undocumented handlers, by handler name. A route served by a
+// handler that is neither documented in docs/openapi.json nor listed here is reported as drift.
+var operations = map[string]operation{
+	// Organizations and their members.
+	"ListOrganizations":        {response: []pmv1.OrganizationResponse{}},
+	"CreateOrganization":       {request: pmv1.OrganizationRequest{}, response: pmv1.OrganizationResponse{}, status: http.StatusCreated},
+	"GetOrganization":          {response: pmv1.OrganizationResponse{}},
+	"UpdateOrganization":       {request: pmv1.OrganizationRequest{}, response: pmv1.OrganizationResponse{}},
+	"AddOrganizationMember":    {request: pmv1.OrganizationMemberRequest{}, response: pmv1.OrganizationMemberResponse{}, status: http.StatusCreated},
+	"UpdateOrganizationMember": {request: pmv1.OrganizationMemberRequest{}, response: pmv1.OrganizationMemberResponse{}},
+	"RemoveOrganizationMember": {},
+
+	// Teams and group mappings.
+	"ListTeams":          {response: []pmv1.TeamResponse{}},
+	"CreateTeam":         {request: pmv1.TeamRequest{}, response: pmv1.TeamResponse{}, status: http.StatusCreated},
+	"GetTeam":            {response: pmv1.TeamResponse{}},
+	"UpdateTeam":         {request: pmv1.TeamRequest{}, response: pmv1.TeamResponse{}},
+	"DeleteTeam":         {},
+	"AddTeamMembers":     {request: pmv1.TeamMembersRequest{}, response: pmv1.TeamResponse{}},
+	"RemoveTeamMember":   {},
+	"ListProjectTeams":   {response: []pmv1.ProjectTeamResponse{}},
+	"AddProjectTeam":     {request: pmv1.ProjectTeamRequest{}, response: pmv1.ProjectTeamResponse{}, status: http.StatusCreated},
+	"UpdateProjectTeam":  {request: pmv1.ProjectTeamRequest{}, response: pmv1.ProjectTeamResponse{}},
+	"RemoveProjectTeam":  {},
+	"ListGroupMappings":  {response: []pmv1.GroupMapping{}},
+	"CreateGroupMapping": {request: pmv1.GroupMappingRequest{}, response: pmv1.GroupMapping{}, status: http.StatusCreated},
+	"DeleteGroupMapping": {},
+
+	// Roles and permissions.
+	"ListPermissions":         {response: []string{}},
+	"ListRoles":               {response: []pmv1.RoleResponse{}},
+	"CreateCustomRole":        {request: pmv1.CustomRoleRequest{}, response: pmv1.RoleResponse{}, status: http.StatusCreated},
+	"UpdateCustomRole":        {request: pmv1.CustomRoleRequest{}, response: pmv1.RoleResponse{}},
+	"DeleteCustomRole":        {},
+	"GetProjectPermissions":   {response: pmv1.ProjectPermissionsResponse{}},
+	"UpdateProjectMemberRole": {request: pmv1.ProjectMemberRoleRequest{}, response: v1.ProjectMember{}},
+
+	// Access tokens and service accounts.
+	"ListAccessTokens":          {response: []pmv1.AccessTokenResponse{}},
+	"CreateAccessToken":         {request: pmv1.AccessTokenRequest{}, response: pmv1.AccessTokenResponse{}, status: http.StatusCreated},
+	"RevokeAccessToken":         {},
+	"ListServiceAccounts":       {response: []pmv1.ServiceAccount{}},
+	"CreateServiceAccount":      {request: pmv1.ServiceAccountRequest{}, response: pmv1.ServiceAccount{}, status: http.StatusCreated},
+	"DeleteServiceAccount":      {},
+	"ListServiceAccountTokens":  {response: []pmv1.AccessTokenResponse{}},
+	"CreateServiceAccountToken": {request: pmv1.AccessTokenRequest{}, response: pmv1.AccessTokenResponse{}, status: http.StatusCreated},
+	"RevokeServiceAccountToken": {},
+	"AddProjectServiceAccount":  {request: pmv1.ProjectServiceAccountRequest{}, response: pmv1.ProjectServiceAccountResponse{}, status: http.StatusCreated},
+
+	// Invitations and ownership.
+	"ListProjectInvitations":   {response: []pmv1.ProjectInvitationResponse{}},
+	"InviteProjectMember":      {request: pmv1.ProjectInvitationRequest{}, response: pmv1.ProjectInvitationResponse{}, status: http.StatusCreated},
+	"ResendProjectInvitation":  {response: pmv1.ProjectInvitationResponse{}},
+	"RevokeProjectInvitation":  {response: pmv1.ProjectInvitationResponse{}},
+	"ListMyInvitations":        {response: []pmv1.ProjectInvitationResponse{}},
+	"AcceptInvitation":         {request: pmv1.InvitationTokenRequest{}, response: pmv1.ProjectInvitationResponse{}},
+	"DeclineInvitation":        {request: pmv1.InvitationTokenRequest{}, response: pmv1.ProjectInvitationResponse{}},
+	"TransferProjectOwnership": {request: pmv1.TransferOwnershipRequest{}, response: pmv1.ProjectOwnersResponse{}},
+
+	// Projects, templates and saved views.
+	"GetProjectStatsByID":    {response: pmv1.ProjectStatsResponse{}},
+	"ArchiveProject":         {response: pmv1.ProjectArchiveResponse{}},
+	"UnarchiveProject":       {response: pmv1.ProjectArchiveResponse{}},
+	"CloneProject":           {request: pmv1.CloneProjectRequest{}, response: v1.ProjectResponse{}, status: http.StatusCreated},
+	"ListProjectTemplates":   {response: []pmv1.ProjectTemplateResponse{}},
+	"CreateProjectTemplate":  {request: pmv1.ProjectTemplateRequest{}, response: pmv1.ProjectTemplateResponse{}, status: http.StatusCreated},
+	"GetProjectTemplate":     {response: pmv1.ProjectTemplateResponse{}},
+	"DeleteProjectTemplate":  {},
+	"CaptureProjectTemplate": {request: pmv1.CaptureTemplateRequest{}, response: pmv1.ProjectTemplateResponse{}, status: http.StatusCreated},
+	"ListSavedViews":         {response: []pmv1.SavedViewResponse{}},
+	"CreateSavedView":        {request: pmv1.SavedViewRequest{}, response: pmv1.SavedViewResponse{}, status: http.StatusCreated},
+	"DeleteSavedView":        {},
+
+	// Workflow and board.
+	"GetProjectWorkflow":         {response: pmv1.WorkflowResponse{}},
+	"CreateStateTransition":      {request: pmv1.StateTransitionRequest{}, response: pmv1.StateTransitionResponse{}, status: http.StatusCreated},
+	"DeleteStateTransition":      {},
+	"UpdateProjectStateCategory": {request: pmv1.StateCategoryRequest{}, response: pmv1.StateCategoryResponse{}},
+	"BulkMoveIssues":             {request: pmv1.BulkMoveIssuesRequest{}, response: pmv1.BulkMoveIssuesResponse{}},
+	"GetProjectBoard":            {response: pmv1.BoardResponse{}},
+	"SetStateWIPLimit":           {request: pmv1.StateWIPLimitRequest{}, response: pmv1.StateWIPLimit{}},
+	"DeleteStateWIPLimit":        {},
+
+	// Issues, their links and comments.
+	"UpdateIssueByID":     {request: v1.UpdateIssueRequest{}, response: v1.IssueResponse{}},
+	"GetIssueFiles":       {response: v1.IssueFileResponse{}, paginated: true},
+	"ListIssueLinks":      {response: v1.IssueLinkResponse{}, paginated: true},
+	"CreateIssueLink":     {request: v1.IssueLinkRequest{}, response: v1.IssueLinkResponse{}, status: http.StatusCreated},
+	"GetIssueLinkByID":    {response: v1.IssueLinkResponse{}},
+	"UpdateIssueLinkByID": {request: v1.IssueLinkRequest{}, response: v1.IssueLinkResponse{}},
+	"DeleteIssueLink":     {},
+	"ListIssueComments":   {response: []pmv1.IssueComment{}},
+	"CreateIssueComment":  {request: pmv1.IssueCommentRequest{}, response: pmv1.IssueComment{}, status: http.StatusCreated},
+	"DeleteIssueComment":  {},
+
+	// Trash.
+	"ListTrash":               {response: pmv1.TrashResponse{}},
+	"RestoreTrashedProject":   {response: pmv1.RestoreResponse{}},
+	"PurgeTrashedProject":     {},
+	"RestoreTrashedClient":    {response: pmv1.RestoreResponse{}},
+	"PurgeTrashedClient":      {},
+	"ListProjectTrash":        {response: pmv1.ProjectTrashResponse{}},
+	"RestoreProjectTrashItem": {response: pmv1.RestoreResponse{}},
+	"PurgeProjectTrashItem":   {},
+
+	// Notifications.
+	"ListNotifications":        {response: pmv1.NotificationResponse{}, paginated: true},
+	"MarkNotificationRead":     {},
+	"MarkAllNotificationsRead": {},
+
+	// Calendars and workload.
+	"GetOrganizationCalendar":    {response: pmv1.WorkingCalendarResponse{}},
+	"UpdateOrganizationCalendar": {request: pmv1.WorkingCalendarRequest{}, response: pmv1.WorkingCalendarResponse{}},
+	"GetMemberCalendar":          {response: pmv1.WorkingCalendarResponse{}},
+	"UpdateMemberCalendar":       {request: pmv1.WorkingCalendarRequest{}, response: pmv1.WorkingCalendarResponse{}},
+	"DeleteMemberCalendar":       {},
+	"ListHolidays":               {response: []pmv1.HolidayResponse{}},
+	"CreateHoliday":              {request: pmv1.HolidayRequest{}, response: pmv1.HolidayResponse{}, status: http.StatusCreated},
+	"DeleteHoliday":              {},
+	"ListTimeOff":                {response: []pmv1.TimeOffResponse{}},
+	"CreateTimeOff":              {request: pmv1.TimeOffRequest{}, response: pmv1.TimeOffResponse{}, status: http.StatusCreated},
+	"DeleteTimeOff":              {},
+	"GetCalendarDays":            {response: pmv1.CalendarDaysResponse{}},
+	"GetWorkload":                {response: pmv1.WorkloadResponse{}},
+	"GetProjectWorkload":         {response: pmv1.WorkloadResponse{}},
+	"GetMemberCapacity":          {response: pmv1.MemberCapacityResponse{}},
+	"UpdateMemberCapacity":       {request: pmv1.MemberCapacityRequest{}, response: pmv1.MemberCapacityResponse{}},
+
+	// Billing, budgets and invoices.
+	"GetClientBilling":            {response: pmv1.ClientBillingResponse{}},
+	"UpdateClientBilling":         {request: pmv1.ClientBillingRequest{}, response: pmv1.ClientBillingResponse{}},
+	"GetProjectBilling":           {response: pmv1.ProjectBillingResponse{}},
+	"UpdateProjectBilling":        {request: pmv1.ProjectBillingRequest{}, response: pmv1.ProjectBillingResponse{}},
+	"SetProjectMemberRate":        {request: pmv1.ProjectMemberRateRequest{}, response: pmv1.ProjectMemberRateItem{}},
+	"DeleteProjectMemberRate":     {status: http.StatusNoContent},
+	"UpdateTimeEntryBilling":      {request: pmv1.TimeEntryBillingRequest{}, response: pmv1.TimeEntryBillingResponse{}},
+	"ApproveTimeEntry":            {response: pmv1.TimeEntryBillingResponse{}},
+	"GetProjectBudget":            {response: pmv1.ProjectBudgetResponse{}},
+	"UpdateProjectBudget":         {request: pmv1.ProjectBudgetRequest{}, response: pmv1.ProjectBudgetResponse{}},
+	"GetProjectBudgetConsumption": {response: pmv1.BudgetConsumption{}},
+	"ListClientInvoices":          {response: pmv1.InvoiceResponse{}, paginated: true},
+	"CreateClientInvoice":         {request: pmv1.InvoiceRequest{}, response: pmv1.InvoiceResponse{}, status: http.StatusCreated},
+	"GetInvoiceByID":              {response: pmv1.InvoiceResponse{}},
+	"IssueInvoice":                {response: pmv1.InvoiceResponse{}},
+	"VoidInvoice":                 {response: pmv1.InvoiceResponse{}},
+	"ExportInvoice":               {response: pmv1.InvoiceResponse{}, documents: []string{"text/html", "application/pdf", "application/json"}},
+}
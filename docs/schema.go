@@ -0,0 +1,181 @@
+package docs
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// schemas builds the JSON schemas of Go types. Named structs are added to the schema components of the
+// spec and referenced, so that a type used by several operations is described once; a component
+// documented in docs/openapi.json is referenced as documented.
+type schemas struct {
+	components map[string]interface{}
+	generated  map[string]reflect.Type
+}
+
+func newSchemas(components map[string]interface{}) *schemas {
+	return &schemas{components: components, generated: map[string]reflect.Type{}}
+}
+
+// of returns the schema of the type of a value.
+func (s *schemas) of(value interface{}) map[string]interface{} {
+	return s.schema(reflect.TypeOf(value))
+}
+
+// schema returns the schema of a type as encoding/json marshals it.
+func (s *schemas) schema(t reflect.Type) map[string]interface{} {
+	switch t {
+	case timeType:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case uuidType:
+		return map[string]interface{}{"type": "string", "format": "uuid"}
+	case rawMessageType:
+		return map[string]interface{}{}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		schema := s.schema(t.Elem())
+		if kind, ok := schema["type"].(string); ok {
+			schema["type"] = []interface{}{kind, "null"}
+		}
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": s.schema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": s.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return s.object(t)
+		}
+		return s.ref(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// ref returns a reference to the component of a named struct, adding the component when it is neither
+// documented nor generated yet. A generated name already taken by another type gets a numeric suffix.
+func (s *schemas) ref(t reflect.Type) map[string]interface{} {
+	name := t.Name()
+	for n := 2; ; n++ {
+		generated, ok := s.generated[name]
+		if ok && generated == t {
+			break
+		}
+		if !ok {
+			if _, documented := s.components[name]; documented && name == t.Name() {
+				break
+			}
+			if _, taken := s.components[name]; !taken {
+				s.generated[name] = t
+				// The component is set before its fields are described so that recursive types end.
+				s.components[name] = map[string]interface{}{}
+				s.components[name] = s.object(t)
+				break
+			}
+		}
+		name = t.Name() + strconv.Itoa(n)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+}
+
+// object returns the schema of a struct, with the properties encoding/json marshals and the required
+// and enumerated values of its binding tags.
+func (s *schemas) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []interface{}{}
+	s.fields(t, properties, &required)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// fields adds the properties of the fields of a struct, including those of embedded structs.
+func (s *schemas) fields(t reflect.Type, properties map[string]interface{}, required *[]interface{}) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				s.fields(embedded, properties, required)
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.schema(field.Type)
+		for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+			key, value, _ := strings.Cut(rule, "=")
+			if key == "dive" {
+				break
+			}
+			switch key {
+			case "required":
+				*required = append(*required, name)
+			case "oneof":
+				var enum []interface{}
+				for _, option := range strings.Fields(value) {
+					enum = append(enum, option)
+				}
+				schema["enum"] = enum
+			case "email", "uuid":
+				schema["format"] = key
+			}
+		}
+		properties[name] = schema
+	}
+}
+
+// envelope returns the schema of a success response of the API carrying data of the given schema, if
+// any, as a list with pagination metadata when the response is paginated.
+func envelope(data map[string]interface{}, paginated bool) map[string]interface{} {
+	properties := map[string]interface{}{
+		"success": map[string]interface{}{"type": "boolean", "example": true},
+		"message": map[string]interface{}{"type": "string"},
+	}
+	if paginated {
+		data = map[string]interface{}{"type": "array", "items": data}
+		properties["meta"] = map[string]interface{}{"$ref": "#/components/schemas/Meta"}
+	}
+	if data != nil {
+		properties["data"] = data
+	}
+	return map[string]interface{}{"type": "object", "properties": properties}
+}
@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// startKey is the statement setting holding the time a query started.
+const startKey = "metrics:start"
+
+// GORMPlugin records the duration and errors of the queries run through a GORM database.
+type GORMPlugin struct{}
+
+// Name returns the name of the plugin.
+func (GORMPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize registers the callbacks timing each kind of query.
+func (GORMPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("metrics:before_create", before),
+		callbacks.Create().After("*").Register("metrics:after_create", after("create")),
+		callbacks.Query().Before("*").Register("metrics:before_query", before),
+		callbacks.Query().After("*").Register("metrics:after_query", after("query")),
+		callbacks.Update().Before("*").Register("metrics:before_update", before),
+		callbacks.Update().After("*").Register("metrics:after_update", after("update")),
+		callbacks.Delete().Before("*").Register("metrics:before_delete", before),
+		callbacks.Delete().After("*").Register("metrics:after_delete", after("delete")),
+		callbacks.Row().Before("*").Register("metrics:before_row", before),
+		callbacks.Row().After("*").Register("metrics:after_row", after("row")),
+		callbacks.Raw().Before("*").Register("metrics:before_raw", before),
+		callbacks.Raw().After("*").Register("metrics:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before records the time a query starts.
+func before(db *gorm.DB) {
+	db.InstanceSet(startKey, time.Now())
+}
+
+// after observes the duration and outcome of a query.
+func after(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		value, ok := db.InstanceGet(startKey)
+		if !ok {
+			return
+		}
+		start, ok := value.(time.Time)
+		if !ok {
+			return
+		}
+
+		table := db.Statement.Table
+		if table == "" {
+			table = "unknown"
+		}
+		dbQueryDuration.WithLabelValues(operation, table).Observe(time.Since(start).Seconds())
+		if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+			dbQueryErrors.WithLabelValues(operation, table).Inc()
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package metrics exposes the service's Prometheus metrics: HTTP requests, database queries and
+// connection pool, object storage transfers, and business counters. Collectors are registered with the
+// default Prometheus registry, which also carries the Go runtime and process metrics, and served by
+// Handler.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes the names of the service's metrics.
+const namespace = "pm"
+
+// unmatchedRoute labels requests that matched no route, so that probing random paths does not create a
+// series per path.
+const unmatchedRoute = "unmatched"
+
+var (
+	httpRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests handled, by route template, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to handle HTTP requests, by route template, method and status.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	httpInFlight = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests being handled.",
+	})
+
+	dbQueryDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_query_duration_seconds",
+		Help:      "Time taken by database queries, by operation and table.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"operation", "table"})
+
+	dbQueryErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_query_errors_total",
+		Help:      "Database queries that failed, by operation and table. Lookups finding no record are not errors.",
+	}, []string{"operation", "table"})
+
+	storageBytes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "storage_bytes_total",
+		Help:      "Bytes uploaded to object storage, and size of the files download URLs were issued for, by operation.",
+	}, []string{"operation"})
+
+	storageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "storage_operation_duration_seconds",
+		Help:      "Time taken by object storage operations, by operation and result.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operation", "result"})
+
+	issuesCreated = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "issues_created_total",
+		Help:      "Issues created.",
+	})
+
+	timeLogged = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "time_logged_hours_total",
+		Help:      "Hours logged in time entries.",
+	})
+)
+
+// Storage operations.
+const (
+	StorageUpload   = "upload"
+	StorageDownload = "download"
+)
+
+// Handler serves the metrics in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// Middleware records the count and duration of HTTP requests. Requests are labelled with the route
+// template, such as /api/v1/project/:project_id, rather than the raw path to keep the number of series
+// bounded.
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		httpDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// RegisterDBStats exports the statistics of a database connection pool: open, in use and idle
+// connections, and waits for a free connection.
+func RegisterDBStats(db *sql.DB, name string) error {
+	return prometheus.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveStorage records an object storage operation transferring the given number of bytes.
+func ObserveStorage(operation string, bytes int64, duration time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	} else {
+		storageBytes.WithLabelValues(operation).Add(float64(bytes))
+	}
+	storageDuration.WithLabelValues(operation, result).Observe(duration.Seconds())
+}
+
+// IssuesCreated records created issues.
+func IssuesCreated(count int) {
+	issuesCreated.Add(float64(count))
+}
+
+// TimeLogged records logged time.
+func TimeLogged(duration time.Duration) {
+	if duration > 0 {
+		timeLogged.Add(duration.Hours())
+	}
+}
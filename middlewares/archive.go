@@ -0,0 +1,42 @@
+// Package middlewares contains the Gin middlewares specific to the project management API.
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+)
+
+// ProjectWritable rejects requests that modify an archived project. Routes without a valid
+// project_id parameter are passed through and left to their own validation.
+func ProjectWritable() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := uuid.Parse(c.Param("project_id"))
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		archived, err := services.IsProjectArchived(databases.DB.WithContext(c.Request.Context()), projectID)
+		if err != nil {
+			logger.LogError("Failed to check project archive status.", logrus.Fields{"error": err.Error(), "project_id": projectID.String()})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+		if archived {
+			models.SendErrorResponse(c, http.StatusConflict, "Project is archived and read-only. Unarchive it to make changes.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,126 @@
+package middlewares
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/middlewares"
+	"github.com/san-data-systems/common/models"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Context keys set by Authenticate for the handlers that follow it. EmailKey is the key the JWT
+// middleware stores the user's email under, which utils.GetEmailFromContext reads.
+const (
+	EmailKey         = "email"
+	GroupsKey        = "groups"
+	AccessTokenIDKey = "access_token_id"
+)
+
+// groupSyncInterval is how long the team memberships synced from a user's identity provider groups are
+// trusted before they are synced again, as long as the groups in the user's tokens do not change.
+const groupSyncInterval = 5 * time.Minute
+
+// groupSyncs records, by email, the groups last synced for OIDC users and when.
+var groupSyncs sync.Map
+
+// groupSync is an entry of groupSyncs.
+type groupSync struct {
+	groups   string
+	syncedAt time.Time
+}
+
+// Authenticate authenticates a request with a personal access token, an OIDC token or a user JWT. Bearer
+// tokens carrying pmv1.AccessTokenPrefix are looked up as access tokens and limited to their scopes,
+// tokens issued by the configured OIDC identity provider are verified against its JWKS, and any other
+// token is handed to the JWT middleware.
+func Authenticate() gin.HandlerFunc {
+	jwt := middlewares.JWTMiddleware()
+	return func(c *gin.Context) {
+		token, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		token = strings.TrimSpace(token)
+		if !found {
+			jwt(c)
+			return
+		}
+		if !strings.HasPrefix(token, pmv1.AccessTokenPrefix) {
+			if verifier := services.CurrentOIDCVerifier(); verifier != nil && verifier.Handles(token) {
+				authenticateOIDC(c, verifier, token)
+				return
+			}
+			jwt(c)
+			return
+		}
+
+		accessToken, err := services.AuthenticateAccessToken(databases.DB.WithContext(c.Request.Context()), token, c.ClientIP())
+		if err == services.ErrInvalidAccessToken {
+			models.SendErrorResponse(c, http.StatusUnauthorized, "Invalid, expired or revoked access token.")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logger.LogError("Failed to authenticate access token.", logrus.Fields{"error": err.Error()})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+		if !services.AccessTokenScopeAllows(accessToken.Scopes, c.Request.Method) {
+			models.SendErrorResponse(c, http.StatusForbidden, "The access token does not have the scope required for this request.")
+			c.Abort()
+			return
+		}
+
+		c.Set(EmailKey, accessToken.Email)
+		c.Set(AccessTokenIDKey, accessToken.ID)
+		c.Next()
+	}
+}
+
+// AuthenticatedByAccessToken reports whether a request was authenticated with an access token rather
+// than a user JWT.
+func AuthenticatedByAccessToken(c *gin.Context) bool {
+	_, exists := c.Get(AccessTokenIDKey)
+	return exists
+}
+
+// authenticateOIDC verifies a token of the OIDC identity provider and syncs the user's group mappings.
+func authenticateOIDC(c *gin.Context, verifier *services.OIDCVerifier, token string) {
+	identity, err := verifier.Verify(c.Request.Context(), token)
+	if err != nil {
+		logger.LogWarning("Rejected OIDC token.", logrus.Fields{"error": err.Error()})
+		models.SendErrorResponse(c, http.StatusUnauthorized, "Invalid or expired token.")
+		c.Abort()
+		return
+	}
+
+	groups := append([]string(nil), identity.Groups...)
+	sort.Strings(groups)
+	fingerprint := strings.Join(groups, "\n")
+	key := strings.ToLower(identity.Email)
+	if last, ok := groupSyncs.Load(key); !ok || last.(groupSync).groups != fingerprint || time.Since(last.(groupSync).syncedAt) >= groupSyncInterval {
+		err := databases.DB.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+			return services.SyncGroupTeams(tx, identity.Email, identity.Groups)
+		})
+		if err != nil {
+			logger.LogError("Failed to sync OIDC groups.", logrus.Fields{"error": err.Error(), "email": identity.Email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+		groupSyncs.Store(key, groupSync{groups: fingerprint, syncedAt: time.Now()})
+	}
+
+	c.Set(EmailKey, identity.Email)
+	c.Set(GroupsKey, identity.Groups)
+	c.Next()
+}
@@ -0,0 +1,59 @@
+package middlewares
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/middlewares"
+)
+
+// corsAllowedOrigins holds the origins set by SetCORSAllowedOrigins, read on every request.
+var corsAllowedOrigins atomic.Pointer[[]string]
+
+// SetCORSAllowedOrigins sets the origins allowed to make cross-origin requests, "*" allowing any origin
+// to make requests without credentials. An empty list restores the policy of the common CORS middleware.
+func SetCORSAllowedOrigins(origins []string) {
+	corsAllowedOrigins.Store(&origins)
+}
+
+// CORS answers cross-origin requests from the origins set by SetCORSAllowedOrigins, which may change
+// while the service runs. Until origins are set it defers to the common CORS middleware.
+func CORS() gin.HandlerFunc {
+	fallback := middlewares.CORSMiddleware()
+	return func(c *gin.Context) {
+		origins := corsAllowedOrigins.Load()
+		if origins == nil || len(*origins) == 0 {
+			fallback(c)
+			return
+		}
+
+		header := c.Writer.Header()
+		header.Add("Vary", "Origin")
+		origin := c.GetHeader("Origin")
+		allowed := origin != "" && slices.Contains(*origins, origin)
+		if allowed {
+			header.Set("Access-Control-Allow-Origin", origin)
+			header.Set("Access-Control-Allow-Credentials", "true")
+		} else if origin != "" && slices.Contains(*origins, "*") {
+			// Any origin is allowed, but never with the credentials of the user, which browsers would
+			// otherwise send to the API from every site.
+			allowed = true
+			header.Set("Access-Control-Allow-Origin", "*")
+		}
+		if allowed {
+			header.Set("Access-Control-Allow-Headers", "Authorization, Content-Type, X-Organization, X-Request-ID, traceparent, tracestate")
+			header.Set("Access-Control-Allow-Methods", strings.Join([]string{
+				http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete, http.MethodOptions,
+			}, ", "))
+			header.Set("Access-Control-Expose-Headers", "Content-Disposition, X-Request-ID")
+		}
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,53 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCORSAllowedOrigins(t *testing.T) {
+	t.Cleanup(func() { SetCORSAllowedOrigins(nil) })
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(CORS())
+	r.GET("/projects", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/projects", nil)
+		req.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	SetCORSAllowedOrigins([]string{"https://app.example.com"})
+	if w := request(http.MethodGet, "https://app.example.com"); w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" {
+		t.Errorf("allowed origin: %d, %q", w.Code, w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if w := request(http.MethodGet, "https://evil.example.com"); w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("other origin allowed: %q", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if w := request(http.MethodOptions, "https://app.example.com"); w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Methods") == "" {
+		t.Errorf("preflight: %d, %q", w.Code, w.Header().Get("Access-Control-Allow-Methods"))
+	}
+
+	// A change applies to the next request without rebuilding the router.
+	SetCORSAllowedOrigins([]string{"*"})
+	w := request(http.MethodGet, "https://evil.example.com")
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("any origin: %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("any origin allowed with credentials: %q", got)
+	}
+
+	// A listed origin keeps its credentials next to the wildcard.
+	SetCORSAllowedOrigins([]string{"*", "https://app.example.com"})
+	w = request(http.MethodGet, "https://app.example.com")
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" || w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+		t.Errorf("listed origin with wildcard: %q, %q", w.Header().Get("Access-Control-Allow-Origin"), w.Header().Get("Access-Control-Allow-Credentials"))
+	}
+}
@@ -0,0 +1,110 @@
+package middlewares
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// Context keys set by ResolveOrganization for the handlers that follow it.
+const (
+	OrganizationIDKey   = "organization_id"
+	OrganizationRoleKey = "organization_role"
+)
+
+// ResolveOrganization selects the organization a request acts in and rejects users who do not belong
+// to it. The organization is taken from the X-Organization header (an ID or slug), then from the
+// subdomain of the host when ORGANIZATION_DOMAIN is set, and finally defaults to the only organization
+// of the user. Unknown organizations and organizations the user does not belong to get a 404 so that
+// their existence is not disclosed.
+func ResolveOrganization() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		email, valid := utils.GetEmailFromContext(c)
+		if !valid {
+			c.Abort()
+			return
+		}
+
+		var organization pmv1.Organization
+		var err error
+		if reference := organizationReference(c); reference != "" {
+			organization, err = services.FindOrganization(databases.DB.WithContext(c.Request.Context()), reference)
+		} else {
+			organization, err = services.OnlyOrganization(databases.DB.WithContext(c.Request.Context()), email)
+			if err == services.ErrAmbiguousOrganization {
+				models.SendErrorResponse(c, http.StatusBadRequest, "You belong to several organizations. Select one with the "+pmv1.OrganizationHeader+" header.")
+				c.Abort()
+				return
+			}
+		}
+		if err == gorm.ErrRecordNotFound {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			c.Abort()
+			return
+		}
+		if err != nil {
+			logger.LogError("Failed to resolve organization.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+
+		role, err := services.OrganizationRole(databases.DB.WithContext(c.Request.Context()), organization.ID, email)
+		if err != nil {
+			logger.LogError("Failed to fetch organization role.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+		if role == "" {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			c.Abort()
+			return
+		}
+
+		c.Set(OrganizationIDKey, organization.ID)
+		c.Set(OrganizationRoleKey, role)
+		c.Next()
+	}
+}
+
+// OrganizationID returns the organization resolved for the request by ResolveOrganization, or uuid.Nil
+// when the route is not scoped to an organization.
+func OrganizationID(c *gin.Context) uuid.UUID {
+	organizationID, _ := c.Get(OrganizationIDKey)
+	id, _ := organizationID.(uuid.UUID)
+	return id
+}
+
+// organizationReference returns the organization requested in the header or as a subdomain of
+// ORGANIZATION_DOMAIN, if any.
+func organizationReference(c *gin.Context) string {
+	if reference := strings.TrimSpace(c.GetHeader(pmv1.OrganizationHeader)); reference != "" {
+		return reference
+	}
+
+	domain := strings.ToLower(strings.TrimPrefix(os.Getenv("ORGANIZATION_DOMAIN"), "."))
+	if domain == "" {
+		return ""
+	}
+	host := strings.ToLower(c.Request.Host)
+	if i := strings.LastIndex(host, ":"); i >= 0 && !strings.Contains(host[i:], "]") {
+		host = host[:i]
+	}
+	if subdomain := strings.TrimSuffix(host, "."+domain); subdomain != host && !strings.Contains(subdomain, ".") {
+		return subdomain
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/common/errors"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/common/models"
+	"github.com/san-data-systems/common/utils"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/sirupsen/logrus"
+)
+
+// Context keys set by RequirePermission for the handlers that follow it.
+const (
+	ProjectRoleKey        = "project_role"
+	ProjectPermissionsKey = "project_permissions"
+)
+
+// RequirePermission rejects requests from users who do not hold a permission in the project of the
+// project_id parameter. Non-members, and requests for a project outside the organization resolved by
+// ResolveOrganization, get a 404 so that the existence of a project is not disclosed, while members
+// lacking the permission get a 403. Requests without a valid project_id parameter get a 400.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		projectID, err := uuid.Parse(c.Param("project_id"))
+		if err != nil {
+			models.SendErrorResponse(c, http.StatusBadRequest, "Invalid project ID.")
+			c.Abort()
+			return
+		}
+
+		email, valid := utils.GetEmailFromContext(c)
+		if !valid {
+			c.Abort()
+			return
+		}
+
+		// Projects of other organizations are reported as missing
+		if organizationID := OrganizationID(c); organizationID != uuid.Nil {
+			projectOrganizationID, err := services.ProjectOrganizationID(databases.DB.WithContext(c.Request.Context()), projectID)
+			if err != nil {
+				logger.LogError("Failed to fetch project organization.", logrus.Fields{"error": err.Error(), "email": email})
+				models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+				c.Abort()
+				return
+			}
+			if projectOrganizationID != organizationID {
+				models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+				c.Abort()
+				return
+			}
+		}
+
+		role, permissions, err := services.ProjectPermissions(databases.DB.WithContext(c.Request.Context()), projectID, email)
+		if err != nil {
+			logger.LogError("Failed to fetch project member permissions.", logrus.Fields{"error": err.Error(), "email": email})
+			models.SendErrorResponse(c, http.StatusInternalServerError, errors.ErrInternalServer)
+			c.Abort()
+			return
+		}
+		if role == "" {
+			models.SendErrorResponse(c, http.StatusNotFound, errors.ErrRecordNotFound)
+			c.Abort()
+			return
+		}
+		if !services.Grants(permissions, permission) {
+			models.SendErrorResponse(c, http.StatusForbidden, "You do not have permission to perform this action.")
+			c.Abort()
+			return
+		}
+
+		c.Set(ProjectRoleKey, role)
+		c.Set(ProjectPermissionsKey, permissions)
+		c.Next()
+	}
+}
@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestRequirePermissionRejectsInvalidProjectID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/project/:project_id/board", RequirePermission(pmv1.PermissionIssueView), func(c *gin.Context) {
+		t.Error("handler reached with an invalid project ID")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/project/not-a-uuid/board", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+}
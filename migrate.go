@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/project-management-api/migrations"
+)
+
+// migrateUsage is the synopsis of the migrate command.
+const migrateUsage = "migrate [status | up | down [--steps <n>] | to <version>]"
+
+// runMigrate shows or changes the version of the schema owned by the service. It applies every
+// pending migration when no subcommand is given.
+func runMigrate(args []string) error {
+	subcommand := "up"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	flags := flag.NewFlagSet("migrate "+subcommand, flag.ExitOnError)
+	flags.Usage = usageFor(flags, migrateUsage, "Show the applied migrations (status), apply every pending migration (up),\nrevert the latest ones (down) or migrate up or down to a version (to, 0 reverts everything).")
+	steps := 1
+	if subcommand == "down" {
+		flags.IntVar(&steps, "steps", 1, "number of migrations to revert")
+	}
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var version int64
+	switch subcommand {
+	case "status", "up", "down":
+		if flags.NArg() != 0 {
+			flags.Usage()
+			return fmt.Errorf("unexpected arguments %v", flags.Args())
+		}
+	case "to":
+		if flags.NArg() != 1 {
+			flags.Usage()
+			return fmt.Errorf("expected a version")
+		}
+		parsed, err := strconv.ParseInt(flags.Arg(0), 10, 64)
+		if err != nil || parsed < 0 {
+			return fmt.Errorf("invalid version %q", flags.Arg(0))
+		}
+		version = parsed
+	default:
+		flags.Usage()
+		return fmt.Errorf("unknown subcommand %q", subcommand)
+	}
+
+	databases.InitPostgresDB()
+	db, err := databases.DB.DB()
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	if subcommand == "status" {
+		return printMigrationStatus(ctx, db)
+	}
+
+	var applied, reverted []migrations.Migration
+	switch subcommand {
+	case "up":
+		applied, err = migrations.Up(ctx, db)
+	case "down":
+		reverted, err = migrations.Down(ctx, db, steps)
+	case "to":
+		applied, reverted, err = migrations.To(ctx, db, version)
+	}
+	for _, migration := range reverted {
+		fmt.Printf("Reverted %04d_%s\n", migration.Version, migration.Name)
+	}
+	for _, migration := range applied {
+		fmt.Printf("Applied %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		return err
+	}
+	switch {
+	case subcommand == "down" && len(reverted) == 0:
+		fmt.Println("Nothing to revert.")
+	case len(applied) == 0 && len(reverted) == 0:
+		fmt.Println("Schema is up to date.")
+	}
+	return nil
+}
+
+// printMigrationStatus prints every migration with the time it was applied.
+func printMigrationStatus(ctx context.Context, db *sql.DB) error {
+	statuses, err := migrations.Statuses(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Local().Format("2006-01-02 15:04:05")
+		}
+		name := status.Name
+		if status.Missing {
+			name = "(unknown to this binary)"
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\n", status.Version, name, appliedAt)
+	}
+	return w.Flush()
+}
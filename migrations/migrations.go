@@ -0,0 +1,290 @@
+// Package migrations versions the schema of the tables owned by the service. Migrations are pairs of
+// SQL files embedded in the binary, sql/<version>_<name>.up.sql and sql/<version>_<name>.down.sql, applied
+// in version order. Applied versions are recorded in the schema_migrations table, and a Postgres
+// advisory lock keeps concurrent instances from migrating at the same time.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// lockID is the key of the advisory lock held while migrating.
+const lockID int64 = 7_261_146_502_117_925
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// fileName matches the names of migration files.
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status is a migration with the time it was applied, nil when it is pending.
+type Status struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at"`
+	// Missing is set for versions recorded as applied that the binary has no files for, which happens
+	// when an older binary runs against a database migrated by a newer one.
+	Missing bool `json:"missing,omitempty"`
+}
+
+// Load returns the embedded migrations in version order.
+func Load() ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("unexpected migration file %s", entry.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := fs.ReadFile(files, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has files named %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Latest returns the version of the newest embedded migration.
+func Latest() (int64, error) {
+	migrations, err := Load()
+	if err != nil || len(migrations) == 0 {
+		return 0, err
+	}
+	return migrations[len(migrations)-1].Version, nil
+}
+
+// Statuses lists the embedded migrations with the time they were applied, followed by the applied
+// versions the binary does not know.
+func Statuses(ctx context.Context, db *sql.DB) ([]Status, error) {
+	var statuses []Status
+	err := withLock(ctx, db, func(conn *sql.Conn, migrations []Migration, applied map[int64]time.Time) error {
+		known := map[int64]bool{}
+		for _, migration := range migrations {
+			known[migration.Version] = true
+			status := Status{Version: migration.Version, Name: migration.Name}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		for version, appliedAt := range applied {
+			if !known[version] {
+				appliedAt := appliedAt
+				statuses = append(statuses, Status{Version: version, AppliedAt: &appliedAt, Missing: true})
+			}
+		}
+		sort.SliceStable(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+		return nil
+	})
+	return statuses, err
+}
+
+// Up applies every pending migration and returns the ones applied.
+func Up(ctx context.Context, db *sql.DB) ([]Migration, error) {
+	latest, err := Latest()
+	if err != nil {
+		return nil, err
+	}
+	applied, _, err := To(ctx, db, latest)
+	return applied, err
+}
+
+// Down reverts the given number of most recently applied migrations and returns the ones reverted.
+func Down(ctx context.Context, db *sql.DB, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := withLock(ctx, db, func(conn *sql.Conn, migrations []Migration, applied map[int64]time.Time) error {
+		for i := len(migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			if _, ok := applied[migrations[i].Version]; !ok {
+				continue
+			}
+			if err := revert(ctx, conn, migrations[i]); err != nil {
+				return err
+			}
+			reverted = append(reverted, migrations[i])
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// To migrates up or down to the given version: migrations up to and including it are applied and later
+// ones are reverted. Version 0 reverts every migration. It returns the migrations applied, in the order
+// they were applied, and the ones reverted, in the order they were reverted.
+func To(ctx context.Context, db *sql.DB, version int64) (applied, reverted []Migration, err error) {
+	err = withLock(ctx, db, func(conn *sql.Conn, migrations []Migration, appliedAt map[int64]time.Time) error {
+		known := version == 0
+		for _, migration := range migrations {
+			known = known || migration.Version == version
+		}
+		if !known {
+			return fmt.Errorf("unknown migration version %d", version)
+		}
+		for appliedVersion := range appliedAt {
+			if appliedVersion > version && !hasVersion(migrations, appliedVersion) {
+				return fmt.Errorf("version %d is applied but this binary has no migration for it", appliedVersion)
+			}
+		}
+
+		for i := len(migrations) - 1; i >= 0; i-- {
+			if _, ok := appliedAt[migrations[i].Version]; ok && migrations[i].Version > version {
+				if err := revert(ctx, conn, migrations[i]); err != nil {
+					return err
+				}
+				reverted = append(reverted, migrations[i])
+			}
+		}
+		for _, migration := range migrations {
+			if _, ok := appliedAt[migration.Version]; !ok && migration.Version <= version {
+				if err := apply(ctx, conn, migration); err != nil {
+					return err
+				}
+				applied = append(applied, migration)
+			}
+		}
+		return nil
+	})
+	return applied, reverted, err
+}
+
+// withLock runs a function holding the migration advisory lock on a dedicated connection, after making
+// sure the schema_migrations table exists. The function receives the embedded migrations and the
+// versions applied so far.
+func withLock(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn, migrations []Migration, applied map[int64]time.Time) error) error {
+	migrations, err := Load()
+	if err != nil {
+		return err
+	}
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return fmt.Errorf("acquiring migration lock: %w", err)
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+	}()
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name varchar(255) NOT NULL,
+		applied_at timestamptz NOT NULL DEFAULT NOW()
+	)`); err != nil {
+		return fmt.Errorf("creating schema_migrations: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return err
+	}
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			rows.Close()
+			return err
+		}
+		applied[version] = appliedAt
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	return fn(conn, migrations, applied)
+}
+
+// apply runs the up file of a migration and records it, in one transaction.
+func apply(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return inTransaction(ctx, conn, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, migration.Up); err != nil {
+			return fmt.Errorf("applying migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+		_, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+		return err
+	})
+}
+
+// revert runs the down file of a migration and forgets it, in one transaction.
+func revert(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	return inTransaction(ctx, conn, func(tx *sql.Tx) error {
+		if migration.Down != "" {
+			if _, err := tx.ExecContext(ctx, migration.Down); err != nil {
+				return fmt.Errorf("reverting migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		_, err := tx.ExecContext(ctx, "DELETE FROM schema_migrations WHERE version = $1", migration.Version)
+		return err
+	})
+}
+
+// inTransaction runs a function in a transaction, committing it when the function succeeds.
+func inTransaction(ctx context.Context, conn *sql.Conn, fn func(tx *sql.Tx) error) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// hasVersion reports whether a migration with the given version is embedded.
+func hasVersion(migrations []Migration, version int64) bool {
+	for _, migration := range migrations {
+		if migration.Version == version {
+			return true
+		}
+	}
+	return false
+}
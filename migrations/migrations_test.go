@@ -0,0 +1,285 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	v1 "github.com/san-data-systems/common/models/v1"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestLoad(t *testing.T) {
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(migrations) == 0 {
+		t.Fatal("no migrations embedded")
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %d_%s: want version %d, versions must follow each other", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+	}
+
+	latest, err := Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := migrations[len(migrations)-1].Version; latest != want {
+		t.Errorf("Latest() = %d, want %d", latest, want)
+	}
+}
+
+// testDB returns a connection pool to a fresh schema of the scratch Postgres database named by
+// TEST_DATABASE_URL, holding the shared tables of the common module the migrations read. The schema is
+// dropped when the test ends. Tests using it are skipped when TEST_DATABASE_URL is unset.
+func testDB(t *testing.T) *sql.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer admin.Close()
+	schema := fmt.Sprintf("migrations_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		admin, err := sql.Open("pgx", dsn)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer admin.Close()
+		if _, err := admin.Exec("DROP SCHEMA " + schema + " CASCADE"); err != nil {
+			t.Error(err)
+		}
+	})
+
+	if strings.Contains(dsn, "://") {
+		parsed, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		query := parsed.Query()
+		query.Set("search_path", schema)
+		parsed.RawQuery = query.Encode()
+		dsn = parsed.String()
+	} else {
+		dsn += " search_path=" + schema
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&v1.Client{}, &v1.Project{}, &v1.ProjectMember{}); err != nil {
+		t.Fatalf("creating the shared tables: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return sqlDB
+}
+
+// appliedVersions returns the versions recorded in schema_migrations, in order.
+func appliedVersions(t *testing.T, db *sql.DB) []int64 {
+	t.Helper()
+	statuses, err := Statuses(context.Background(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, status := range statuses {
+		if status.AppliedAt != nil {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+// tableExists reports whether a table exists in the test schema.
+func tableExists(t *testing.T, db *sql.DB, table string) bool {
+	t.Helper()
+	var exists bool
+	if err := db.QueryRow("SELECT to_regclass($1) IS NOT NULL", table).Scan(&exists); err != nil {
+		t.Fatal(err)
+	}
+	return exists
+}
+
+// migrationVersions returns the versions of migrations.
+func migrationVersions(migrations []Migration) []int64 {
+	versions := []int64{}
+	for _, migration := range migrations {
+		versions = append(versions, migration.Version)
+	}
+	return versions
+}
+
+// versionsUpTo returns the versions from 1 to n.
+func versionsUpTo(n int64) []int64 {
+	versions := []int64{}
+	for version := int64(1); version <= n; version++ {
+		versions = append(versions, version)
+	}
+	return versions
+}
+
+// equalVersions reports whether two lists of versions are the same, nil being the empty list.
+func equalVersions(a, b []int64) bool {
+	return fmt.Sprint(a) == fmt.Sprint(b)
+}
+
+func TestUpDownAndTo(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	latest, err := Latest()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest < 2 {
+		t.Skip("needs at least two migrations")
+	}
+
+	applied, err := Up(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := migrationVersions(applied); !equalVersions(got, versionsUpTo(latest)) {
+		t.Fatalf("Up applied %v, want %v", got, versionsUpTo(latest))
+	}
+	if applied, err := Up(ctx, db); err != nil || len(applied) != 0 {
+		t.Fatalf("Up on an up to date schema applied %v, %v", migrationVersions(applied), err)
+	}
+
+	reverted, err := Down(ctx, db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := migrationVersions(reverted); !equalVersions(got, []int64{latest}) {
+		t.Fatalf("Down(1) reverted %v, want [%d]", got, latest)
+	}
+	if got := appliedVersions(t, db); !equalVersions(got, versionsUpTo(latest-1)) {
+		t.Fatalf("applied after Down(1) = %v, want %v", got, versionsUpTo(latest-1))
+	}
+
+	applied, reverted, err = To(ctx, db, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wantReverted []int64
+	for version := latest - 1; version > 1; version-- {
+		wantReverted = append(wantReverted, version)
+	}
+	if len(applied) != 0 || !equalVersions(migrationVersions(reverted), wantReverted) {
+		t.Fatalf("To(1) applied %v and reverted %v, want none and %v", migrationVersions(applied), migrationVersions(reverted), wantReverted)
+	}
+
+	applied, reverted, err = To(ctx, db, latest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != 0 || !equalVersions(migrationVersions(applied), versionsUpTo(latest)[1:]) {
+		t.Fatalf("To(%d) applied %v and reverted %v", latest, migrationVersions(applied), migrationVersions(reverted))
+	}
+
+	if _, _, err := To(ctx, db, latest+1); err == nil {
+		t.Fatal("To an unknown version succeeded")
+	}
+
+	_, reverted, err = To(ctx, db, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reverted) != int(latest) || reverted[0].Version != latest || reverted[len(reverted)-1].Version != 1 {
+		t.Fatalf("To(0) reverted %v, want every migration from the latest", migrationVersions(reverted))
+	}
+	if got := appliedVersions(t, db); len(got) != 0 {
+		t.Fatalf("applied after To(0) = %v, want none", got)
+	}
+	if tableExists(t, db, "state_wip_limits") {
+		t.Fatal("state_wip_limits remains after reverting the baseline")
+	}
+	if !tableExists(t, db, "projects") {
+		t.Fatal("reverting dropped a table of the common module")
+	}
+
+	if reverted, err := Down(ctx, db, 1); err != nil || len(reverted) != 0 {
+		t.Fatalf("Down with nothing applied reverted %v, %v", migrationVersions(reverted), err)
+	}
+}
+
+func TestBaselineIsIdempotent(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	migrations, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	baseline := migrations[0]
+
+	// A database created by AutoMigrate before versioned migrations, with the table name GORM derived.
+	if _, err := db.Exec(`CREATE TABLE state_w_ip_limits (
+		id uuid DEFAULT gen_random_uuid() PRIMARY KEY,
+		state_id uuid NOT NULL,
+		project_id uuid NOT NULL,
+		"limit" bigint NOT NULL,
+		mode varchar(10) NOT NULL,
+		updated_by varchar(255) NOT NULL,
+		created_at timestamptz,
+		updated_at timestamptz
+	)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("CREATE UNIQUE INDEX idx_state_w_ip_limits_state_id ON state_w_ip_limits (state_id)"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.Exec("INSERT INTO state_w_ip_limits (state_id, project_id, \"limit\", mode, updated_by) VALUES (gen_random_uuid(), gen_random_uuid(), 3, 'hard', 'jane@example.com')"); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if _, err := db.Exec(baseline.Up); err != nil {
+			t.Fatalf("running the baseline a %d. time: %v", i+1, err)
+		}
+	}
+	if tableExists(t, db, "state_w_ip_limits") {
+		t.Fatal("state_w_ip_limits was not renamed")
+	}
+	var limits int
+	if err := db.QueryRow("SELECT COUNT(*) FROM state_wip_limits").Scan(&limits); err != nil {
+		t.Fatal(err)
+	}
+	if limits != 1 {
+		t.Fatalf("state_wip_limits has %d rows, want the 1 row of state_w_ip_limits", limits)
+	}
+
+	// The schema created outside the runner is adopted as is.
+	applied, err := Up(ctx, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != len(migrations) {
+		t.Fatalf("Up applied %v, want every migration", migrationVersions(applied))
+	}
+}
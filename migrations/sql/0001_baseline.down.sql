@@ -0,0 +1,34 @@
+-- Drops every table owned by the service. Tables of the common module are left alone.
+
+DROP TABLE IF EXISTS "group_mappings";
+DROP TABLE IF EXISTS "service_accounts";
+DROP TABLE IF EXISTS "access_tokens";
+DROP TABLE IF EXISTS "organization_projects";
+DROP TABLE IF EXISTS "organization_clients";
+DROP TABLE IF EXISTS "organization_members";
+DROP TABLE IF EXISTS "organizations";
+DROP TABLE IF EXISTS "project_teams";
+DROP TABLE IF EXISTS "team_members";
+DROP TABLE IF EXISTS "teams";
+DROP TABLE IF EXISTS "project_invitations";
+DROP TABLE IF EXISTS "issue_comments";
+DROP TABLE IF EXISTS "custom_roles";
+DROP TABLE IF EXISTS "project_archives";
+DROP TABLE IF EXISTS "project_templates";
+DROP TABLE IF EXISTS "saved_views";
+DROP TABLE IF EXISTS "state_wip_limits";
+DROP TABLE IF EXISTS "state_transitions";
+DROP TABLE IF EXISTS "project_state_categories";
+DROP TABLE IF EXISTS "time_offs";
+DROP TABLE IF EXISTS "holidays";
+DROP TABLE IF EXISTS "working_calendars";
+DROP TABLE IF EXISTS "member_capacities";
+DROP TABLE IF EXISTS "notifications";
+DROP TABLE IF EXISTS "project_budget_alerts";
+DROP TABLE IF EXISTS "project_budgets";
+DROP TABLE IF EXISTS "invoice_lines";
+DROP TABLE IF EXISTS "invoices";
+DROP TABLE IF EXISTS "time_entry_billings";
+DROP TABLE IF EXISTS "project_member_rates";
+DROP TABLE IF EXISTS "project_billings";
+DROP TABLE IF EXISTS "client_billings";
@@ -0,0 +1,445 @@
+-- Baseline schema of the tables owned by the service, as previously created by GORM's AutoMigrate.
+-- Every statement is idempotent so that databases created before versioned migrations are adopted as is.
+
+CREATE TABLE IF NOT EXISTS "client_billings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "client_id" uuid NOT NULL,
+    "hourly_rate" numeric(12,2) NOT NULL DEFAULT 0,
+    "currency" varchar(3) NOT NULL DEFAULT 'USD',
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_client_billings_client_id" ON "client_billings" ("client_id");
+
+CREATE TABLE IF NOT EXISTS "project_billings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "hourly_rate" numeric(12,2),
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_billings_project_id" ON "project_billings" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_member_rates" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "hourly_rate" numeric(12,2) NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_member_rate" ON "project_member_rates" ("project_id","email");
+
+CREATE TABLE IF NOT EXISTS "time_entry_billings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "time_entry_id" uuid NOT NULL,
+    "project_id" uuid NOT NULL,
+    "is_billable" boolean NOT NULL DEFAULT true,
+    "approved_by" varchar(255),
+    "approved_at" timestamptz,
+    "invoice_id" uuid,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_time_entry_billings_invoice_id" ON "time_entry_billings" ("invoice_id");
+CREATE INDEX IF NOT EXISTS "idx_time_entry_billings_project_id" ON "time_entry_billings" ("project_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_time_entry_billings_time_entry_id" ON "time_entry_billings" ("time_entry_id");
+
+CREATE TABLE IF NOT EXISTS "invoices" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "client_id" uuid NOT NULL,
+    "number" varchar(50) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'draft',
+    "currency" varchar(3) NOT NULL,
+    "period_start" date NOT NULL,
+    "period_end" date NOT NULL,
+    "total_hours" numeric(12,2) NOT NULL DEFAULT 0,
+    "total_amount" numeric(14,2) NOT NULL DEFAULT 0,
+    "created_by" varchar(255) NOT NULL,
+    "issued_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_invoices_number" ON "invoices" ("number");
+CREATE INDEX IF NOT EXISTS "idx_invoices_client_id" ON "invoices" ("client_id");
+
+CREATE TABLE IF NOT EXISTS "invoice_lines" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "invoice_id" uuid NOT NULL,
+    "time_entry_id" uuid NOT NULL,
+    "project_id" uuid NOT NULL,
+    "issue_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "date" date NOT NULL,
+    "description" text,
+    "hours" numeric(10,2) NOT NULL,
+    "hourly_rate" numeric(12,2) NOT NULL,
+    "amount" numeric(14,2) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_invoices_lines" FOREIGN KEY ("invoice_id") REFERENCES "invoices"("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_invoice_lines_invoice_id" ON "invoice_lines" ("invoice_id");
+
+CREATE TABLE IF NOT EXISTS "project_budgets" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "budget_hours" numeric(12,2),
+    "budget_amount" numeric(14,2),
+    "thresholds" integer[],
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_budgets_project_id" ON "project_budgets" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_budget_alerts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "kind" varchar(20) NOT NULL,
+    "threshold" bigint NOT NULL,
+    "consumed" numeric(14,2) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_budget_alert" ON "project_budget_alerts" ("project_id","kind","threshold");
+
+CREATE TABLE IF NOT EXISTS "notifications" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "project_id" uuid,
+    "type" varchar(50) NOT NULL,
+    "title" varchar(255) NOT NULL,
+    "message" text,
+    "read_at" timestamptz,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_notifications_project_id" ON "notifications" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_notifications_email" ON "notifications" ("email");
+
+CREATE TABLE IF NOT EXISTS "member_capacities" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "weekly_hours" numeric(5,2) NOT NULL,
+    "updated_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_member_capacities_email" ON "member_capacities" ("email");
+
+CREATE TABLE IF NOT EXISTS "working_calendars" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid,
+    "email" varchar(255) NOT NULL DEFAULT '',
+    "working_days" integer[] NOT NULL,
+    "hours_per_day" numeric(4,2) NOT NULL,
+    "updated_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+-- Added when calendars were scoped to organizations, replacing the service-wide unique email
+ALTER TABLE "working_calendars" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+DROP INDEX IF EXISTS "idx_working_calendars_email";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_working_calendar_organization_email" ON "working_calendars" ("organization_id","email");
+
+CREATE TABLE IF NOT EXISTS "holidays" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid,
+    "date" date NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+-- Added when holidays were scoped to organizations, replacing the service-wide unique date
+ALTER TABLE "holidays" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+DROP INDEX IF EXISTS "idx_holidays_date";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_holiday_organization_date" ON "holidays" ("organization_id","date");
+
+CREATE TABLE IF NOT EXISTS "time_offs" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "email" varchar(255) NOT NULL,
+    "start_date" date NOT NULL,
+    "end_date" date NOT NULL,
+    "reason" varchar(255),
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_time_offs_email" ON "time_offs" ("email");
+
+CREATE TABLE IF NOT EXISTS "project_state_categories" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "state_id" uuid NOT NULL,
+    "project_id" uuid NOT NULL,
+    "category" varchar(20) NOT NULL,
+    "updated_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_project_state_categories_project_id" ON "project_state_categories" ("project_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_state_categories_state_id" ON "project_state_categories" ("state_id");
+
+CREATE TABLE IF NOT EXISTS "state_transitions" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "from_state_id" uuid,
+    "to_state_id" uuid NOT NULL,
+    "roles" text[],
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_state_transitions_project_id" ON "state_transitions" ("project_id");
+
+-- GORM named this table state_w_ip_limits before the model named it explicitly.
+DO $$
+BEGIN
+    IF to_regclass('state_w_ip_limits') IS NOT NULL AND to_regclass('state_wip_limits') IS NULL THEN
+        ALTER TABLE "state_w_ip_limits" RENAME TO "state_wip_limits";
+        ALTER INDEX IF EXISTS "idx_state_w_ip_limits_project_id" RENAME TO "idx_state_wip_limits_project_id";
+        ALTER INDEX IF EXISTS "idx_state_w_ip_limits_state_id" RENAME TO "idx_state_wip_limits_state_id";
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS "state_wip_limits" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "state_id" uuid NOT NULL,
+    "project_id" uuid NOT NULL,
+    "limit" bigint NOT NULL,
+    "mode" varchar(10) NOT NULL,
+    "updated_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_state_wip_limits_project_id" ON "state_wip_limits" ("project_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_state_wip_limits_state_id" ON "state_wip_limits" ("state_id");
+
+CREATE TABLE IF NOT EXISTS "saved_views" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "filters" jsonb NOT NULL DEFAULT '{}',
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_saved_views_project_id" ON "saved_views" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_templates" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid,
+    "name" varchar(255) NOT NULL,
+    "description" text,
+    "source_project_id" uuid,
+    "definition" jsonb NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+-- Added when templates were scoped to organizations
+ALTER TABLE "project_templates" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+CREATE INDEX IF NOT EXISTS "idx_project_templates_deleted_at" ON "project_templates" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_project_templates_organization_id" ON "project_templates" ("organization_id");
+
+CREATE TABLE IF NOT EXISTS "project_archives" (
+    "project_id" uuid,
+    "previous_status" varchar(50),
+    "archived_by" varchar(255) NOT NULL,
+    "archived_at" timestamptz NOT NULL,
+    PRIMARY KEY ("project_id")
+);
+
+CREATE TABLE IF NOT EXISTS "custom_roles" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid,
+    "name" varchar(100) NOT NULL,
+    "description" text,
+    "permissions" text[] NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "updated_by" varchar(255),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+-- Added when custom roles were scoped to organizations, replacing the service-wide unique name
+ALTER TABLE "custom_roles" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+DROP INDEX IF EXISTS "idx_custom_roles_name";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_custom_role_organization_name" ON "custom_roles" ("organization_id","name");
+
+CREATE TABLE IF NOT EXISTS "issue_comments" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "issue_id" uuid NOT NULL,
+    "body" text NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "deleted_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_issue_comments_deleted_at" ON "issue_comments" ("deleted_at");
+CREATE INDEX IF NOT EXISTS "idx_issue_comments_issue_id" ON "issue_comments" ("issue_id");
+CREATE INDEX IF NOT EXISTS "idx_issue_comments_project_id" ON "issue_comments" ("project_id");
+
+CREATE TABLE IF NOT EXISTS "project_invitations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "role" varchar(100) NOT NULL,
+    "token_hash" varchar(64) NOT NULL,
+    "status" varchar(20) NOT NULL DEFAULT 'pending',
+    "invited_by" varchar(255) NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "responded_at" timestamptz,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_invitations_token_hash" ON "project_invitations" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_project_invitations_email" ON "project_invitations" ("email");
+CREATE INDEX IF NOT EXISTS "idx_project_invitations_project_id" ON "project_invitations" ("project_id");
+CREATE INDEX IF NOT EXISTS "idx_project_invitations_status" ON "project_invitations" ("status");
+
+CREATE TABLE IF NOT EXISTS "teams" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid,
+    "name" varchar(255) NOT NULL,
+    "description" text,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+-- Added when teams were scoped to organizations, replacing the service-wide unique name
+ALTER TABLE "teams" ADD COLUMN IF NOT EXISTS "organization_id" uuid;
+DROP INDEX IF EXISTS "idx_teams_name";
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_team_organization_name" ON "teams" ("organization_id","name");
+
+CREATE TABLE IF NOT EXISTS "team_members" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "team_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "added_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_team_members_email" ON "team_members" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_team_member" ON "team_members" ("team_id","email");
+
+CREATE TABLE IF NOT EXISTS "project_teams" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "project_id" uuid NOT NULL,
+    "team_id" uuid NOT NULL,
+    "role" varchar(100) NOT NULL,
+    "added_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_project_teams_team_id" ON "project_teams" ("team_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_project_team" ON "project_teams" ("project_id","team_id");
+
+CREATE TABLE IF NOT EXISTS "organizations" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" varchar(255) NOT NULL,
+    "slug" varchar(63) NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_organizations_slug" ON "organizations" ("slug");
+
+CREATE TABLE IF NOT EXISTS "organization_members" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "role" varchar(20) NOT NULL DEFAULT 'member',
+    "added_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_organization_members_email" ON "organization_members" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_organization_member" ON "organization_members" ("organization_id","email");
+
+CREATE TABLE IF NOT EXISTS "organization_clients" (
+    "client_id" uuid,
+    "organization_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("client_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_organization_clients_organization_id" ON "organization_clients" ("organization_id");
+
+CREATE TABLE IF NOT EXISTS "organization_projects" (
+    "project_id" uuid,
+    "organization_id" uuid NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("project_id")
+);
+CREATE INDEX IF NOT EXISTS "idx_organization_projects_organization_id" ON "organization_projects" ("organization_id");
+
+CREATE TABLE IF NOT EXISTS "access_tokens" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "name" varchar(255) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "service_account_id" uuid,
+    "token_hash" varchar(64) NOT NULL,
+    "hint" varchar(20) NOT NULL,
+    "scopes" text[] NOT NULL,
+    "expires_at" timestamptz NOT NULL,
+    "last_used_at" timestamptz,
+    "last_used_ip" varchar(64),
+    "revoked_at" timestamptz,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_access_tokens_token_hash" ON "access_tokens" ("token_hash");
+CREATE INDEX IF NOT EXISTS "idx_access_tokens_service_account_id" ON "access_tokens" ("service_account_id");
+CREATE INDEX IF NOT EXISTS "idx_access_tokens_email" ON "access_tokens" ("email");
+
+CREATE TABLE IF NOT EXISTS "service_accounts" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid NOT NULL,
+    "name" varchar(255) NOT NULL,
+    "email" varchar(255) NOT NULL,
+    "description" text,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_service_accounts_email" ON "service_accounts" ("email");
+CREATE INDEX IF NOT EXISTS "idx_service_accounts_organization_id" ON "service_accounts" ("organization_id");
+
+CREATE TABLE IF NOT EXISTS "group_mappings" (
+    "id" uuid DEFAULT gen_random_uuid(),
+    "organization_id" uuid NOT NULL,
+    "group" varchar(255) NOT NULL,
+    "team_id" uuid NOT NULL,
+    "created_by" varchar(255) NOT NULL,
+    "created_at" timestamptz,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_group_mappings_team_id" ON "group_mappings" ("team_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_group_mapping" ON "group_mappings" ("group","team_id");
+CREATE INDEX IF NOT EXISTS "idx_group_mappings_organization_id" ON "group_mappings" ("organization_id");
@@ -0,0 +1 @@
+-- The backfilled Owner memberships cannot be told apart from memberships granted since, so they are kept.
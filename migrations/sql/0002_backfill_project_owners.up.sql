@@ -0,0 +1,10 @@
+-- Gives the creator of every project without an Owner an explicit Owner membership.
+
+UPDATE project_members SET role = 'Owner', updated_at = NOW()
+FROM projects
+WHERE project_members.project_id = projects.id AND project_members.email = projects.created_by
+AND NOT EXISTS (SELECT 1 FROM project_members owners WHERE owners.project_id = projects.id AND owners.role = 'Owner');
+
+INSERT INTO project_members (id, project_id, email, role, created_at, updated_at)
+SELECT gen_random_uuid(), projects.id, projects.created_by, 'Owner', NOW(), NOW() FROM projects
+WHERE NOT EXISTS (SELECT 1 FROM project_members owners WHERE owners.project_id = projects.id AND owners.role = 'Owner');
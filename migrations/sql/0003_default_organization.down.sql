@@ -0,0 +1 @@
+-- Data assigned to the default organization stays there; reverting the baseline removes the organizations.
@@ -0,0 +1,55 @@
+-- Moves the clients, projects, templates, teams, custom roles, working calendars and holidays that do
+-- not belong to an organization yet to the default organization. When the default organization is
+-- created, everyone involved in its data becomes a member, and the owners of its projects become admins.
+
+DO $$
+DECLARE
+    default_organization uuid;
+    created boolean := false;
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM clients WHERE NOT EXISTS (SELECT 1 FROM organization_clients WHERE client_id = clients.id))
+        AND NOT EXISTS (SELECT 1 FROM projects WHERE NOT EXISTS (SELECT 1 FROM organization_projects WHERE project_id = projects.id))
+        AND NOT EXISTS (SELECT 1 FROM project_templates WHERE organization_id IS NULL)
+        AND NOT EXISTS (SELECT 1 FROM teams WHERE organization_id IS NULL)
+        AND NOT EXISTS (SELECT 1 FROM custom_roles WHERE organization_id IS NULL)
+        AND NOT EXISTS (SELECT 1 FROM working_calendars WHERE organization_id IS NULL)
+        AND NOT EXISTS (SELECT 1 FROM holidays WHERE organization_id IS NULL) THEN
+        RETURN;
+    END IF;
+
+    SELECT id INTO default_organization FROM organizations WHERE slug = 'default';
+    IF default_organization IS NULL THEN
+        INSERT INTO organizations (id, name, slug, created_by, created_at, updated_at)
+        VALUES (gen_random_uuid(), 'Default', 'default', 'system', NOW(), NOW())
+        RETURNING id INTO default_organization;
+        created := true;
+    END IF;
+
+    INSERT INTO organization_clients (client_id, organization_id, created_at)
+    SELECT id, default_organization, NOW() FROM clients
+    WHERE NOT EXISTS (SELECT 1 FROM organization_clients WHERE client_id = clients.id);
+
+    INSERT INTO organization_projects (project_id, organization_id, created_at)
+    SELECT id, default_organization, NOW() FROM projects
+    WHERE NOT EXISTS (SELECT 1 FROM organization_projects WHERE project_id = projects.id);
+
+    UPDATE project_templates SET organization_id = default_organization WHERE organization_id IS NULL;
+    UPDATE teams SET organization_id = default_organization WHERE organization_id IS NULL;
+    UPDATE custom_roles SET organization_id = default_organization WHERE organization_id IS NULL;
+    UPDATE working_calendars SET organization_id = default_organization WHERE organization_id IS NULL;
+    UPDATE holidays SET organization_id = default_organization WHERE organization_id IS NULL;
+
+    IF created THEN
+        INSERT INTO organization_members (id, organization_id, email, role, added_by, created_at, updated_at)
+        SELECT gen_random_uuid(), default_organization, MIN(email), CASE WHEN BOOL_OR(owner) THEN 'admin' ELSE 'member' END, 'system', NOW(), NOW()
+        FROM (
+            SELECT email, role = 'Owner' AS owner FROM project_members
+            UNION ALL SELECT created_by, FALSE FROM projects
+            UNION ALL SELECT created_by, FALSE FROM clients
+            UNION ALL SELECT email, FALSE FROM team_members
+        ) involved
+        WHERE email <> ''
+        GROUP BY LOWER(email)
+        ON CONFLICT DO NOTHING;
+    END IF;
+END $$;
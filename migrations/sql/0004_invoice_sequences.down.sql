@@ -0,0 +1,3 @@
+-- Invoices keep their numbers; new ones are numbered from the invoice count again by older binaries.
+
+DROP TABLE IF EXISTS "invoice_sequences";
@@ -0,0 +1,15 @@
+-- Numbers invoices with a sequence per month, locked while numbering, instead of counting every invoice.
+-- The sequences of months that already have invoices continue from their highest number.
+
+CREATE TABLE IF NOT EXISTS "invoice_sequences" (
+    "period" varchar(6),
+    "last_number" bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY ("period")
+);
+
+INSERT INTO invoice_sequences (period, last_number)
+SELECT SUBSTRING(number FROM 5 FOR 6), MAX(CAST(SUBSTRING(number FROM 12) AS bigint))
+FROM invoices
+WHERE number ~ '^INV-[0-9]{6}-[0-9]+$'
+GROUP BY 1
+ON CONFLICT (period) DO NOTHING;
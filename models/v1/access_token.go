@@ -0,0 +1,101 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// AccessTokenPrefix starts every personal access token, telling them apart from user JWTs.
+const AccessTokenPrefix = "pmpat_"
+
+// DefaultAccessTokenExpiryDays is how long an access token is valid when its creator does not say otherwise.
+const DefaultAccessTokenExpiryDays = 90
+
+// ServiceAccountEmailDomain is the domain of the email addresses identifying service accounts. It is
+// not routable, so that no mail is ever delivered to a service account.
+const ServiceAccountEmailDomain = "service-accounts.invalid"
+
+// Access token scopes. Read tokens can only make safe requests (GET, HEAD and OPTIONS); write tokens can
+// make any request their owner could, except managing access tokens.
+const (
+	AccessTokenScopeRead  = "read"
+	AccessTokenScopeWrite = "write"
+)
+
+// AccessToken is a personal access token authenticating a user or a service account without a JWT. Only
+// a hash of the token is stored; the token itself is shown once, when it is created.
+type AccessToken struct {
+	ID               uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name             string         `gorm:"type:varchar(255);not null" json:"name"`
+	Email            string         `gorm:"type:varchar(255);not null;index" json:"email"`
+	ServiceAccountID *uuid.UUID     `gorm:"type:uuid;index" json:"service_account_id"`
+	TokenHash        string         `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Hint             string         `gorm:"type:varchar(20);not null" json:"hint"`
+	Scopes           pq.StringArray `gorm:"type:text[];not null" json:"scopes"`
+	ExpiresAt        time.Time      `gorm:"not null" json:"expires_at"`
+	LastUsedAt       *time.Time     `json:"last_used_at"`
+	LastUsedIP       string         `gorm:"type:varchar(64)" json:"last_used_ip"`
+	RevokedAt        *time.Time     `json:"revoked_at"`
+	CreatedBy        string         `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt        time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ServiceAccount is a non-human identity of an organization, such as a CI bot. It authenticates with
+// access tokens and is added to projects like a user, under its generated email address.
+type ServiceAccount struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Name           string    `gorm:"type:varchar(255);not null" json:"name"`
+	Email          string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
+	Description    string    `gorm:"type:text" json:"description"`
+	CreatedBy      string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// AccessTokenRequest creates an access token. Scopes default to read only and ExpiresInDays to
+// DefaultAccessTokenExpiryDays.
+type AccessTokenRequest struct {
+	Name          string   `json:"name" binding:"required,max=255"`
+	Scopes        []string `json:"scopes" binding:"omitempty,dive,oneof=read write"`
+	ExpiresInDays *int     `json:"expires_in_days" binding:"omitempty,min=1,max=365"`
+}
+
+// AccessTokenResponse is the API representation of an access token. Token is only set in the response
+// creating it.
+type AccessTokenResponse struct {
+	ID               string     `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	ServiceAccountID string     `json:"service_account_id,omitempty"`
+	Token            string     `json:"token,omitempty"`
+	Hint             string     `json:"hint"`
+	Scopes           []string   `json:"scopes"`
+	ExpiresAt        time.Time  `json:"expires_at"`
+	Expired          bool       `json:"expired"`
+	LastUsedAt       *time.Time `json:"last_used_at"`
+	LastUsedIP       string     `json:"last_used_ip,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedBy        string     `json:"created_by"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// ServiceAccountRequest creates a service account.
+type ServiceAccountRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description"`
+}
+
+// ProjectServiceAccountRequest adds a service account to a project with a role.
+type ProjectServiceAccountRequest struct {
+	ServiceAccountID string `json:"service_account_id" binding:"required,uuid"`
+	Role             string `json:"role" binding:"required,max=100"`
+}
+
+// ProjectServiceAccountResponse is a service account added to a project.
+type ProjectServiceAccountResponse struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
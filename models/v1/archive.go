@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProjectStatusArchived is the status of an archived, read-only project.
+const ProjectStatusArchived = "archived"
+
+// ProjectArchive records who archived a project and the status it is restored to when unarchived.
+type ProjectArchive struct {
+	ProjectID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"project_id"`
+	PreviousStatus string    `gorm:"type:varchar(50)" json:"previous_status"`
+	ArchivedBy     string    `gorm:"type:varchar(255);not null" json:"archived_by"`
+	ArchivedAt     time.Time `gorm:"not null" json:"archived_at"`
+}
+
+// ProjectArchiveResponse reports the archive state of a project.
+type ProjectArchiveResponse struct {
+	ProjectID  string     `json:"project_id"`
+	Archived   bool       `json:"archived"`
+	Status     string     `json:"status"`
+	ArchivedBy string     `json:"archived_by,omitempty"`
+	ArchivedAt *time.Time `json:"archived_at,omitempty"`
+}
@@ -0,0 +1,194 @@
+// Package v1 contains the database models and request/response payloads that are owned by the
+// project management API itself, as opposed to the shared models provided by the common module.
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invoice statuses.
+const (
+	InvoiceStatusDraft  = "draft"
+	InvoiceStatusIssued = "issued"
+	InvoiceStatusVoid   = "void"
+)
+
+// DefaultCurrency is used when a client has no billing settings.
+const DefaultCurrency = "USD"
+
+// ClientBilling holds the billing settings of a client.
+type ClientBilling struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClientID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"client_id"`
+	HourlyRate float64   `gorm:"type:numeric(12,2);not null;default:0" json:"hourly_rate"`
+	Currency   string    `gorm:"type:varchar(3);not null;default:'USD'" json:"currency"`
+	CreatedBy  string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ProjectBilling holds the billing settings of a project. A nil HourlyRate falls back to the client rate.
+type ProjectBilling struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"project_id"`
+	HourlyRate *float64  `gorm:"type:numeric(12,2)" json:"hourly_rate"`
+	CreatedBy  string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ProjectMemberRate overrides the hourly rate of a single member on a project.
+type ProjectMemberRate struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_member_rate" json:"project_id"`
+	Email      string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_project_member_rate" json:"email"`
+	HourlyRate float64   `gorm:"type:numeric(12,2);not null" json:"hourly_rate"`
+	CreatedBy  string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt  time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TimeEntryBilling tracks the billable flag, approval and invoicing state of a time entry.
+type TimeEntryBilling struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TimeEntryID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"time_entry_id"`
+	ProjectID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"project_id"`
+	IsBillable  bool       `gorm:"not null;default:true" json:"is_billable"`
+	ApprovedBy  string     `gorm:"type:varchar(255)" json:"approved_by"`
+	ApprovedAt  *time.Time `json:"approved_at"`
+	InvoiceID   *uuid.UUID `gorm:"type:uuid;index" json:"invoice_id"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Invoice is a draft or issued invoice for a client covering a billing period.
+type Invoice struct {
+	ID          uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ClientID    uuid.UUID     `gorm:"type:uuid;not null;index" json:"client_id"`
+	Number      string        `gorm:"type:varchar(50);not null;uniqueIndex" json:"number"`
+	Status      string        `gorm:"type:varchar(20);not null;default:'draft'" json:"status"`
+	Currency    string        `gorm:"type:varchar(3);not null" json:"currency"`
+	PeriodStart time.Time     `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd   time.Time     `gorm:"type:date;not null" json:"period_end"`
+	TotalHours  float64       `gorm:"type:numeric(12,2);not null;default:0" json:"total_hours"`
+	TotalAmount float64       `gorm:"type:numeric(14,2);not null;default:0" json:"total_amount"`
+	CreatedBy   string        `gorm:"type:varchar(255);not null" json:"created_by"`
+	IssuedAt    *time.Time    `json:"issued_at"`
+	CreatedAt   time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+	Lines       []InvoiceLine `gorm:"foreignKey:InvoiceID" json:"lines,omitempty"`
+}
+
+// InvoiceSequence holds the last number given to an invoice in a month. Its row is locked while a new
+// invoice is numbered, so that concurrent drafts get consecutive numbers.
+type InvoiceSequence struct {
+	Period     string `gorm:"type:varchar(6);primaryKey" json:"period"`
+	LastNumber int    `gorm:"not null;default:0" json:"last_number"`
+}
+
+// InvoiceLine is a single time entry billed on an invoice.
+type InvoiceLine struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	InvoiceID   uuid.UUID `gorm:"type:uuid;not null;index" json:"invoice_id"`
+	TimeEntryID uuid.UUID `gorm:"type:uuid;not null" json:"time_entry_id"`
+	ProjectID   uuid.UUID `gorm:"type:uuid;not null" json:"project_id"`
+	IssueID     uuid.UUID `gorm:"type:uuid;not null" json:"issue_id"`
+	Email       string    `gorm:"type:varchar(255);not null" json:"email"`
+	Date        time.Time `gorm:"type:date;not null" json:"date"`
+	Description string    `gorm:"type:text" json:"description"`
+	Hours       float64   `gorm:"type:numeric(10,2);not null" json:"hours"`
+	HourlyRate  float64   `gorm:"type:numeric(12,2);not null" json:"hourly_rate"`
+	Amount      float64   `gorm:"type:numeric(14,2);not null" json:"amount"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ClientBillingRequest updates the billing settings of a client.
+type ClientBillingRequest struct {
+	HourlyRate *float64 `json:"hourly_rate" binding:"omitempty,gte=0"`
+	Currency   string   `json:"currency" binding:"omitempty,len=3"`
+}
+
+// ProjectBillingRequest updates the billing settings of a project.
+type ProjectBillingRequest struct {
+	HourlyRate *float64 `json:"hourly_rate" binding:"omitempty,gte=0"`
+}
+
+// ProjectMemberRateRequest sets the hourly rate override of a project member.
+type ProjectMemberRateRequest struct {
+	Email      string  `json:"email" binding:"required,email"`
+	HourlyRate float64 `json:"hourly_rate" binding:"gte=0"`
+}
+
+// TimeEntryBillingRequest updates the billable flag of a time entry.
+type TimeEntryBillingRequest struct {
+	IsBillable bool `json:"is_billable"`
+}
+
+// InvoiceRequest creates a draft invoice for a client and period.
+type InvoiceRequest struct {
+	PeriodStart string `json:"period_start" binding:"required"`
+	PeriodEnd   string `json:"period_end" binding:"required"`
+}
+
+// ClientBillingResponse is the API representation of client billing settings.
+type ClientBillingResponse struct {
+	ClientID   string    `json:"client_id"`
+	HourlyRate float64   `json:"hourly_rate"`
+	Currency   string    `json:"currency"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ProjectBillingResponse is the API representation of project billing settings.
+type ProjectBillingResponse struct {
+	ProjectID   string                  `json:"project_id"`
+	HourlyRate  *float64                `json:"hourly_rate"`
+	Currency    string                  `json:"currency"`
+	MemberRates []ProjectMemberRateItem `json:"member_rates"`
+}
+
+// ProjectMemberRateItem is a member rate override in a billing response.
+type ProjectMemberRateItem struct {
+	Email      string  `json:"email"`
+	HourlyRate float64 `json:"hourly_rate"`
+}
+
+// TimeEntryBillingResponse is the API representation of the billing state of a time entry.
+type TimeEntryBillingResponse struct {
+	TimeEntryID string     `json:"time_entry_id"`
+	IsBillable  bool       `json:"is_billable"`
+	ApprovedBy  string     `json:"approved_by"`
+	ApprovedAt  *time.Time `json:"approved_at"`
+	InvoiceID   *string    `json:"invoice_id"`
+}
+
+// InvoiceLineResponse is the API representation of an invoice line.
+type InvoiceLineResponse struct {
+	TimeEntryID string    `json:"time_entry_id"`
+	ProjectID   string    `json:"project_id"`
+	IssueID     string    `json:"issue_id"`
+	Email       string    `json:"email"`
+	Date        time.Time `json:"date"`
+	Description string    `json:"description"`
+	Hours       float64   `json:"hours"`
+	HourlyRate  float64   `json:"hourly_rate"`
+	Amount      float64   `json:"amount"`
+}
+
+// InvoiceResponse is the API representation of an invoice.
+type InvoiceResponse struct {
+	ID          string                `json:"id"`
+	ClientID    string                `json:"client_id"`
+	Number      string                `json:"number"`
+	Status      string                `json:"status"`
+	Currency    string                `json:"currency"`
+	PeriodStart time.Time             `json:"period_start"`
+	PeriodEnd   time.Time             `json:"period_end"`
+	TotalHours  float64               `json:"total_hours"`
+	TotalAmount float64               `json:"total_amount"`
+	CreatedBy   string                `json:"created_by"`
+	IssuedAt    *time.Time            `json:"issued_at"`
+	CreatedAt   time.Time             `json:"created_at"`
+	Lines       []InvoiceLineResponse `json:"lines,omitempty"`
+}
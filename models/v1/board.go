@@ -0,0 +1,87 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Work-in-progress limit modes.
+const (
+	// WIPModeSoft lets moves exceed the limit and reports a warning.
+	WIPModeSoft = "soft"
+	// WIPModeHard rejects moves that would exceed the limit.
+	WIPModeHard = "hard"
+)
+
+// StateWIPLimit caps the number of issues a project state may hold.
+type StateWIPLimit struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	StateID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"state_id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
+	Limit     int       `gorm:"not null" json:"limit"`
+	Mode      string    `gorm:"type:varchar(10);not null" json:"mode"`
+	UpdatedBy string    `gorm:"type:varchar(255);not null" json:"updated_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table of WIP limits, which GORM would otherwise name state_w_ip_limits.
+func (StateWIPLimit) TableName() string {
+	return "state_wip_limits"
+}
+
+// StateWIPLimitRequest sets the work-in-progress limit of a project state.
+type StateWIPLimitRequest struct {
+	Limit int    `json:"limit" binding:"required,gt=0"`
+	Mode  string `json:"mode" binding:"required,oneof=soft hard"`
+}
+
+// StateColumn summarizes a project state as a board column.
+type StateColumn struct {
+	StateID    string `json:"state_id"`
+	Name       string `json:"name"`
+	Sequence   int32  `json:"sequence"`
+	Category   string `json:"category"`
+	IssueCount int64  `json:"issue_count"`
+	WIPLimit   *int   `json:"wip_limit"`
+	WIPMode    string `json:"wip_mode,omitempty"`
+	OverLimit  bool   `json:"over_limit"`
+}
+
+// BoardIssue is the card of an issue on a board.
+type BoardIssue struct {
+	ID          string     `json:"id"`
+	SequenceID  int32      `json:"sequence_id"`
+	Title       string     `json:"title"`
+	Priority    string     `json:"priority"`
+	Point       int32      `json:"point"`
+	EndDate     time.Time  `json:"end_date"`
+	CompletedAt *time.Time `json:"completed_at"`
+}
+
+// BoardColumn is a state column of a board together with its issues.
+type BoardColumn struct {
+	StateColumn
+	Issues []BoardIssue `json:"issues"`
+}
+
+// BoardResponse is the kanban board of a project.
+type BoardResponse struct {
+	ProjectID       string        `json:"project_id"`
+	Columns         []BoardColumn `json:"columns"`
+	OverLimitStates []string      `json:"over_limit_states"`
+}
+
+// ProjectStatsResponse summarizes the issues and logged hours of a project.
+type ProjectStatsResponse struct {
+	ProjectID       string           `json:"project_id"`
+	TotalIssues     int64            `json:"total_issues"`
+	OpenIssues      int64            `json:"open_issues"`
+	ClosedIssues    int64            `json:"closed_issues"`
+	OverdueIssues   int64            `json:"overdue_issues"`
+	ByCategory      map[string]int64 `json:"by_category"`
+	States          []StateColumn    `json:"states"`
+	OverLimitStates []string         `json:"over_limit_states"`
+	LoggedHours     float64          `json:"logged_hours"`
+}
@@ -0,0 +1,78 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Budget kinds tracked for a project.
+const (
+	BudgetKindHours  = "hours"
+	BudgetKindAmount = "amount"
+)
+
+// DefaultBudgetThresholds are the consumption percentages that raise alerts when none are configured.
+var DefaultBudgetThresholds = pq.Int64Array{75, 90, 100}
+
+// ProjectBudget holds the hours and money budget of a project and the thresholds that raise alerts.
+type ProjectBudget struct {
+	ID           uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID    uuid.UUID     `gorm:"type:uuid;not null;uniqueIndex" json:"project_id"`
+	BudgetHours  *float64      `gorm:"type:numeric(12,2)" json:"budget_hours"`
+	BudgetAmount *float64      `gorm:"type:numeric(14,2)" json:"budget_amount"`
+	Thresholds   pq.Int64Array `gorm:"type:integer[]" json:"thresholds"`
+	CreatedBy    string        `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt    time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ProjectBudgetAlert records a threshold that has already been crossed so that it is raised only once.
+type ProjectBudgetAlert struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_budget_alert" json:"project_id"`
+	Kind      string    `gorm:"type:varchar(20);not null;uniqueIndex:idx_project_budget_alert" json:"kind"`
+	Threshold int64     `gorm:"not null;uniqueIndex:idx_project_budget_alert" json:"threshold"`
+	Consumed  float64   `gorm:"type:numeric(14,2);not null" json:"consumed"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ProjectBudgetRequest updates the budget of a project.
+type ProjectBudgetRequest struct {
+	BudgetHours  *float64 `json:"budget_hours" binding:"omitempty,gt=0"`
+	BudgetAmount *float64 `json:"budget_amount" binding:"omitempty,gt=0"`
+	Thresholds   []int64  `json:"thresholds" binding:"omitempty,dive,gt=0,lte=1000"`
+}
+
+// ProjectBudgetResponse is the API representation of a project budget.
+type ProjectBudgetResponse struct {
+	ProjectID    string    `json:"project_id"`
+	BudgetHours  *float64  `json:"budget_hours"`
+	BudgetAmount *float64  `json:"budget_amount"`
+	Currency     string    `json:"currency"`
+	Thresholds   []int64   `json:"thresholds"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// BudgetConsumption reports how much of a project budget has been used.
+type BudgetConsumption struct {
+	ProjectID        string              `json:"project_id"`
+	Currency         string              `json:"currency"`
+	BudgetHours      *float64            `json:"budget_hours"`
+	BudgetAmount     *float64            `json:"budget_amount"`
+	ConsumedHours    float64             `json:"consumed_hours"`
+	ConsumedAmount   float64             `json:"consumed_amount"`
+	HoursPercentage  *float64            `json:"hours_percentage"`
+	AmountPercentage *float64            `json:"amount_percentage"`
+	Thresholds       []int64             `json:"thresholds"`
+	Members          []MemberConsumption `json:"members"`
+}
+
+// MemberConsumption reports the hours and cost logged by a single member.
+type MemberConsumption struct {
+	Email      string  `json:"email"`
+	Hours      float64 `json:"hours"`
+	HourlyRate float64 `json:"hourly_rate"`
+	Amount     float64 `json:"amount"`
+}
@@ -0,0 +1,115 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// DefaultWorkingDays are the working weekdays (0 is Sunday) used when no calendar is configured.
+var DefaultWorkingDays = pq.Int64Array{1, 2, 3, 4, 5}
+
+// DefaultHoursPerDay is the number of working hours per day used when no calendar is configured.
+const DefaultHoursPerDay = 8.0
+
+// WorkingCalendar defines the working weekdays and hours per day in an organization.
+// The organization calendar has an empty Email; a member calendar overrides it for one user.
+type WorkingCalendar struct {
+	ID             uuid.UUID     `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID     `gorm:"type:uuid;uniqueIndex:idx_working_calendar_organization_email" json:"organization_id"`
+	Email          string        `gorm:"type:varchar(255);not null;default:'';uniqueIndex:idx_working_calendar_organization_email" json:"email"`
+	WorkingDays    pq.Int64Array `gorm:"type:integer[];not null" json:"working_days"`
+	HoursPerDay    float64       `gorm:"type:numeric(4,2);not null" json:"hours_per_day"`
+	UpdatedBy      string        `gorm:"type:varchar(255);not null" json:"updated_by"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time     `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// Holiday is a public holiday on which nobody in the organization works.
+type Holiday struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_holiday_organization_date" json:"organization_id"`
+	Date           time.Time `gorm:"type:date;not null;uniqueIndex:idx_holiday_organization_date" json:"date"`
+	Name           string    `gorm:"type:varchar(255);not null" json:"name"`
+	CreatedBy      string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// TimeOff is a period during which a member does not work.
+type TimeOff struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email     string    `gorm:"type:varchar(255);not null;index" json:"email"`
+	StartDate time.Time `gorm:"type:date;not null" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date;not null" json:"end_date"`
+	Reason    string    `gorm:"type:varchar(255)" json:"reason"`
+	CreatedBy string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// WorkingCalendarRequest sets the working weekdays and hours per day of a calendar.
+// Email selects a member calendar; it is ignored for the organization calendar.
+type WorkingCalendarRequest struct {
+	Email       string   `json:"email" binding:"omitempty,email"`
+	WorkingDays []int64  `json:"working_days" binding:"required,dive,gte=0,lte=6"`
+	HoursPerDay *float64 `json:"hours_per_day" binding:"required,gt=0,lte=24"`
+}
+
+// WorkingCalendarResponse is the API representation of a calendar.
+type WorkingCalendarResponse struct {
+	Email       string     `json:"email,omitempty"`
+	WorkingDays []int64    `json:"working_days"`
+	HoursPerDay float64    `json:"hours_per_day"`
+	IsDefault   bool       `json:"is_default"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// HolidayRequest creates a public holiday.
+type HolidayRequest struct {
+	Date string `json:"date" binding:"required,datetime=2006-01-02"`
+	Name string `json:"name" binding:"required,max=255"`
+}
+
+// HolidayResponse is the API representation of a public holiday.
+type HolidayResponse struct {
+	ID        string    `json:"id"`
+	Date      time.Time `json:"date"`
+	Name      string    `json:"name"`
+	CreatedBy string    `json:"created_by"`
+}
+
+// TimeOffRequest records time off for a member.
+type TimeOffRequest struct {
+	Email     string `json:"email" binding:"omitempty,email"`
+	StartDate string `json:"start_date" binding:"required,datetime=2006-01-02"`
+	EndDate   string `json:"end_date" binding:"required,datetime=2006-01-02"`
+	Reason    string `json:"reason" binding:"max=255"`
+}
+
+// TimeOffResponse is the API representation of time off.
+type TimeOffResponse struct {
+	ID        string    `json:"id"`
+	Email     string    `json:"email"`
+	StartDate time.Time `json:"start_date"`
+	EndDate   time.Time `json:"end_date"`
+	Reason    string    `json:"reason"`
+	CreatedBy string    `json:"created_by"`
+}
+
+// CalendarDay reports whether a member works on a given day and for how many hours.
+type CalendarDay struct {
+	Date    time.Time `json:"date"`
+	Working bool      `json:"working"`
+	Hours   float64   `json:"hours"`
+	Reason  string    `json:"reason,omitempty"`
+}
+
+// CalendarDaysResponse lists the effective calendar of a member over a date range.
+type CalendarDaysResponse struct {
+	Email        string        `json:"email"`
+	From         time.Time     `json:"from"`
+	To           time.Time     `json:"to"`
+	WorkingDays  int           `json:"working_days"`
+	WorkingHours float64       `json:"working_hours"`
+	Days         []CalendarDay `json:"days"`
+}
@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GroupSyncAddedBy is recorded as the AddedBy of team memberships granted from identity provider groups,
+// telling them apart from memberships added by hand, which group sync never removes.
+const GroupSyncAddedBy = "oidc-group-sync"
+
+// GroupMapping makes the users in an identity provider group members of a team when they sign in with
+// OIDC. The team's project roles then apply to them, and the membership is removed once the user is no
+// longer in any group mapped to the team.
+type GroupMapping struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Group          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_group_mapping" json:"group"`
+	TeamID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_group_mapping;index" json:"team_id"`
+	CreatedBy      string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// GroupMappingRequest maps an identity provider group to a team.
+type GroupMappingRequest struct {
+	Group  string `json:"group" binding:"required,max=255"`
+	TeamID string `json:"team_id" binding:"required,uuid"`
+}
@@ -0,0 +1,69 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultInvitationExpiryDays is how long an invitation can be accepted when the inviter does not say otherwise.
+const DefaultInvitationExpiryDays = 7
+
+// Invitation statuses. Only pending invitations can be accepted, declined or revoked.
+const (
+	InvitationStatusPending  = "pending"
+	InvitationStatusAccepted = "accepted"
+	InvitationStatusDeclined = "declined"
+	InvitationStatusRevoked  = "revoked"
+)
+
+// ProjectInvitation invites an email address to join a project with a role. The membership is created
+// only when the invitee accepts it with the single-use token sent to them, of which only a hash is stored.
+type ProjectInvitation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"project_id"`
+	Email       string     `gorm:"type:varchar(255);not null;index" json:"email"`
+	Role        string     `gorm:"type:varchar(100);not null" json:"role"`
+	TokenHash   string     `gorm:"type:varchar(64);not null;uniqueIndex" json:"-"`
+	Status      string     `gorm:"type:varchar(20);not null;default:'pending';index" json:"status"`
+	InvitedBy   string     `gorm:"type:varchar(255);not null" json:"invited_by"`
+	ExpiresAt   time.Time  `gorm:"not null" json:"expires_at"`
+	RespondedAt *time.Time `json:"responded_at"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// ProjectInvitationRequest invites a user to a project. ExpiresInDays defaults to DefaultInvitationExpiryDays.
+type ProjectInvitationRequest struct {
+	Email         string `json:"email" binding:"required,email"`
+	Role          string `json:"role" binding:"required,max=100"`
+	ExpiresInDays *int   `json:"expires_in_days" binding:"omitempty,min=1,max=90"`
+}
+
+// InvitationTokenRequest accepts or declines an invitation.
+type InvitationTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ProjectInvitationResponse is the API representation of an invitation.
+type ProjectInvitationResponse struct {
+	ID          string     `json:"id"`
+	ProjectID   string     `json:"project_id"`
+	ProjectName string     `json:"project_name,omitempty"`
+	Email       string     `json:"email"`
+	Role        string     `json:"role"`
+	Status      string     `json:"status"`
+	InvitedBy   string     `json:"invited_by"`
+	ExpiresAt   time.Time  `json:"expires_at"`
+	Expired     bool       `json:"expired"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// ProjectMemberOperationsResponse reports the outcome of a batch of member operations: existing members
+// whose role changed and the invitations sent to new members.
+type ProjectMemberOperationsResponse struct {
+	Updated []string                    `json:"updated"`
+	Removed []string                    `json:"removed"`
+	Invited []ProjectInvitationResponse `json:"invited"`
+}
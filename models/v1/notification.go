@@ -0,0 +1,35 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification types.
+const (
+	NotificationTypeBudgetThreshold = "budget_threshold"
+)
+
+// Notification is an in-app message addressed to a single user.
+type Notification struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email     string     `gorm:"type:varchar(255);not null;index" json:"email"`
+	ProjectID *uuid.UUID `gorm:"type:uuid;index" json:"project_id"`
+	Type      string     `gorm:"type:varchar(50);not null" json:"type"`
+	Title     string     `gorm:"type:varchar(255);not null" json:"title"`
+	Message   string     `gorm:"type:text" json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// NotificationResponse is the API representation of a notification.
+type NotificationResponse struct {
+	ID        string     `json:"id"`
+	ProjectID *string    `json:"project_id"`
+	Type      string     `json:"type"`
+	Title     string     `json:"title"`
+	Message   string     `json:"message"`
+	ReadAt    *time.Time `json:"read_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
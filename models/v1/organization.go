@@ -0,0 +1,90 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationHeader is the request header selecting the organization a request acts in, by ID or slug.
+const OrganizationHeader = "X-Organization"
+
+// DefaultOrganizationSlug is the slug of the organization that data created before organizations
+// existed is moved to.
+const DefaultOrganizationSlug = "default"
+
+// Organization member roles. Admins manage the organization, its members, teams and custom roles.
+const (
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+// Organization is a tenant of the service, such as a business unit. Clients, projects, templates, teams
+// and custom roles belong to exactly one organization and are invisible to the others.
+type Organization struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Slug      string    `gorm:"type:varchar(63);not null;uniqueIndex" json:"slug"`
+	CreatedBy string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OrganizationMember is a user belonging to an organization.
+type OrganizationMember struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_organization_member" json:"organization_id"`
+	Email          string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_organization_member;index" json:"email"`
+	Role           string    `gorm:"type:varchar(20);not null;default:'member'" json:"role"`
+	AddedBy        string    `gorm:"type:varchar(255);not null" json:"added_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// OrganizationClient assigns a client to its organization. Clients are defined by the common models,
+// so the assignment is kept alongside them.
+type OrganizationClient struct {
+	ClientID       uuid.UUID `gorm:"type:uuid;primaryKey" json:"client_id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OrganizationProject assigns a project to its organization. Labels, states, issues and the rest of a
+// project's data belong to the organization of the project.
+type OrganizationProject struct {
+	ProjectID      uuid.UUID `gorm:"type:uuid;primaryKey" json:"project_id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;index" json:"organization_id"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// OrganizationRequest creates or renames an organization. The slug selects the organization in the
+// X-Organization header or as a subdomain.
+type OrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=255"`
+	Slug string `json:"slug" binding:"required,max=63"`
+}
+
+// OrganizationMemberRequest adds a user to an organization or changes their role.
+type OrganizationMemberRequest struct {
+	Email string `json:"email" binding:"omitempty,email"`
+	Role  string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// OrganizationResponse is the API representation of an organization and the role of the user in it.
+type OrganizationResponse struct {
+	ID        string                       `json:"id"`
+	Name      string                       `json:"name"`
+	Slug      string                       `json:"slug"`
+	Role      string                       `json:"role"`
+	Members   []OrganizationMemberResponse `json:"members,omitempty"`
+	CreatedBy string                       `json:"created_by"`
+	CreatedAt time.Time                    `json:"created_at"`
+}
+
+// OrganizationMemberResponse is the API representation of an organization member.
+type OrganizationMemberResponse struct {
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
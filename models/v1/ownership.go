@@ -0,0 +1,38 @@
+package v1
+
+// Project member roles, from the most to the least privileged.
+const (
+	RoleOwner       = "Owner"
+	RoleManager     = "Manager"
+	RoleContributor = "Contributor"
+	RoleWatcher     = "Watcher"
+)
+
+// roleRanks orders the built-in member roles, 0 being the most privileged.
+var roleRanks = map[string]int{RoleOwner: 0, RoleManager: 1, RoleContributor: 2, RoleWatcher: 3}
+
+// RoleAtLeast reports whether role is the given built-in member role or a more privileged one. Roles
+// outside that hierarchy, such as custom roles, only satisfy themselves.
+func RoleAtLeast(role, minimum string) bool {
+	if role == minimum {
+		return true
+	}
+	rank, ranked := roleRanks[role]
+	minimumRank, minimumRanked := roleRanks[minimum]
+	return ranked && minimumRanked && rank <= minimumRank
+}
+
+// TransferOwnershipRequest hands the Owner role of a project to another user. FromEmail defaults to the
+// caller; an Owner may also transfer on behalf of another Owner, e.g. one who has left.
+type TransferOwnershipRequest struct {
+	Email          string `json:"email" binding:"required,email"`
+	FromEmail      string `json:"from_email" binding:"omitempty,email"`
+	PreviousRole   string `json:"previous_role" binding:"omitempty,oneof=Manager Contributor Watcher"`
+	RemovePrevious bool   `json:"remove_previous"`
+}
+
+// ProjectOwnersResponse lists the Owners of a project.
+type ProjectOwnersResponse struct {
+	ProjectID string   `json:"project_id"`
+	Owners    []string `json:"owners"`
+}
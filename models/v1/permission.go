@@ -0,0 +1,160 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// Permissions granted by project roles. Each project-scoped route requires exactly one of them.
+const (
+	PermissionProjectView   = "project.view"
+	PermissionProjectEdit   = "project.edit"
+	PermissionProjectAdmin  = "project.admin"
+	PermissionIssueView     = "issue.view"
+	PermissionIssueCreate   = "issue.create"
+	PermissionIssueEdit     = "issue.edit"
+	PermissionIssueDelete   = "issue.delete"
+	PermissionIssueComment  = "issue.comment"
+	PermissionStateManage   = "state.manage"
+	PermissionLabelManage   = "label.manage"
+	PermissionMemberManage  = "member.manage"
+	PermissionTimeView      = "time.view"
+	PermissionTimeLog       = "time.log"
+	PermissionTimeApprove   = "time.approve"
+	PermissionFileUpload    = "file.upload"
+	PermissionFileDelete    = "file.delete"
+	PermissionBillingManage = "billing.manage"
+)
+
+// RoleClientReviewer is a built-in role for client stakeholders who can read a project and comment on
+// its issues but not change anything.
+const RoleClientReviewer = "Client Reviewer"
+
+// Permissions lists every permission, in the order they are documented.
+var Permissions = []string{
+	PermissionProjectView,
+	PermissionProjectEdit,
+	PermissionProjectAdmin,
+	PermissionIssueView,
+	PermissionIssueCreate,
+	PermissionIssueEdit,
+	PermissionIssueDelete,
+	PermissionIssueComment,
+	PermissionStateManage,
+	PermissionLabelManage,
+	PermissionMemberManage,
+	PermissionTimeView,
+	PermissionTimeLog,
+	PermissionTimeApprove,
+	PermissionFileUpload,
+	PermissionFileDelete,
+	PermissionBillingManage,
+}
+
+// BuiltInRolePermissions maps the built-in roles to the permissions they grant. Owners hold every
+// permission; project.admin (deleting, archiving and purging a project, and granting ownership) is
+// reserved to them. Every role that acts on issues can read them (issue.view), while reading the time
+// logged by others (time.view) is kept to Owners and Managers.
+var BuiltInRolePermissions = map[string][]string{
+	RoleOwner: Permissions,
+	RoleManager: {
+		PermissionProjectView,
+		PermissionProjectEdit,
+		PermissionIssueView,
+		PermissionIssueCreate,
+		PermissionIssueEdit,
+		PermissionIssueDelete,
+		PermissionIssueComment,
+		PermissionStateManage,
+		PermissionLabelManage,
+		PermissionMemberManage,
+		PermissionTimeView,
+		PermissionTimeLog,
+		PermissionTimeApprove,
+		PermissionFileUpload,
+		PermissionFileDelete,
+		PermissionBillingManage,
+	},
+	RoleContributor: {
+		PermissionProjectView,
+		PermissionIssueView,
+		PermissionIssueCreate,
+		PermissionIssueEdit,
+		PermissionIssueDelete,
+		PermissionIssueComment,
+		PermissionTimeLog,
+		PermissionFileUpload,
+		PermissionFileDelete,
+	},
+	RoleWatcher: {
+		PermissionProjectView,
+	},
+	RoleClientReviewer: {
+		PermissionProjectView,
+		PermissionIssueView,
+		PermissionIssueComment,
+	},
+}
+
+// CustomRole is a role defined by an organization in addition to the built-in roles. Members holding
+// it are granted exactly its permissions.
+type CustomRole struct {
+	ID             uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID      `gorm:"type:uuid;uniqueIndex:idx_custom_role_organization_name" json:"organization_id"`
+	Name           string         `gorm:"type:varchar(100);not null;uniqueIndex:idx_custom_role_organization_name" json:"name"`
+	Description    string         `gorm:"type:text" json:"description"`
+	Permissions    pq.StringArray `gorm:"type:text[];not null" json:"permissions"`
+	CreatedBy      string         `gorm:"type:varchar(255);not null" json:"created_by"`
+	UpdatedBy      string         `gorm:"type:varchar(255)" json:"updated_by"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// CustomRoleRequest creates or replaces a custom role.
+type CustomRoleRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required,min=1"`
+}
+
+// RoleResponse is the API representation of a built-in or custom role.
+type RoleResponse struct {
+	ID          string     `json:"id,omitempty"`
+	Name        string     `json:"name"`
+	Description string     `json:"description,omitempty"`
+	Permissions []string   `json:"permissions"`
+	BuiltIn     bool       `json:"built_in"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// ProjectMemberRoleRequest changes the role of a project member to a built-in or custom role.
+type ProjectMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,max=100"`
+}
+
+// ProjectPermissionsResponse lists the permissions the caller holds in a project.
+type ProjectPermissionsResponse struct {
+	ProjectID   string   `json:"project_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
+// IssueComment is a comment left on an issue.
+type IssueComment struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID uuid.UUID  `gorm:"type:uuid;not null;index" json:"project_id"`
+	IssueID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"issue_id"`
+	Body      string     `gorm:"type:text;not null" json:"body"`
+	CreatedBy string     `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt *time.Time `gorm:"index" json:"deleted_at"`
+}
+
+// IssueCommentRequest creates a comment on an issue.
+type IssueCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
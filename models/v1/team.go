@@ -0,0 +1,79 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Team is an organization-level group of users, such as "Frontend" or "QA". Adding a team to a project
+// gives every member of the team the team's role in that project.
+type Team struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_team_organization_name" json:"organization_id"`
+	Name           string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_team_organization_name" json:"name"`
+	Description    string    `gorm:"type:text" json:"description"`
+	CreatedBy      string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt      time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TeamMember is a user belonging to a team.
+type TeamMember struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	TeamID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_team_member" json:"team_id"`
+	Email     string    `gorm:"type:varchar(255);not null;uniqueIndex:idx_team_member;index" json:"email"`
+	AddedBy   string    `gorm:"type:varchar(255);not null" json:"added_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// ProjectTeam gives the members of a team a role in a project.
+type ProjectTeam struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_team" json:"project_id"`
+	TeamID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_project_team;index" json:"team_id"`
+	Role      string    `gorm:"type:varchar(100);not null" json:"role"`
+	AddedBy   string    `gorm:"type:varchar(255);not null" json:"added_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TeamRequest creates or renames a team.
+type TeamRequest struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description"`
+}
+
+// TeamMembersRequest adds users to a team.
+type TeamMembersRequest struct {
+	Emails []string `json:"emails" binding:"required,min=1,dive,email"`
+}
+
+// ProjectTeamRequest adds a team to a project, or changes its role. The Owner role can only be held
+// through a direct membership.
+type ProjectTeamRequest struct {
+	TeamID string `json:"team_id" binding:"omitempty,uuid"`
+	Role   string `json:"role" binding:"required,max=100"`
+}
+
+// TeamResponse is the API representation of a team.
+type TeamResponse struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	Members     []string              `json:"members"`
+	Projects    []ProjectTeamResponse `json:"projects,omitempty"`
+	CreatedBy   string                `json:"created_by"`
+	CreatedAt   time.Time             `json:"created_at"`
+}
+
+// ProjectTeamResponse is the API representation of a team added to a project.
+type ProjectTeamResponse struct {
+	ProjectID string    `json:"project_id"`
+	TeamID    string    `json:"team_id"`
+	TeamName  string    `json:"team_name,omitempty"`
+	Role      string    `json:"role"`
+	Members   []string  `json:"members,omitempty"`
+	AddedBy   string    `json:"added_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
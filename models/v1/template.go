@@ -0,0 +1,137 @@
+package v1
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedView is a named set of issue filters saved on a project.
+type SavedView struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	Filters   string    `gorm:"type:jsonb;not null;default:'{}'" json:"filters"`
+	CreatedBy string    `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// SavedViewRequest creates a saved view.
+type SavedViewRequest struct {
+	Name    string          `json:"name" binding:"required,max=255"`
+	Filters json.RawMessage `json:"filters" binding:"required"`
+}
+
+// SavedViewResponse is the API representation of a saved view.
+type SavedViewResponse struct {
+	ID        string          `json:"id"`
+	ProjectID string          `json:"project_id"`
+	Name      string          `json:"name"`
+	Filters   json.RawMessage `json:"filters"`
+	CreatedBy string          `json:"created_by"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// ProjectTemplate is a reusable project structure. Its definition is stored as a JSON TemplateDefinition.
+type ProjectTemplate struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	OrganizationID  uuid.UUID  `gorm:"type:uuid;index" json:"organization_id"`
+	Name            string     `gorm:"type:varchar(255);not null" json:"name"`
+	Description     string     `gorm:"type:text" json:"description"`
+	SourceProjectID *uuid.UUID `gorm:"type:uuid" json:"source_project_id"`
+	Definition      string     `gorm:"type:jsonb;not null" json:"definition"`
+	CreatedBy       string     `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt       *time.Time `gorm:"index" json:"deleted_at"`
+}
+
+// TemplateDefinition is the structure applied to a project created from a template or a clone.
+type TemplateDefinition struct {
+	States  []TemplateState  `json:"states" binding:"dive"`
+	Labels  []TemplateLabel  `json:"labels" binding:"dive"`
+	Members []TemplateMember `json:"members" binding:"dive"`
+	Views   []TemplateView   `json:"views" binding:"dive"`
+	Issues  []TemplateIssue  `json:"issues" binding:"dive"`
+}
+
+// TemplateState is a state of a template, in board order.
+type TemplateState struct {
+	Name     string `json:"name" binding:"required,max=255"`
+	Sequence int32  `json:"sequence"`
+	Category string `json:"category" binding:"omitempty,oneof=backlog unstarted started completed cancelled"`
+	WIPLimit *int   `json:"wip_limit,omitempty" binding:"omitempty,gt=0"`
+	WIPMode  string `json:"wip_mode,omitempty" binding:"omitempty,oneof=soft hard"`
+}
+
+// TemplateLabel is a label of a template.
+type TemplateLabel struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Color string `json:"color"`
+}
+
+// TemplateMember is a member added with a default role to projects created from a template.
+type TemplateMember struct {
+	Email string `json:"email" binding:"required,email"`
+	Role  string `json:"role" binding:"required,oneof=Owner Manager Contributor Watcher"`
+}
+
+// TemplateView is a saved view of a template.
+type TemplateView struct {
+	Name    string          `json:"name" binding:"required,max=255"`
+	Filters json.RawMessage `json:"filters"`
+}
+
+// TemplateIssue is a seed issue of a template. Its dates are relative to the start date of the project and
+// counted in working days of the organization calendar, its state and labels are referenced by name and its
+// parent by the Key of another seed issue.
+type TemplateIssue struct {
+	Key             int32    `json:"key"`
+	ParentKey       int32    `json:"parent_key,omitempty"`
+	Title           string   `json:"title" binding:"required"`
+	Description     string   `json:"description"`
+	Priority        string   `json:"priority"`
+	Point           int32    `json:"point"`
+	EstimatedHours  float64  `json:"estimated_hours"`
+	State           string   `json:"state"`
+	Labels          []string `json:"labels"`
+	StartOffsetDays int      `json:"start_offset_days"`
+	DurationDays    int      `json:"duration_days"`
+}
+
+// ProjectTemplateRequest creates a template from a definition.
+type ProjectTemplateRequest struct {
+	Name        string             `json:"name" binding:"required,max=255"`
+	Description string             `json:"description"`
+	Definition  TemplateDefinition `json:"definition"`
+}
+
+// CaptureTemplateRequest creates a template from an existing project.
+type CaptureTemplateRequest struct {
+	Name              string `json:"name" binding:"required,max=255"`
+	Description       string `json:"description"`
+	IncludeOpenIssues bool   `json:"include_open_issues"`
+}
+
+// ProjectTemplateResponse is the API representation of a template.
+type ProjectTemplateResponse struct {
+	ID              string             `json:"id"`
+	Name            string             `json:"name"`
+	Description     string             `json:"description"`
+	SourceProjectID *string            `json:"source_project_id"`
+	Definition      TemplateDefinition `json:"definition"`
+	CreatedBy       string             `json:"created_by"`
+	CreatedAt       time.Time          `json:"created_at"`
+}
+
+// CloneProjectRequest duplicates a project under a new slug.
+type CloneProjectRequest struct {
+	Name              string `json:"name" binding:"required,max=255"`
+	Slug              string `json:"slug" binding:"required,max=255"`
+	StartDate         string `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate           string `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+	IncludeOpenIssues bool   `json:"include_open_issues"`
+	IncludeMembers    bool   `json:"include_members"`
+}
@@ -0,0 +1,53 @@
+package v1
+
+import "time"
+
+// Kinds of trashed items.
+const (
+	TrashKindProject = "project"
+	TrashKindClient  = "client"
+	TrashKindIssue   = "issue"
+	TrashKindState   = "state"
+	TrashKindLabel   = "label"
+)
+
+// TrashItem is a soft-deleted row that can be restored or purged.
+type TrashItem struct {
+	ID        string     `json:"id"`
+	Kind      string     `json:"kind"`
+	Name      string     `json:"name"`
+	ParentID  *string    `json:"parent_id,omitempty"`
+	DeletedAt time.Time  `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at,omitempty"`
+}
+
+// ProjectTrashResponse lists the trashed issues, states and labels of a project.
+type ProjectTrashResponse struct {
+	ProjectID string      `json:"project_id"`
+	Issues    []TrashItem `json:"issues"`
+	States    []TrashItem `json:"states"`
+	Labels    []TrashItem `json:"labels"`
+}
+
+// TrashResponse lists the trashed projects and clients of the user.
+type TrashResponse struct {
+	Projects []TrashItem `json:"projects"`
+	Clients  []TrashItem `json:"clients"`
+}
+
+// RestoreResponse reports a restored item and the number of dependent rows revived with it.
+type RestoreResponse struct {
+	ID         string `json:"id"`
+	Kind       string `json:"kind"`
+	Dependents int64  `json:"dependents"`
+}
+
+// PurgeSummary counts the rows hard-deleted by a retention run.
+type PurgeSummary struct {
+	Projects int `json:"projects"`
+	Clients  int `json:"clients"`
+	Issues   int `json:"issues"`
+	States   int `json:"states"`
+	Labels   int `json:"labels"`
+	Skipped  int `json:"skipped"`
+}
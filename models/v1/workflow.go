@@ -0,0 +1,111 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+// State categories group project states by how far along their issues are.
+const (
+	StateCategoryBacklog   = "backlog"
+	StateCategoryUnstarted = "unstarted"
+	StateCategoryStarted   = "started"
+	StateCategoryCompleted = "completed"
+	StateCategoryCancelled = "cancelled"
+)
+
+// DefaultStateCategory is the category of states that have not been categorized.
+const DefaultStateCategory = StateCategoryUnstarted
+
+// IsClosedStateCategory reports whether issues in a state of the given category are done.
+func IsClosedStateCategory(category string) bool {
+	return category == StateCategoryCompleted || category == StateCategoryCancelled
+}
+
+// ProjectStateCategory assigns a category to a project state.
+type ProjectStateCategory struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	StateID   uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"state_id"`
+	ProjectID uuid.UUID `gorm:"type:uuid;not null;index" json:"project_id"`
+	Category  string    `gorm:"type:varchar(20);not null" json:"category"`
+	UpdatedBy string    `gorm:"type:varchar(255);not null" json:"updated_by"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// StateTransition allows issues of a project to move from one state to another.
+// A nil FromStateID allows the move from any state, and the creation of issues in the target state. When Roles
+// is empty any member who can edit issues may use it, otherwise the listed roles and more privileged ones may.
+// Projects without transitions allow every move.
+type StateTransition struct {
+	ID          uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	ProjectID   uuid.UUID      `gorm:"type:uuid;not null;index" json:"project_id"`
+	FromStateID *uuid.UUID     `gorm:"type:uuid" json:"from_state_id"`
+	ToStateID   uuid.UUID      `gorm:"type:uuid;not null" json:"to_state_id"`
+	Roles       pq.StringArray `gorm:"type:text[]" json:"roles"`
+	CreatedBy   string         `gorm:"type:varchar(255);not null" json:"created_by"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// StateCategoryRequest sets the category of a project state.
+type StateCategoryRequest struct {
+	Category string `json:"category" binding:"required,oneof=backlog unstarted started completed cancelled"`
+}
+
+// StateTransitionRequest creates a transition rule.
+type StateTransitionRequest struct {
+	FromStateID string   `json:"from_state_id" binding:"omitempty,uuid"`
+	ToStateID   string   `json:"to_state_id" binding:"required,uuid"`
+	Roles       []string `json:"roles" binding:"omitempty,dive,oneof=Owner Manager Contributor Watcher"`
+}
+
+// BulkMoveIssuesRequest moves several issues of a project to one state.
+type BulkMoveIssuesRequest struct {
+	IssueIDs []string `json:"issue_ids" binding:"required,min=1,max=500,dive,uuid"`
+	StateID  string   `json:"state_id" binding:"required,uuid"`
+}
+
+// StateCategoryResponse is a project state together with its category.
+type StateCategoryResponse struct {
+	StateID  string `json:"state_id"`
+	Name     string `json:"name"`
+	Sequence int32  `json:"sequence"`
+	Category string `json:"category"`
+}
+
+// StateTransitionResponse is the API representation of a transition rule.
+type StateTransitionResponse struct {
+	ID          string    `json:"id"`
+	FromStateID *string   `json:"from_state_id"`
+	ToStateID   string    `json:"to_state_id"`
+	Roles       []string  `json:"roles"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// WorkflowResponse describes the states and transition rules of a project.
+type WorkflowResponse struct {
+	States      []StateCategoryResponse   `json:"states"`
+	Transitions []StateTransitionResponse `json:"transitions"`
+}
+
+// BulkMoveIssuesResponse lists the issues moved by a bulk move.
+type BulkMoveIssuesResponse struct {
+	StateID  string   `json:"state_id"`
+	Moved    []string `json:"moved"`
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// StateDeletionResponse lists the issues affected by deleting a state and where they are moved to.
+type StateDeletionResponse struct {
+	StateID        string       `json:"state_id"`
+	MoveToStateID  *string      `json:"move_to_state_id"`
+	DryRun         bool         `json:"dry_run"`
+	AffectedIssues []BoardIssue `json:"affected_issues"`
+	// WIPLimitViolation describes the work-in-progress limit of the target state that moving the affected
+	// issues breaks. A hard limit, flagged by WIPLimitHard, makes the deletion fail; a soft one only warns.
+	WIPLimitViolation string `json:"wip_limit_violation,omitempty"`
+	WIPLimitHard      bool   `json:"wip_limit_hard,omitempty"`
+}
@@ -0,0 +1,77 @@
+package v1
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MemberCapacity holds the number of hours a user can work per week across all projects.
+// Without one, the capacity of a user follows their working calendar.
+type MemberCapacity struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Email       string    `gorm:"type:varchar(255);not null;uniqueIndex" json:"email"`
+	WeeklyHours float64   `gorm:"type:numeric(5,2);not null" json:"weekly_hours"`
+	UpdatedBy   string    `gorm:"type:varchar(255);not null" json:"updated_by"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// MemberCapacityRequest sets the weekly capacity of a user.
+type MemberCapacityRequest struct {
+	Email       string   `json:"email" binding:"omitempty,email"`
+	WeeklyHours *float64 `json:"weekly_hours" binding:"required,gte=0,lte=168"`
+}
+
+// MemberCapacityResponse is the API representation of a user's weekly capacity.
+type MemberCapacityResponse struct {
+	Email       string     `json:"email"`
+	WeeklyHours float64    `json:"weekly_hours"`
+	IsDefault   bool       `json:"is_default"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// WorkloadResponse reports the workload of a set of members over a date range.
+type WorkloadResponse struct {
+	From    time.Time        `json:"from"`
+	To      time.Time        `json:"to"`
+	Members []MemberWorkload `json:"members"`
+}
+
+// MemberWorkload reports the planned and logged work of a single member against their capacity.
+type MemberWorkload struct {
+	Email             string          `json:"email"`
+	WeeklyCapacity    float64         `json:"weekly_capacity"`
+	CapacityHours     float64         `json:"capacity_hours"`
+	OpenIssues        int             `json:"open_issues"`
+	RemainingHours    float64         `json:"remaining_hours"`
+	AllocatedHours    float64         `json:"allocated_hours"`
+	LoggedHours       float64         `json:"logged_hours"`
+	OverAllocated     bool            `json:"over_allocated"`
+	OverAllocatedDays int             `json:"over_allocated_days"`
+	Days              []WorkloadDay   `json:"days"`
+	Issues            []WorkloadIssue `json:"issues"`
+}
+
+// WorkloadDay reports the planned and logged hours of a member on a single day.
+type WorkloadDay struct {
+	Date           time.Time `json:"date"`
+	CapacityHours  float64   `json:"capacity_hours"`
+	AllocatedHours float64   `json:"allocated_hours"`
+	LoggedHours    float64   `json:"logged_hours"`
+	OverAllocated  bool      `json:"over_allocated"`
+}
+
+// WorkloadIssue is an open issue contributing to a member's workload.
+type WorkloadIssue struct {
+	ID             string    `json:"id"`
+	ProjectID      string    `json:"project_id"`
+	Title          string    `json:"title"`
+	StartDate      time.Time `json:"start_date"`
+	EndDate        time.Time `json:"end_date"`
+	EstimatedHours float64   `json:"estimated_hours"`
+	RemainingHours float64   `json:"remaining_hours"`
+	Overdue        bool      `json:"overdue"`
+	// WorkingDaysLeft counts the member's working days from today, or the start date, until the end date.
+	WorkingDaysLeft int `json:"working_days_left"`
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/project-management-api/docs"
+	"github.com/san-data-systems/project-management-api/routes"
+)
+
+// runOpenAPI prints the OpenAPI spec of the routes the server registers, the spec SDKs should be
+// generated from. With --check it instead lists the drift between the documentation and the router:
+// operations docs/openapi.json documents that the router does not serve, and served routes neither
+// documented nor described in docs/operations.go. It fails when there is any.
+func runOpenAPI(args []string) error {
+	flags := flag.NewFlagSet("openapi", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "openapi [--check]", "Print the OpenAPI spec of the registered routes, or check it for drift.")
+	check := flags.Bool("check", false, "fail on drift between the documentation and the routes instead of printing the spec")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	gin.SetMode(gin.ReleaseMode)
+	spec, drift, err := docs.Generate(routes.New().Routes())
+	if err != nil {
+		return err
+	}
+
+	if *check {
+		for _, d := range drift {
+			fmt.Fprintf(os.Stderr, "%s: %s %s\n", d.Reason, d.Method, d.Path)
+		}
+		if len(drift) > 0 {
+			return fmt.Errorf("%d operations drifted from the documentation", len(drift))
+		}
+		return nil
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(spec)
+}
@@ -0,0 +1,75 @@
+package reload
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"sync"
+
+	"github.com/san-data-systems/common/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Certificate serves a TLS certificate loaded from files and reloaded when they change, so that a
+// rotated certificate is used for new connections without a restart.
+type Certificate struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+}
+
+// NewCertificate loads the certificate and private key in the given PEM files.
+func NewCertificate(certFile, keyFile string) (*Certificate, error) {
+	c := &Certificate{certFile: certFile, keyFile: keyFile}
+	if err := c.Reload(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload loads the certificate files again. On failure, such as a key not matching the certificate
+// while the files are being replaced, the certificate loaded before is kept.
+func (c *Certificate) Reload() error {
+	certificate, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		return err
+	}
+	if certificate.Leaf == nil {
+		if certificate.Leaf, err = x509.ParseCertificate(certificate.Certificate[0]); err != nil {
+			return err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.certificate = &certificate
+	return nil
+}
+
+// GetCertificate returns the current certificate. It is meant for tls.Config.GetCertificate.
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.certificate, nil
+}
+
+// TLSConfig returns a server TLS configuration serving the current certificate.
+func (c *Certificate) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: c.GetCertificate}
+}
+
+// Watch reloads the certificate when its files change, until the context is cancelled.
+func (c *Certificate) Watch(ctx context.Context) error {
+	return watch(ctx, []string{c.certFile, c.keyFile}, func() {
+		if err := c.Reload(); err != nil {
+			logger.LogError("Failed to reload TLS certificate, keeping the current one.", logrus.Fields{"error": err.Error()})
+			return
+		}
+		c.mu.RLock()
+		leaf := c.certificate.Leaf
+		c.mu.RUnlock()
+		logger.LogInfo("Reloaded TLS certificate.", logrus.Fields{"subject": leaf.Subject.String(), "not_after": leaf.NotAfter})
+	})
+}
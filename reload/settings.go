@@ -0,0 +1,183 @@
+package reload
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/project-management-api/middlewares"
+	"github.com/sirupsen/logrus"
+)
+
+// settings are the settings that are safe to change while the service runs, with the function applying
+// a new value. An empty value restores the default. Any other setting needs a restart.
+var settings = map[string]func(value string) error{
+	"CORS_ALLOWED_ORIGINS": applyCORSAllowedOrigins,
+	"LOG_LEVEL":            applyLogLevel,
+	"READINESS_TIMEOUT":    applyEnvDuration("READINESS_TIMEOUT"),
+}
+
+// Settings applies the reloadable settings of the environment, overridden by those of a runtime
+// configuration file when one is set. The file uses the format of .env files, so that it can be a
+// mounted ConfigMap.
+type Settings struct {
+	file string
+
+	mu       sync.Mutex
+	defaults map[string]string
+	applied  map[string]string
+}
+
+// NewSettings applies the reloadable settings of the environment and of the given file, which may be
+// empty. Invalid values are an error.
+func NewSettings(file string) (*Settings, error) {
+	s := &Settings{file: file, defaults: map[string]string{}, applied: map[string]string{}}
+	for key := range settings {
+		s.defaults[key] = os.Getenv(key)
+	}
+	values, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	for key, apply := range settings {
+		if err := apply(values[key]); err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", key, values[key], err)
+		}
+		s.applied[key] = values[key]
+	}
+	return s, nil
+}
+
+// Reload reads the runtime configuration file again and applies the settings that changed. Invalid
+// values are logged and the previous value is kept.
+func (s *Settings) Reload() error {
+	values, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for key, apply := range settings {
+		value := values[key]
+		if value == s.applied[key] {
+			continue
+		}
+		if err := apply(value); err != nil {
+			logger.LogError("Ignoring invalid runtime setting.", logrus.Fields{"setting": key, "value": value, "error": err.Error()})
+			continue
+		}
+		s.applied[key] = value
+		logger.LogInfo("Applied runtime setting.", logrus.Fields{"setting": key, "value": value})
+	}
+	return nil
+}
+
+// Watch reloads the settings when the runtime configuration file changes, until the context is
+// cancelled. It returns straight away when no file is set.
+func (s *Settings) Watch(ctx context.Context) error {
+	if s.file == "" {
+		return nil
+	}
+	return watch(ctx, []string{s.file}, func() {
+		if err := s.Reload(); err != nil {
+			logger.LogError("Failed to reload runtime configuration.", logrus.Fields{"file": s.file, "error": err.Error()})
+		}
+	})
+}
+
+// read returns the reloadable settings of the environment overridden by those of the file.
+func (s *Settings) read() (map[string]string, error) {
+	values := make(map[string]string, len(s.defaults))
+	for key, value := range s.defaults {
+		values[key] = value
+	}
+	if s.file == "" {
+		return values, nil
+	}
+
+	file, err := os.Open(s.file)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, found := strings.Cut(strings.TrimPrefix(line, "export "), "=")
+		if !found {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+			value = value[1 : len(value)-1]
+		}
+		if _, ok := settings[key]; ok {
+			values[key] = value
+		}
+	}
+	return values, scanner.Err()
+}
+
+// applyLogLevel sets the level of the common logger, which writes the logs of the service, and of the
+// standard logrus logger, info by default.
+func applyLogLevel(value string) error {
+	level := logrus.InfoLevel
+	if value != "" {
+		parsed, err := logrus.ParseLevel(value)
+		if err != nil {
+			return err
+		}
+		level = parsed
+	}
+	logger.Logger.SetLevel(level)
+	logrus.SetLevel(level)
+	return nil
+}
+
+// applyCORSAllowedOrigins sets the comma-separated origins allowed to make cross-origin requests, each
+// "*" or a scheme and host such as https://app.example.com. Empty restores the common CORS policy.
+func applyCORSAllowedOrigins(value string) error {
+	var origins []string
+	for _, origin := range strings.Split(value, ",") {
+		origin = strings.TrimSpace(origin)
+		if origin == "" {
+			continue
+		}
+		if origin != "*" {
+			parsed, err := url.Parse(origin)
+			if err != nil {
+				return err
+			}
+			if parsed.Scheme == "" || parsed.Host == "" || parsed.Path != "" || parsed.RawQuery != "" {
+				return fmt.Errorf("origin %q is not a scheme and host", origin)
+			}
+		}
+		origins = append(origins, origin)
+	}
+	middlewares.SetCORSAllowedOrigins(origins)
+	return nil
+}
+
+// applyEnvDuration returns a function validating a duration and setting it as the environment variable
+// of a setting read each time it is used.
+func applyEnvDuration(key string) func(value string) error {
+	return func(value string) error {
+		if value != "" {
+			if _, err := time.ParseDuration(value); err != nil {
+				return err
+			}
+		}
+		return os.Setenv(key, value)
+	}
+}
@@ -0,0 +1,50 @@
+package reload
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/san-data-systems/common/logger"
+	"github.com/sirupsen/logrus"
+)
+
+func TestApplyCORSAllowedOrigins(t *testing.T) {
+	t.Cleanup(func() { _ = applyCORSAllowedOrigins("") })
+	for _, value := range []string{"", "*", "https://app.example.com", "https://app.example.com, http://localhost:3000"} {
+		if err := applyCORSAllowedOrigins(value); err != nil {
+			t.Errorf("applyCORSAllowedOrigins(%q) = %v", value, err)
+		}
+	}
+	for _, value := range []string{"app.example.com", "https://app.example.com/", "https://app.example.com/path"} {
+		if err := applyCORSAllowedOrigins(value); err == nil {
+			t.Errorf("applyCORSAllowedOrigins(%q) accepted an invalid origin", value)
+		}
+	}
+}
+
+func TestReloadAppliesLogLevel(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "")
+	t.Cleanup(func() { _ = applyLogLevel("") })
+	file := filepath.Join(t.TempDir(), "runtime.env")
+	if err := os.WriteFile(file, []byte("LOG_LEVEL=warn\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSettings(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if level := logger.Logger.GetLevel(); level != logrus.WarnLevel {
+		t.Fatalf("level after start = %s, want warn", level)
+	}
+
+	if err := os.WriteFile(file, []byte("LOG_LEVEL=debug\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if level := logger.Logger.GetLevel(); level != logrus.DebugLevel {
+		t.Errorf("level after reload = %s, want debug", level)
+	}
+}
@@ -0,0 +1,68 @@
+// Package reload applies changes to files the service reads at startup without restarting it: the TLS
+// certificate, swapped in for new connections, and the runtime configuration file, whose settings that
+// are safe to change at runtime are applied again.
+package reload
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/san-data-systems/common/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// debounce is how long changes must settle before they are applied, since writing a file, or swapping
+// the files of a mounted Kubernetes secret, produces several events.
+const debounce = 500 * time.Millisecond
+
+// watch calls onChange when files change, until the context is cancelled. It watches the directories
+// holding the files rather than the files themselves, so that files replaced by a rename or a symlink
+// swap, as cert-manager and mounted secrets do, are still followed.
+func watch(ctx context.Context, files []string, onChange func()) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	watched := map[string]bool{}
+	for _, file := range files {
+		dir, err := filepath.Abs(filepath.Dir(file))
+		if err != nil {
+			return err
+		}
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			return err
+		}
+		watched[dir] = true
+	}
+
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op != fsnotify.Chmod {
+				timer.Reset(debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			logger.LogWarning("File watcher error.", logrus.Fields{"error": err.Error()})
+		case <-timer.C:
+			onChange()
+		}
+	}
+}
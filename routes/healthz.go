@@ -4,6 +4,7 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/project-management-api/services"
 )
 
 // HealthzRoute sets up health check routes
@@ -19,3 +20,23 @@ func HealthzRoute(router *gin.RouterGroup) {
 		})
 	}
 }
+
+// ProbeRoute sets up the Kubernetes probe routes. /livez reports that the process is serving requests,
+// and /readyz that its dependencies are reachable and it is not shutting down, with the status of each
+// dependency.
+func ProbeRoute(router *gin.RouterGroup) {
+	probes := router.Group("")
+	{
+		probes.GET("/livez", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"status": "ok"})
+		})
+		probes.GET("/readyz", func(c *gin.Context) {
+			readiness := services.CheckReadiness(c.Request.Context())
+			status := http.StatusOK
+			if !readiness.Ready() {
+				status = http.StatusServiceUnavailable
+			}
+			c.JSON(status, readiness)
+		})
+	}
+}
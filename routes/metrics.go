@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/project-management-api/metrics"
+)
+
+// MetricsRoute sets up the Prometheus metrics route
+func MetricsRoute(router *gin.RouterGroup) {
+	router.GET("/metrics", gin.WrapH(metrics.Handler()))
+}
@@ -2,16 +2,48 @@ package routes
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
 	"github.com/gin-gonic/gin"
 	scalar "github.com/san-data-systems/common/scaler"
-	"net/http"
+	"github.com/san-data-systems/project-management-api/docs"
 )
 
-// OpenAPISpec registers the /openapi.json endpoint for API documentation
-func OpenAPISpec(router *gin.RouterGroup) {
+// OpenAPISpec registers the documentation routes: the OpenAPI spec of the routes registered on the
+// engine, as JSON at /openapi.json, and its Scalar API reference at the root of the group. The spec is
+// generated on first use, once every route is registered, and lists the server it is fetched from first.
+func OpenAPISpec(router *gin.RouterGroup, engine *gin.Engine) {
+	var (
+		once    sync.Once
+		spec    docs.Spec
+		specErr error
+	)
+	generate := func() (docs.Spec, error) {
+		once.Do(func() {
+			// Drift fails TestOpenAPISpecHasNoDrift and openapi --check; the spec is served regardless.
+			spec, _, specErr = docs.Generate(engine.Routes())
+		})
+		return spec, specErr
+	}
+
+	specURL := router.BasePath() + "/openapi.json"
 	router.GET("/openapi.json", func(c *gin.Context) {
+		spec, err := generate()
+		if err != nil {
+			c.String(http.StatusInternalServerError, fmt.Sprintf("Error generating OpenAPI spec: %v", err))
+			return
+		}
+		c.JSON(http.StatusOK, spec.WithServers(map[string]interface{}{
+			"url":         requestOrigin(c) + docs.BasePath,
+			"description": "This server",
+		}))
+	})
+
+	router.GET("", func(c *gin.Context) {
 		htmlContent, err := scalar.ApiReferenceHTML(&scalar.Options{
-			SpecURL: "./docs/openapi.json",
+			SpecURL: specURL,
 			CustomOptions: scalar.CustomOptions{
 				PageTitle: "Project Management API",
 			},
@@ -26,3 +58,20 @@ func OpenAPISpec(router *gin.RouterGroup) {
 		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(htmlContent))
 	})
 }
+
+// requestOrigin returns the scheme and host a request was sent to, as seen by the client when a proxy
+// sets X-Forwarded-Proto and X-Forwarded-Host.
+func requestOrigin(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	host := c.Request.Host
+	if forwarded := strings.TrimSpace(strings.Split(c.GetHeader("X-Forwarded-Host"), ",")[0]); forwarded != "" {
+		host = forwarded
+	}
+	return scheme + "://" + host
+}
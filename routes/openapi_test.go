@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/project-management-api/docs"
+)
+
+func TestOpenAPISpecHasNoDrift(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	_, drift, err := docs.Generate(New().Routes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, d := range drift {
+		t.Errorf("%s: %s %s", d.Reason, d.Method, d.Path)
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/config"
 	"github.com/san-data-systems/common/middlewares"
+	"github.com/san-data-systems/project-management-api/metrics"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	"github.com/san-data-systems/project-management-api/tracing"
 )
 
 // service defines the service name.
@@ -24,31 +27,57 @@ func New() *gin.Engine {
 	initRoute(r)
 
 	r.Use(gin.CustomRecovery(middlewares.AppRecovery()))
-	r.Use(middlewares.CORSMiddleware())
+	r.Use(pmmiddlewares.CORS())
 	r.Use(middlewares.RequestIDMiddleware())
+	r.Use(tracing.Middleware(), tracing.RequestIDAttribute())
 	r.Use(middlewares.LoggerMiddleware()) // Log requests to both file and stdout
+	r.Use(metrics.Middleware())
 
+	operations := r.Group("")
+	{
+		ProbeRoute(operations)
+		MetricsRoute(operations)
+	}
 	docs := r.Group("/docs")
 	{
-		OpenAPISpec(docs)
+		OpenAPISpec(docs, r)
 	}
 	apiV1 := r.Group("/api/v1")
 	{
 		HealthzRoute(apiV1)
 		VersionRoute(apiV1)
 
-		v1.ProjectRoute(apiV1, middlewares.JWTMiddleware())
-		v1.ClientRoute(apiV1, middlewares.JWTMiddleware())
-		v1.ProjectLabelRoute(apiV1, middlewares.JWTMiddleware())
-		v1.ProjectStateRoute(apiV1, middlewares.JWTMiddleware())
-		v1.ProjectSlugRoute(apiV1, middlewares.JWTMiddleware())
-		v1.ProjectMember(apiV1, middlewares.JWTMiddleware())
-		v1.ProjectFileRoute(apiV1, middlewares.JWTMiddleware())
-		v1.IssueRoute(apiV1, middlewares.JWTMiddleware())
-		v1.IssueLinkRoute(apiV1, middlewares.JWTMiddleware())
-		v1.IssueAssigneeRoute(apiV1, middlewares.JWTMiddleware())
-		v1.IssueFileRoute(apiV1, middlewares.JWTMiddleware())
-		v1.IssueTimeEntryRoute(apiV1, middlewares.JWTMiddleware())
+		v1.ProjectRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ClientRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectLabelRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectStateRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectSlugRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectMember(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectFileRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueLinkRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueAssigneeRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueFileRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueTimeEntryRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.BillingRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.InvoiceRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.ProjectBudgetRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.NotificationRoute(apiV1, pmmiddlewares.Authenticate())
+		v1.WorkloadRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.CalendarRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.WorkflowRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.BoardRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.TemplateRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.TrashRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.PermissionRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.IssueCommentRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.InvitationRoute(apiV1, pmmiddlewares.Authenticate())
+		v1.ProjectInvitationRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.TeamRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.OrganizationRoute(apiV1, pmmiddlewares.Authenticate())
+		v1.AccessTokenRoute(apiV1, pmmiddlewares.Authenticate())
+		v1.ServiceAccountRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
+		v1.GroupMappingRoute(apiV1, pmmiddlewares.Authenticate(), pmmiddlewares.ResolveOrganization())
 	}
 	return r
 }
@@ -57,6 +86,9 @@ func New() *gin.Engine {
 func initRoute(r *gin.Engine) {
 	_ = r.SetTrustedProxies(nil)
 	r.RedirectTrailingSlash = false
+	// Let the gin context stand in for the request context, so that the trace of a request follows the
+	// calls it is passed to
+	r.ContextWithFallback = true
 	r.HandleMethodNotAllowed = true
 
 	r.NoRoute(func(c *gin.Context) {
@@ -0,0 +1,34 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// AccessTokenRoute sets up the routes for the personal access tokens of the current user.
+func AccessTokenRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	token := router.Group("", handlers...)
+	{
+		token.GET("/tokens", v1.ListAccessTokens)
+		token.POST("/token", v1.CreateAccessToken)
+		token.DELETE("/token/:token_id", v1.RevokeAccessToken)
+	}
+}
+
+// ServiceAccountRoute sets up the routes for service accounts, their access tokens and the projects
+// they belong to.
+func ServiceAccountRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	serviceAccount := router.Group("", handlers...)
+	{
+		serviceAccount.GET("/service-accounts", v1.ListServiceAccounts)
+		serviceAccount.POST("/service-account", v1.CreateServiceAccount)
+		serviceAccount.DELETE("/service-account/:service_account_id", v1.DeleteServiceAccount)
+		serviceAccount.GET("/service-account/:service_account_id/tokens", v1.ListServiceAccountTokens)
+		serviceAccount.POST("/service-account/:service_account_id/token", v1.CreateServiceAccountToken)
+		serviceAccount.DELETE("/service-account/:service_account_id/token/:token_id", v1.RevokeServiceAccountToken)
+		serviceAccount.POST("/project/:project_id/service-account", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.AddProjectServiceAccount)
+	}
+}
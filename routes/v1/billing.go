@@ -0,0 +1,26 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// BillingRoute sets up the routes for rate and time entry billing API endpoints.
+func BillingRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	billing := router.Group("", handlers...)
+	{
+		billing.GET("/client/:id/billing", validators.ClientIDValidator(), v1.GetClientBilling)
+		billing.PUT("/client/:id/billing", validators.ClientIDValidator(), v1.UpdateClientBilling)
+
+		billing.GET("/project/:project_id/billing", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), v1.GetProjectBilling)
+		billing.PUT("/project/:project_id/billing", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), pmmiddlewares.ProjectWritable(), v1.UpdateProjectBilling)
+		billing.PUT("/project/:project_id/billing/member-rate", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), pmmiddlewares.ProjectWritable(), v1.SetProjectMemberRate)
+		billing.DELETE("/project/:project_id/billing/member-rate/:email", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), pmmiddlewares.ProjectWritable(), validators.EmailValidator(), v1.DeleteProjectMemberRate)
+
+		billing.PUT("/project/:project_id/issue/:issue_id/time-entry/:te_id/billing", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionTimeLog), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), validators.TimeEntryIDValidator(), v1.UpdateTimeEntryBilling)
+		billing.POST("/project/:project_id/issue/:issue_id/time-entry/:te_id/approve", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionTimeApprove), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), validators.TimeEntryIDValidator(), v1.ApproveTimeEntry)
+	}
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// BoardRoute sets up the routes for the project board and work-in-progress limit API endpoints.
+func BoardRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	board := router.Group("", handlers...)
+	{
+		board.GET("/project/:project_id/board", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueView), v1.GetProjectBoard)
+		board.PUT("/project/:project_id/state/:state_id/wip-limit", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), validators.ProjectStateIDValidator(), v1.SetStateWIPLimit)
+		board.DELETE("/project/:project_id/state/:state_id/wip-limit", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), validators.ProjectStateIDValidator(), v1.DeleteStateWIPLimit)
+	}
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// ProjectBudgetRoute sets up the routes for project budget API endpoints.
+func ProjectBudgetRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	budget := router.Group("", handlers...)
+	{
+		budget.GET("/project/:project_id/budget", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), v1.GetProjectBudget)
+		budget.PUT("/project/:project_id/budget", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), pmmiddlewares.ProjectWritable(), v1.UpdateProjectBudget)
+		budget.GET("/project/:project_id/budget/consumption", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionBillingManage), v1.GetProjectBudgetConsumption)
+	}
+}
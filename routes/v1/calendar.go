@@ -0,0 +1,29 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+)
+
+// CalendarRoute sets up the routes for working calendar, holiday and time off API endpoints.
+func CalendarRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	calendar := router.Group("/calendar", handlers...)
+	{
+		calendar.GET("/organization", v1.GetOrganizationCalendar)
+		calendar.PUT("/organization", v1.UpdateOrganizationCalendar)
+
+		calendar.GET("/member", v1.GetMemberCalendar)
+		calendar.PUT("/member", v1.UpdateMemberCalendar)
+		calendar.DELETE("/member", v1.DeleteMemberCalendar)
+
+		calendar.GET("/holidays", v1.ListHolidays)
+		calendar.POST("/holiday", v1.CreateHoliday)
+		calendar.DELETE("/holiday/:holiday_id", v1.DeleteHoliday)
+
+		calendar.GET("/time-off", v1.ListTimeOff)
+		calendar.POST("/time-off", v1.CreateTimeOff)
+		calendar.DELETE("/time-off/:time_off_id", v1.DeleteTimeOff)
+
+		calendar.GET("/days", v1.GetCalendarDays)
+	}
+}
@@ -0,0 +1,16 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+)
+
+// GroupMappingRoute sets up the routes mapping identity provider groups to teams.
+func GroupMappingRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	mapping := router.Group("", handlers...)
+	{
+		mapping.GET("/group-mappings", v1.ListGroupMappings)
+		mapping.POST("/group-mapping", v1.CreateGroupMapping)
+		mapping.DELETE("/group-mapping/:group_mapping_id", v1.DeleteGroupMapping)
+	}
+}
@@ -0,0 +1,31 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// InvitationRoute sets up the routes for listing and answering the invitations of the user. They are
+// not scoped to an organization, since invitees join the organization of the project when they accept.
+func InvitationRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	invitation := router.Group("", handlers...)
+	{
+		invitation.GET("/invitations", v1.ListMyInvitations)
+		invitation.POST("/invitation/accept", v1.AcceptInvitation)
+		invitation.POST("/invitation/decline", v1.DeclineInvitation)
+	}
+}
+
+// ProjectInvitationRoute sets up the routes for sending, listing and revoking project invitations.
+func ProjectInvitationRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	invitation := router.Group("", handlers...)
+	{
+		invitation.POST("/project/:project_id/invitation", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.InviteProjectMember)
+		invitation.GET("/project/:project_id/invitations", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), v1.ListProjectInvitations)
+		invitation.POST("/project/:project_id/invitation/:invitation_id/resend", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.ResendProjectInvitation)
+		invitation.DELETE("/project/:project_id/invitation/:invitation_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), v1.RevokeProjectInvitation)
+	}
+}
@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+)
+
+// InvoiceRoute sets up the routes for invoice-related API endpoints.
+func InvoiceRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	invoice := router.Group("", handlers...)
+	{
+		invoice.POST("/client/:id/invoice", validators.ClientIDValidator(), v1.CreateClientInvoice)
+		invoice.GET("/client/:id/invoices", validators.ClientIDValidator(), v1.ListClientInvoices)
+		invoice.GET("/invoice/:invoice_id", v1.GetInvoiceByID)
+		invoice.GET("/invoice/:invoice_id/export", v1.ExportInvoice)
+		invoice.POST("/invoice/:invoice_id/issue", v1.IssueInvoice)
+		invoice.DELETE("/invoice/:invoice_id", v1.VoidInvoice)
+	}
+}
@@ -0,0 +1,19 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// IssueCommentRoute sets up the routes for issue comments.
+func IssueCommentRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	issueComment := router.Group("", handlers...)
+	{
+		issueComment.GET("/project/:project_id/issue/:issue_id/comments", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueView), validators.IssueIDValidator(), v1.ListIssueComments)
+		issueComment.POST("/project/:project_id/issue/:issue_id/comment", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueComment), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), v1.CreateIssueComment)
+		issueComment.DELETE("/project/:project_id/issue/:issue_id/comment/:comment_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueComment), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), v1.DeleteIssueComment)
+	}
+}
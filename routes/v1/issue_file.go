@@ -4,6 +4,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // IssueFileRoute sets up the routes for task-related API endpoints.
@@ -11,8 +13,8 @@ func IssueFileRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 	issueFile := router.Group("", handlers...)
 	{
 		// Issue File
-		issueFile.POST("/project/:project_id/issue/:issue_id/files", validators.ProjectIDValidator(), validators.IssueIDValidator(), v1.UploadIssueFiles)
-		issueFile.GET("/project/:project_id/issue/:issue_id/files", validators.ProjectIDValidator(), validators.IssueIDValidator(), v1.GetIssueFiles)
-		issueFile.DELETE("/project/:project_id/issue/:issue_id/file/:file_id", validators.ProjectIDValidator(), validators.IssueIDValidator(), v1.DeleteIssueFileByID)
+		issueFile.POST("/project/:project_id/issue/:issue_id/files", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionFileUpload), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), v1.UploadIssueFiles)
+		issueFile.GET("/project/:project_id/issue/:issue_id/files", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueView), validators.IssueIDValidator(), v1.GetIssueFiles)
+		issueFile.DELETE("/project/:project_id/issue/:issue_id/file/:file_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionFileDelete), pmmiddlewares.ProjectWritable(), validators.IssueIDValidator(), v1.DeleteIssueFileByID)
 	}
 }
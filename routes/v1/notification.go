@@ -0,0 +1,16 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+)
+
+// NotificationRoute sets up the routes for the notification inbox of the current user.
+func NotificationRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	notification := router.Group("", handlers...)
+	{
+		notification.GET("/notifications", v1.ListNotifications)
+		notification.POST("/notifications/read", v1.MarkAllNotificationsRead)
+		notification.POST("/notification/:notification_id/read", v1.MarkNotificationRead)
+	}
+}
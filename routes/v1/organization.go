@@ -0,0 +1,22 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+)
+
+// OrganizationRoute sets up the routes for organizations and their members. They select the
+// organization by the organization_id parameter, an ID or slug, rather than by the X-Organization header.
+func OrganizationRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	organization := router.Group("", handlers...)
+	{
+		organization.GET("/organizations", v1.ListOrganizations)
+		organization.POST("/organization", v1.CreateOrganization)
+		organization.GET("/organization/:organization_id", v1.GetOrganization)
+		organization.PUT("/organization/:organization_id", v1.UpdateOrganization)
+		organization.POST("/organization/:organization_id/member", v1.AddOrganizationMember)
+		organization.PUT("/organization/:organization_id/member/:email", validators.EmailValidator(), v1.UpdateOrganizationMember)
+		organization.DELETE("/organization/:organization_id/member/:email", validators.EmailValidator(), v1.RemoveOrganizationMember)
+	}
+}
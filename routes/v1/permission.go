@@ -0,0 +1,23 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// PermissionRoute sets up the routes for roles, custom roles and project permissions.
+func PermissionRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	permission := router.Group("", handlers...)
+	{
+		permission.GET("/permissions", v1.ListPermissions)
+		permission.GET("/roles", v1.ListRoles)
+		permission.POST("/role", v1.CreateCustomRole)
+		permission.PUT("/role/:role_id", v1.UpdateCustomRole)
+		permission.DELETE("/role/:role_id", v1.DeleteCustomRole)
+		permission.GET("/project/:project_id/permissions", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectPermissions)
+		permission.PUT("/project/:project_id/member/email/:email/role", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), validators.EmailValidator(), v1.UpdateProjectMemberRole)
+	}
+}
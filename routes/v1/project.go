@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // ProjectRoute sets up the routes for task-related API endpoints.
@@ -13,11 +15,13 @@ func ProjectRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 	project := router.Group("", handlers...)
 	{
 		project.POST("/project", validators.CreateProjectValidator(), v1.CreateProject)
-		project.GET("/project/:project_id", validators.ProjectIDValidator(), v1.GetProjectByID)
-		project.PUT("/project/:project_id", validators.ProjectIDValidator(), v1.UpdateProjectByID)
-		project.DELETE("/project/:project_id", validators.ProjectIDValidator(), v1.DeleteProjectByID)
+		project.GET("/project/:project_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectByID)
+		project.PUT("/project/:project_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectEdit), v1.UpdateProjectByID)
+		project.DELETE("/project/:project_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.DeleteProjectByID)
+		project.POST("/project/:project_id/archive", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.ArchiveProject)
+		project.POST("/project/:project_id/unarchive", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.UnarchiveProject)
 		project.GET("/projects", v1.ListProjects)
-		project.GET("/project/:project_id/stats", validators.ProjectIDValidator(), v1.GetProjectStatsByID)
-		project.GET("/project/:project_id/activities", validators.ProjectIDValidator(), v1.ListProjectActivitiesByID)
+		project.GET("/project/:project_id/stats", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectStatsByID)
+		project.GET("/project/:project_id/activities", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.ListProjectActivitiesByID)
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // ProjectFileRoute sets up the routes for task-related API endpoints.
@@ -13,9 +15,9 @@ func ProjectFileRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 	projectFile := router.Group("", handlers...)
 	{
 		// Project File
-		projectFile.POST("/project/:project_id/cover", validators.ProjectIDValidator(), v1.ChangeProjectCoverImageByID)
-		projectFile.POST("/project/:project_id/files", validators.ProjectIDValidator(), v1.UploadProjectFiles)
-		projectFile.GET("/project/:project_id/files", validators.ProjectIDValidator(), v1.GetProjectFiles)
-		projectFile.DELETE("/project/:project_id/file/:file_id", validators.ProjectIDValidator(), validators.ProjectFileIDValidator(), v1.DeleteProjectFileByID)
+		projectFile.POST("/project/:project_id/cover", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectEdit), pmmiddlewares.ProjectWritable(), v1.ChangeProjectCoverImageByID)
+		projectFile.POST("/project/:project_id/files", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionFileUpload), pmmiddlewares.ProjectWritable(), v1.UploadProjectFiles)
+		projectFile.GET("/project/:project_id/files", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectFiles)
+		projectFile.DELETE("/project/:project_id/file/:file_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionFileDelete), pmmiddlewares.ProjectWritable(), validators.ProjectFileIDValidator(), v1.DeleteProjectFileByID)
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // ProjectLabelRoute sets up the routes for label-related API endpoints.
@@ -15,12 +17,15 @@ func ProjectLabelRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 		// Create a new label for a project
 		projectLabels.POST("/project/:project_id/label",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionLabelManage),
+			pmmiddlewares.ProjectWritable(),
 			v1.CreateProjectLabel,
 		)
 
 		// Get a specific label by ID for a project
 		projectLabels.GET("/project/:project_id/label/:label_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionProjectView),
 			validators.ProjectLabelIDValidator(),
 			v1.GetProjectLabelByID,
 		)
@@ -28,6 +33,8 @@ func ProjectLabelRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 		// Update a label for a project by ID
 		projectLabels.PUT("/project/:project_id/label/:label_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionLabelManage),
+			pmmiddlewares.ProjectWritable(),
 			validators.ProjectLabelIDValidator(),
 			v1.UpdateProjectLabelByID,
 		)
@@ -35,6 +42,8 @@ func ProjectLabelRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 		// Delete a label for a project by ID
 		projectLabels.DELETE("/project/:project_id/label/:label_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionLabelManage),
+			pmmiddlewares.ProjectWritable(),
 			validators.ProjectLabelIDValidator(),
 			v1.DeleteProjectLabelByID,
 		)
@@ -42,6 +51,7 @@ func ProjectLabelRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 		// List all labels for a project, with pagination
 		projectLabels.GET("/project/:project_id/labels",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionProjectView),
 			v1.ListProjectLabels,
 		)
 	}
@@ -6,21 +6,24 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // ProjectMember sets up the routes for task-related API endpoints.
 func ProjectMember(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 	projectMember := router.Group("", handlers...)
 	{
-		projectMember.POST("/project/:project_id/members/operation", validators.ProjectIDValidator(), validators.ProjectMemberOperationsRequestValidator(), v1.AddORRemoveProjectMembers)
-		projectMember.GET("/project/:project_id/members", validators.ProjectIDValidator(), v1.GetProjectMembers)
+		projectMember.POST("/project/:project_id/members/operation", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), validators.ProjectMemberOperationsRequestValidator(), v1.AddORRemoveProjectMembers)
+		projectMember.GET("/project/:project_id/members", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectMembers)
 
-		projectMember.GET("/project/:project_id/member/email/:email", validators.ProjectIDValidator(), validators.EmailValidator(), v1.GetProjectMemberByEmail)
-		projectMember.GET("/project/:project_id/member/id/:member_id", validators.ProjectIDValidator(), validators.ProjectMemberIDValidator(), v1.GetProjectMemberByID)
+		projectMember.GET("/project/:project_id/member/email/:email", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), validators.EmailValidator(), v1.GetProjectMemberByEmail)
+		projectMember.GET("/project/:project_id/member/id/:member_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), validators.ProjectMemberIDValidator(), v1.GetProjectMemberByID)
 
-		projectMember.DELETE("/project/:project_id/member/email/:email", validators.ProjectIDValidator(), validators.EmailValidator(), v1.DeleteProjectMemberByEmail)
-		projectMember.DELETE("/project/:project_id/member/id/:member_id", validators.ProjectIDValidator(), validators.ProjectMemberIDValidator(), v1.DeleteProjectMemberByID)
-		projectMember.POST("/project/:project_id/member", validators.ProjectIDValidator(), validators.CreateProjectMemberValidator(), v1.AddSingleProjectMembers)
+		projectMember.DELETE("/project/:project_id/member/email/:email", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), validators.EmailValidator(), v1.DeleteProjectMemberByEmail)
+		projectMember.DELETE("/project/:project_id/member/id/:member_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), validators.ProjectMemberIDValidator(), v1.DeleteProjectMemberByID)
+		projectMember.POST("/project/:project_id/member", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), validators.CreateProjectMemberValidator(), v1.AddSingleProjectMembers)
+		projectMember.POST("/project/:project_id/ownership/transfer", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.TransferProjectOwnership)
 
 	}
 }
@@ -6,6 +6,8 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/san-data-systems/common/validators"
 	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
 )
 
 // ProjectStateRoute sets up the routes for task-related API endpoints.
@@ -14,26 +16,33 @@ func ProjectStateRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
 	{
 		projectState.POST("/project/:project_id/state",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionStateManage),
+			pmmiddlewares.ProjectWritable(),
 			validators.CreateProjectStateValidator(),
 			v1.CreateProjectState,
 		)
 		projectState.GET("/project/:project_id/state/:state_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionProjectView),
 			validators.ProjectStateIDValidator(),
 			v1.GetProjectStateByID,
 		)
 		projectState.PUT("/project/:project_id/state/:state_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionStateManage),
+			pmmiddlewares.ProjectWritable(),
 			validators.ProjectStateIDValidator(),
 			validators.UpdateProjectStateValidator(),
 			v1.UpdateProjectStateByID,
 		)
 		projectState.DELETE("/project/:project_id/state/:state_id",
 			validators.ProjectIDValidator(),
+			pmmiddlewares.RequirePermission(pmv1.PermissionStateManage),
+			pmmiddlewares.ProjectWritable(),
 			validators.ProjectStateIDValidator(),
 			v1.DeleteProjectStateByID,
 		)
-		projectState.GET("/project/:project_id/states", validators.ProjectIDValidator(), v1.ListProjectStates)
-		projectState.PUT("/project/:project_id/states", validators.ProjectIDValidator(), validators.ProjectStatesSequenceUpdateValidator(), v1.UpdateProjectStatesSequence)
+		projectState.GET("/project/:project_id/states", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.ListProjectStates)
+		projectState.PUT("/project/:project_id/states", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), validators.ProjectStatesSequenceUpdateValidator(), v1.UpdateProjectStatesSequence)
 	}
 }
@@ -0,0 +1,27 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// TeamRoute sets up the routes for teams, their members and the projects they belong to.
+func TeamRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	team := router.Group("", handlers...)
+	{
+		team.GET("/teams", v1.ListTeams)
+		team.POST("/team", v1.CreateTeam)
+		team.GET("/team/:team_id", v1.GetTeam)
+		team.PUT("/team/:team_id", v1.UpdateTeam)
+		team.DELETE("/team/:team_id", v1.DeleteTeam)
+		team.POST("/team/:team_id/members", v1.AddTeamMembers)
+		team.DELETE("/team/:team_id/member/:email", validators.EmailValidator(), v1.RemoveTeamMember)
+		team.GET("/project/:project_id/teams", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.ListProjectTeams)
+		team.POST("/project/:project_id/team", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.AddProjectTeam)
+		team.PUT("/project/:project_id/team/:team_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.UpdateProjectTeam)
+		team.DELETE("/project/:project_id/team/:team_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), pmmiddlewares.ProjectWritable(), v1.RemoveProjectTeam)
+	}
+}
@@ -0,0 +1,25 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// TemplateRoute sets up the routes for project templates, saved views and project cloning API endpoints.
+func TemplateRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	template := router.Group("", handlers...)
+	{
+		template.GET("/project-templates", v1.ListProjectTemplates)
+		template.POST("/project-templates", v1.CreateProjectTemplate)
+		template.GET("/project-template/:template_id", v1.GetProjectTemplate)
+		template.DELETE("/project-template/:template_id", v1.DeleteProjectTemplate)
+		template.POST("/project/:project_id/template", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectEdit), v1.CaptureProjectTemplate)
+		template.POST("/project/:project_id/clone", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectEdit), v1.CloneProject)
+		template.GET("/project/:project_id/views", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.ListSavedViews)
+		template.POST("/project/:project_id/view", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), pmmiddlewares.ProjectWritable(), v1.CreateSavedView)
+		template.DELETE("/project/:project_id/view/:view_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), pmmiddlewares.ProjectWritable(), v1.DeleteSavedView)
+	}
+}
@@ -0,0 +1,24 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// TrashRoute sets up the routes for listing, restoring and purging soft-deleted entities.
+func TrashRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	trash := router.Group("", handlers...)
+	{
+		trash.GET("/trash", v1.ListTrash)
+		trash.POST("/trash/project/:project_id/restore", pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.RestoreTrashedProject)
+		trash.DELETE("/trash/project/:project_id", pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), v1.PurgeTrashedProject)
+		trash.POST("/trash/client/:id/restore", v1.RestoreTrashedClient)
+		trash.DELETE("/trash/client/:id", v1.PurgeTrashedClient)
+		trash.GET("/project/:project_id/trash", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueView), v1.ListProjectTrash)
+		trash.POST("/project/:project_id/trash/:kind/:item_id/restore", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), pmmiddlewares.ProjectWritable(), v1.RestoreProjectTrashItem)
+		trash.DELETE("/project/:project_id/trash/:kind/:item_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectAdmin), pmmiddlewares.ProjectWritable(), v1.PurgeProjectTrashItem)
+	}
+}
@@ -0,0 +1,21 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// WorkflowRoute sets up the routes for state category, transition rule and bulk move API endpoints.
+func WorkflowRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	workflow := router.Group("", handlers...)
+	{
+		workflow.GET("/project/:project_id/workflow", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionProjectView), v1.GetProjectWorkflow)
+		workflow.PUT("/project/:project_id/state/:state_id/category", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), validators.ProjectStateIDValidator(), v1.UpdateProjectStateCategory)
+		workflow.POST("/project/:project_id/workflow/transition", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), v1.CreateStateTransition)
+		workflow.DELETE("/project/:project_id/workflow/transition/:transition_id", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionStateManage), pmmiddlewares.ProjectWritable(), v1.DeleteStateTransition)
+		workflow.POST("/project/:project_id/issues/move", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionIssueEdit), pmmiddlewares.ProjectWritable(), v1.BulkMoveIssues)
+	}
+}
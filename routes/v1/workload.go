@@ -0,0 +1,20 @@
+package v1
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/validators"
+	v1 "github.com/san-data-systems/project-management-api/controllers/v1"
+	pmmiddlewares "github.com/san-data-systems/project-management-api/middlewares"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// WorkloadRoute sets up the routes for workload and capacity API endpoints.
+func WorkloadRoute(router *gin.RouterGroup, handlers ...gin.HandlerFunc) {
+	workload := router.Group("", handlers...)
+	{
+		workload.GET("/workload", v1.GetWorkload)
+		workload.GET("/workload/capacity", v1.GetMemberCapacity)
+		workload.PUT("/workload/capacity", v1.UpdateMemberCapacity)
+		workload.GET("/project/:project_id/workload", validators.ProjectIDValidator(), pmmiddlewares.RequirePermission(pmv1.PermissionMemberManage), v1.GetProjectWorkload)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/san-data-systems/common/databases"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/services"
+	"gorm.io/gorm"
+)
+
+// errAlreadySeeded stops the seed transaction when the demo organization exists.
+var errAlreadySeeded = errors.New("already seeded")
+
+// demoDefinition is the structure of the demo project.
+var demoDefinition = pmv1.TemplateDefinition{
+	States: []pmv1.TemplateState{
+		{Name: "Backlog", Sequence: 1, Category: "backlog"},
+		{Name: "To Do", Sequence: 2, Category: "unstarted"},
+		{Name: "In Progress", Sequence: 3, Category: "started"},
+		{Name: "Done", Sequence: 4, Category: "completed"},
+	},
+	Labels: []pmv1.TemplateLabel{
+		{Name: "Design", Color: "#8e44ad"},
+		{Name: "Frontend", Color: "#2980b9"},
+		{Name: "Bug", Color: "#c0392b"},
+	},
+	Issues: []pmv1.TemplateIssue{
+		{Key: 1, Title: "Audit the current website", Priority: "High", State: "Done", Labels: []string{"Design"}, DurationDays: 5},
+		{Key: 2, Title: "Design the new home page", Priority: "High", State: "In Progress", Labels: []string{"Design"}, StartOffsetDays: 5, DurationDays: 10},
+		{Key: 3, Title: "Build the home page", Priority: "Medium", State: "To Do", Labels: []string{"Frontend"}, StartOffsetDays: 15, DurationDays: 10},
+		{Key: 4, ParentKey: 3, Title: "Responsive navigation", Priority: "Medium", State: "To Do", Labels: []string{"Frontend"}, StartOffsetDays: 15, DurationDays: 3},
+		{Key: 5, Title: "Fix broken links in the footer", Priority: "Low", State: "Backlog", Labels: []string{"Bug"}, StartOffsetDays: 20, DurationDays: 1},
+	},
+}
+
+// runSeed creates a demo organization with a client and a project owned by the given user. It does
+// nothing when the organization already exists.
+func runSeed(args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "seed --email <email> [--organization <slug>]", "Create a demo organization, client and project. The schema must be migrated first.")
+	email := flags.String("email", "", "email of the demo organization admin and project owner (required)")
+	slug := flags.String("organization", "demo", "slug of the demo organization")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, err := mail.ParseAddress(*email); err != nil || *email == "" {
+		flags.Usage()
+		return errors.New("a valid --email is required")
+	}
+	if !services.ValidOrganizationSlug(*slug) {
+		return fmt.Errorf("invalid organization slug %q", *slug)
+	}
+
+	databases.InitPostgresDB()
+	err := databases.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := services.FindOrganization(tx, *slug); err != gorm.ErrRecordNotFound {
+			if err == nil {
+				return errAlreadySeeded
+			}
+			return err
+		}
+
+		organization := pmv1.Organization{Name: "Demo", Slug: *slug, CreatedBy: *email}
+		if err := tx.Create(&organization).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&pmv1.OrganizationMember{
+			OrganizationID: organization.ID,
+			Email:          *email,
+			Role:           pmv1.OrganizationRoleAdmin,
+			AddedBy:        *email,
+		}).Error; err != nil {
+			return err
+		}
+
+		client := v1.Client{Name: "Acme Corp", ManagerEmails: pq.StringArray{*email}, Country: "Japan", CreatedBy: *email}
+		if err := tx.Create(&client).Error; err != nil {
+			return err
+		}
+		if err := services.AssignClient(tx, organization.ID, client.ID); err != nil {
+			return err
+		}
+
+		start := time.Now().Truncate(24 * time.Hour)
+		project := v1.Project{
+			Name:        "Website Redesign",
+			Slug:        "WEBR",
+			Description: "Demo project created by the seed command.",
+			ClientID:    client.ID,
+			StartDate:   start,
+			EndDate:     start.AddDate(0, 3, 0),
+			Status:      "Not Started",
+			Tags:        pq.StringArray{"demo"},
+			CreatedBy:   *email,
+		}
+		if err := tx.Create(&project).Error; err != nil {
+			return err
+		}
+		if err := services.AssignProject(tx, organization.ID, project.ID); err != nil {
+			return err
+		}
+		if err := services.SetProjectRole(tx, project.ID, *email, pmv1.RoleOwner); err != nil {
+			return err
+		}
+		return services.ApplyProjectDefinition(tx, project, demoDefinition, *email)
+	})
+	if err == errAlreadySeeded {
+		fmt.Printf("Organization %q already exists, nothing to seed.\n", *slug)
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to seed demo data: %w", err)
+	}
+
+	fmt.Printf("Seeded organization %q with a demo client and project owned by %s.\n", *slug, *email)
+	return nil
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/san-data-systems/common/clients/minio"
+	"github.com/san-data-systems/common/config"
+	"github.com/san-data-systems/common/databases"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/project-management-api/metrics"
+	"github.com/san-data-systems/project-management-api/migrations"
+	"github.com/san-data-systems/project-management-api/reload"
+	"github.com/san-data-systems/project-management-api/routes"
+	"github.com/san-data-systems/project-management-api/services"
+	"github.com/san-data-systems/project-management-api/tracing"
+	"github.com/sirupsen/logrus"
+)
+
+// runServe starts the Project Management API server and blocks until it is shut down.
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "serve", "Start the API server.")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	// Trace requests, queries and storage calls, exporting the spans when an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to configure tracing: %w", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			logger.LogError("Failed to flush traces.", logrus.Fields{"error": err.Error()})
+		}
+	}()
+
+	delay, timeout, err := shutdownDurations()
+	if err != nil {
+		return err
+	}
+
+	// Apply the settings that can change at runtime, from RUNTIME_CONFIG_FILE when it is set
+	runtimeSettings, err := reload.NewSettings(os.Getenv("RUNTIME_CONFIG_FILE"))
+	if err != nil {
+		return fmt.Errorf("failed to load runtime configuration: %w", err)
+	}
+
+	// Initialize PostgresQL database
+	databases.InitPostgresDB()
+
+	// Export query and connection pool metrics of the database, and trace its queries
+	if err := databases.DB.Use(metrics.GORMPlugin{}); err != nil {
+		return fmt.Errorf("failed to instrument database: %w", err)
+	}
+	if err := databases.DB.Use(tracing.GORMPlugin{}); err != nil {
+		return fmt.Errorf("failed to instrument database: %w", err)
+	}
+	sqlDB, err := databases.DB.DB()
+	if err != nil {
+		return err
+	}
+	if err := metrics.RegisterDBStats(sqlDB, config.Config.DatabaseName); err != nil {
+		return fmt.Errorf("failed to register database metrics: %w", err)
+	}
+
+	// Apply pending migrations of the tables owned by this service, unless MIGRATE_ON_STARTUP is false
+	migrateOnStartup := true
+	if value := os.Getenv("MIGRATE_ON_STARTUP"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid MIGRATE_ON_STARTUP %q", value)
+		}
+		migrateOnStartup = parsed
+	}
+	if migrateOnStartup {
+		applied, err := migrations.Up(context.Background(), sqlDB)
+		if err != nil {
+			return fmt.Errorf("failed to migrate database: %w", err)
+		}
+		for _, migration := range applied {
+			logger.LogInfo("Applied database migration.", logrus.Fields{"version": migration.Version, "name": migration.Name})
+		}
+	}
+
+	// Optionally, initialize Redis if enabled in the config
+	if config.Config.UseRedis {
+		databases.CheckRedisConnection()
+	}
+
+	// Optionally, initialize Redis if enabled in the config
+	if config.Config.UseMinIO {
+		client, err := minio.NewMinIOClient(
+			config.Config.MinIOEndpoint,
+			config.Config.MinIOAccessKey,
+			config.Config.MinIOSecretKey,
+			config.Config.MinIOSSL,
+		)
+		if err != nil {
+			logger.LogError("Failed to initialize MinIO client.", logrus.Fields{"error": err.Error()})
+		}
+
+		// Example usage of the MinIO client
+		err = client.CheckConnection(context.Background())
+		if err != nil {
+			logger.LogError("Failed to check connection.", logrus.Fields{"error": err.Error()})
+		}
+	}
+
+	// Send emails, such as project invitations, through the configured sender
+	mailer, err := services.MailerFromEnv()
+	if err != nil {
+		return fmt.Errorf("failed to configure mail sender: %w", err)
+	}
+	services.SetMailer(mailer)
+
+	// Accept tokens of the OIDC identity provider, when one is configured, alongside the service's JWTs
+	verifier, err := services.OIDCVerifierFromEnv(context.Background())
+	if err != nil {
+		return fmt.Errorf("failed to configure OIDC authentication: %w", err)
+	}
+	services.SetOIDCVerifier(verifier)
+
+	// Purge rows that have been in the trash for longer than the retention period, when one is set
+	workers := newBackgroundWorkers()
+	if retentionDays := services.TrashRetentionDays(); retentionDays > 0 {
+		workers.Go(func(ctx context.Context) {
+			services.RunTrashRetention(ctx, databases.DB, retentionDays, time.Hour)
+		})
+	} else {
+		logger.LogInfo("Trash retention is disabled; trashed rows are kept until purged by hand.", nil)
+	}
+
+	// Apply changes to the runtime configuration file
+	workers.Go(func(ctx context.Context) {
+		if err := runtimeSettings.Watch(ctx); err != nil {
+			logger.LogError("Failed to watch runtime configuration.", logrus.Fields{"error": err.Error()})
+		}
+	})
+
+	// Initialize the Gin router with defined routes
+	routes.InitGin()
+	router := routes.New()
+
+	// Define HTTP server with proper timeouts
+	server := &http.Server{
+		Addr:         config.Config.ServerHost + ":" + config.Config.ServerPort,
+		WriteTimeout: time.Second * 30,
+		ReadTimeout:  time.Second * 30,
+		IdleTimeout:  time.Second * 30,
+		Handler:      router,
+	}
+
+	// Start the server with SSL if Mode is 'release' and TLS_CERT/TLS_KEY exist
+	if config.Config.Mode == "release" && config.Config.TLSKey != "" && config.Config.TLSCert != "" {
+		// Serve with TLS (SSL), picking up rotated certificates without a restart
+		certificate, err := reload.NewCertificate(config.Config.TLSCert, config.Config.TLSKey)
+		if err != nil {
+			return fmt.Errorf("failed to load TLS certificate: %w", err)
+		}
+		server.TLSConfig = certificate.TLSConfig()
+		workers.Go(func(ctx context.Context) {
+			if err := certificate.Watch(ctx); err != nil {
+				logger.LogError("Failed to watch TLS certificate.", logrus.Fields{"error": err.Error()})
+			}
+		})
+
+		go func() {
+			log.Printf("Server started on https://%s:%s", config.Config.ServerHost, config.Config.ServerPort)
+			log.Printf("API reference can be accessed on https://%s:%s/docs", config.Config.ServerHost, config.Config.ServerPort)
+			if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Server failed to start: %v", err)
+			}
+		}()
+	} else {
+		// Serve without SSL
+		go func() {
+			log.Printf("Server started on http://%s:%s", config.Config.ServerHost, config.Config.ServerPort)
+			log.Printf("API reference can be accessed on http://%s:%s/docs", config.Config.ServerHost, config.Config.ServerPort)
+			if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Fatalf("Server failed to start: %v", err)
+			}
+		}()
+	}
+
+	// Gracefully handle server shutdown
+	handleGracefulShutdown(server, workers, sqlDB, delay, timeout)
+	return nil
+}
+
+// shutdownDurations returns how long shutdown waits after failing readiness before it stops accepting
+// connections, SHUTDOWN_DELAY (5s by default), and how long it then gives in-flight requests and
+// background workers to finish, SHUTDOWN_TIMEOUT (20s by default). Together they should stay below the
+// termination grace period of the pod.
+func shutdownDurations() (delay, timeout time.Duration, err error) {
+	delay, timeout = 5*time.Second, 20*time.Second
+	for key, duration := range map[string]*time.Duration{"SHUTDOWN_DELAY": &delay, "SHUTDOWN_TIMEOUT": &timeout} {
+		value := os.Getenv(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return 0, 0, fmt.Errorf("invalid %s %q", key, value)
+		}
+		*duration = parsed
+	}
+	return delay, timeout, nil
+}
+
+// handleGracefulShutdown waits for SIGINT or SIGTERM and shuts the server down. Readiness fails first and
+// the server keeps serving for the pre-stop delay, while load balancers stop routing traffic to it. It
+// then stops accepting connections and waits for in-flight requests, stops the background workers, and
+// closes the Redis client, when Redis is enabled, and the database pool last. A second signal exits
+// immediately.
+func handleGracefulShutdown(server *http.Server, workers *backgroundWorkers, db *sql.DB, delay, timeout time.Duration) {
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	sig := <-quit
+	log.Printf("%s received. Shutting down server...", sig)
+	go func() {
+		<-quit
+		log.Println("Second shutdown signal received. Exiting immediately.")
+		os.Exit(1)
+	}()
+
+	// Fail readiness first so that no new traffic is routed to this instance
+	services.SetDraining()
+	if delay > 0 {
+		log.Printf("Waiting %s for traffic to be routed away...", delay)
+		time.Sleep(delay)
+	}
+
+	// Create a deadline context for draining requests and stopping workers
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// Stop accepting connections and wait for in-flight requests to complete
+	if err := server.Shutdown(ctx); err != nil {
+		log.Printf("Server forced to shutdown: %v", err)
+		_ = server.Close()
+	}
+
+	// Stop the background workers and wait for the work in progress
+	if err := workers.Stop(ctx); err != nil {
+		log.Printf("Background workers did not stop in time: %v", err)
+	}
+
+	// Close the Redis client and the database pool once nothing uses them any more
+	if config.Config.UseRedis && databases.RedisClient != nil {
+		if err := databases.RedisClient.Close(); err != nil {
+			log.Printf("Failed to close Redis connections: %v", err)
+		}
+	}
+	if err := db.Close(); err != nil {
+		log.Printf("Failed to close database connections: %v", err)
+	}
+
+	log.Println("Server gracefully stopped.")
+}
+
+// backgroundWorkers runs the goroutines doing work outside of requests, such as scheduled jobs, so that
+// shutdown can stop them and wait for them.
+type backgroundWorkers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newBackgroundWorkers returns an empty set of background workers.
+func newBackgroundWorkers() *backgroundWorkers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &backgroundWorkers{ctx: ctx, cancel: cancel}
+}
+
+// Go starts a worker. The worker must return once its context is cancelled.
+func (w *backgroundWorkers) Go(run func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		run(w.ctx)
+	}()
+}
+
+// Stop cancels the workers and waits for them to return, or for the context to expire.
+func (w *backgroundWorkers) Stop(ctx context.Context) error {
+	w.cancel()
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidAccessToken is returned by AuthenticateAccessToken for unknown, revoked and expired tokens,
+// and for tokens of deleted service accounts.
+var ErrInvalidAccessToken = errors.New("invalid access token")
+
+// accessTokenUsageInterval is how often the last use of an access token is recorded, so that a busy
+// token does not write on every request.
+const accessTokenUsageInterval = time.Minute
+
+// serviceAccountSlugPattern matches the characters replaced when turning a service account name into
+// the local part of its email address.
+var serviceAccountSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// NewAccessToken generates a random access token and returns it with its hash and the hint shown to
+// identify it afterwards.
+func NewAccessToken() (token, hash, hint string, err error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	token = pmv1.AccessTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashAccessToken(token), pmv1.AccessTokenPrefix + "…" + token[len(token)-4:], nil
+}
+
+// HashAccessToken returns the hash under which an access token is stored.
+func HashAccessToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// AccessTokenExpiry returns the expiry of an access token created now and valid for the given number of
+// days, or DefaultAccessTokenExpiryDays when days is nil.
+func AccessTokenExpiry(days *int) time.Time {
+	validity := pmv1.DefaultAccessTokenExpiryDays
+	if days != nil {
+		validity = *days
+	}
+	return time.Now().AddDate(0, 0, validity)
+}
+
+// AuthenticateAccessToken loads the access token matching a bearer token and records its use. It returns
+// ErrInvalidAccessToken when the token cannot be used.
+func AuthenticateAccessToken(tx *gorm.DB, token, ip string) (pmv1.AccessToken, error) {
+	var accessToken pmv1.AccessToken
+	err := tx.Where("token_hash = ?", HashAccessToken(token)).First(&accessToken).Error
+	if err == gorm.ErrRecordNotFound {
+		return accessToken, ErrInvalidAccessToken
+	}
+	if err != nil {
+		return accessToken, err
+	}
+
+	now := time.Now()
+	if accessToken.RevokedAt != nil || !now.Before(accessToken.ExpiresAt) {
+		return accessToken, ErrInvalidAccessToken
+	}
+	if accessToken.ServiceAccountID != nil {
+		var count int64
+		if err := tx.Model(&pmv1.ServiceAccount{}).Where("id = ?", *accessToken.ServiceAccountID).Count(&count).Error; err != nil {
+			return accessToken, err
+		}
+		if count == 0 {
+			return accessToken, ErrInvalidAccessToken
+		}
+	}
+
+	if accessToken.LastUsedAt == nil || now.Sub(*accessToken.LastUsedAt) >= accessTokenUsageInterval || accessToken.LastUsedIP != ip {
+		accessToken.LastUsedAt = &now
+		accessToken.LastUsedIP = ip
+		if err := tx.Model(&accessToken).Updates(map[string]interface{}{"last_used_at": now, "last_used_ip": ip}).Error; err != nil {
+			return accessToken, err
+		}
+	}
+	return accessToken, nil
+}
+
+// AccessTokenScopeAllows reports whether a token with the given scopes can make a request with the given
+// HTTP method.
+func AccessTokenScopeAllows(scopes []string, method string) bool {
+	for _, scope := range scopes {
+		if scope == pmv1.AccessTokenScopeWrite {
+			return true
+		}
+	}
+	switch method {
+	case "GET", "HEAD", "OPTIONS":
+		return len(scopes) > 0
+	default:
+		return false
+	}
+}
+
+// ServiceAccountEmail returns the email address identifying a service account of an organization.
+func ServiceAccountEmail(name, organizationSlug string) string {
+	slug := strings.Trim(serviceAccountSlugPattern.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		slug = "service-account"
+	}
+	return fmt.Sprintf("%s.%s@%s", slug, organizationSlug, pmv1.ServiceAccountEmailDomain)
+}
+
+// RevokeAccessTokens revokes the active access tokens of a user or service account.
+func RevokeAccessTokens(tx *gorm.DB, email string) error {
+	return tx.Model(&pmv1.AccessToken{}).
+		Where("LOWER(email) = LOWER(?) AND revoked_at IS NULL", email).
+		Update("revoked_at", time.Now()).Error
+}
+
+// DeleteServiceAccount revokes the tokens of a service account and removes it from its projects, teams
+// and organization before deleting it.
+func DeleteServiceAccount(tx *gorm.DB, serviceAccount pmv1.ServiceAccount) error {
+	if err := RevokeAccessTokens(tx, serviceAccount.Email); err != nil {
+		return err
+	}
+	if err := tx.Where("LOWER(email) = LOWER(?)", serviceAccount.Email).Delete(&v1.ProjectMember{}).Error; err != nil {
+		return err
+	}
+	if _, err := LeaveOrganization(tx, serviceAccount.OrganizationID, serviceAccount.Email); err != nil {
+		return err
+	}
+	return tx.Delete(&serviceAccount).Error
+}
+
+// ServiceAccountInOrganization loads a service account of an organization.
+func ServiceAccountInOrganization(tx *gorm.DB, organizationID, serviceAccountID uuid.UUID) (pmv1.ServiceAccount, error) {
+	var serviceAccount pmv1.ServiceAccount
+	err := tx.Where("id = ? AND organization_id = ?", serviceAccountID, organizationID).First(&serviceAccount).Error
+	return serviceAccount, err
+}
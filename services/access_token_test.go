@@ -0,0 +1,137 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestNewAccessToken(t *testing.T) {
+	token, hash, hint, err := NewAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(token, pmv1.AccessTokenPrefix) {
+		t.Errorf("token %q does not start with %q", token, pmv1.AccessTokenPrefix)
+	}
+	if hash != HashAccessToken(token) {
+		t.Errorf("hash %q is not the hash of the token", hash)
+	}
+	if len(hash) != 64 || strings.Contains(hash, token) {
+		t.Errorf("hash %q is not a SHA-256 hex digest", hash)
+	}
+	if !strings.HasSuffix(hint, token[len(token)-4:]) || strings.Contains(hint, token[len(pmv1.AccessTokenPrefix):len(token)-4]) {
+		t.Errorf("hint %q reveals more than the last four characters of the token", hint)
+	}
+
+	other, otherHash, _, err := NewAccessToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == token || otherHash == hash {
+		t.Error("two access tokens are the same")
+	}
+}
+
+func TestAccessTokenScopeAllows(t *testing.T) {
+	tests := []struct {
+		scopes []string
+		method string
+		want   bool
+	}{
+		{[]string{pmv1.AccessTokenScopeRead}, "GET", true},
+		{[]string{pmv1.AccessTokenScopeRead}, "HEAD", true},
+		{[]string{pmv1.AccessTokenScopeRead}, "POST", false},
+		{[]string{pmv1.AccessTokenScopeRead}, "DELETE", false},
+		{[]string{pmv1.AccessTokenScopeWrite}, "POST", true},
+		{[]string{pmv1.AccessTokenScopeRead, pmv1.AccessTokenScopeWrite}, "PATCH", true},
+		{nil, "GET", false},
+	}
+	for _, test := range tests {
+		if got := AccessTokenScopeAllows(test.scopes, test.method); got != test.want {
+			t.Errorf("AccessTokenScopeAllows(%v, %s) = %t, want %t", test.scopes, test.method, got, test.want)
+		}
+	}
+}
+
+func TestAuthenticateAccessTokenLooksUpHash(t *testing.T) {
+	tx := testDB(t)
+
+	create := func(t *testing.T, change func(*pmv1.AccessToken)) string {
+		t.Helper()
+		token, hash, hint, err := NewAccessToken()
+		if err != nil {
+			t.Fatal(err)
+		}
+		accessToken := pmv1.AccessToken{
+			Name:      "ci",
+			Email:     "jane@example.com",
+			TokenHash: hash,
+			Hint:      hint,
+			Scopes:    []string{pmv1.AccessTokenScopeRead},
+			ExpiresAt: time.Now().Add(time.Hour),
+			CreatedBy: "jane@example.com",
+		}
+		if change != nil {
+			change(&accessToken)
+		}
+		if err := tx.Create(&accessToken).Error; err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		token := create(t, nil)
+		accessToken, err := AuthenticateAccessToken(tx, token, "203.0.113.7")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accessToken.TokenHash != HashAccessToken(token) || accessToken.Email != "jane@example.com" {
+			t.Fatalf("authenticated as %+v", accessToken)
+		}
+
+		var stored pmv1.AccessToken
+		if err := tx.First(&stored, "id = ?", accessToken.ID).Error; err != nil {
+			t.Fatal(err)
+		}
+		if stored.LastUsedAt == nil || stored.LastUsedIP != "203.0.113.7" {
+			t.Fatalf("last use not recorded: %+v", stored)
+		}
+	})
+
+	t.Run("stored hash is not a token", func(t *testing.T) {
+		token := create(t, nil)
+		if _, err := AuthenticateAccessToken(tx, HashAccessToken(token), ""); !errors.Is(err, ErrInvalidAccessToken) {
+			t.Fatalf("got %v, want %v", err, ErrInvalidAccessToken)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		change func(*pmv1.AccessToken)
+	}{
+		{"revoked", func(accessToken *pmv1.AccessToken) {
+			revokedAt := time.Now()
+			accessToken.RevokedAt = &revokedAt
+		}},
+		{"expired", func(accessToken *pmv1.AccessToken) { accessToken.ExpiresAt = time.Now().Add(-time.Minute) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			token := create(t, test.change)
+			if _, err := AuthenticateAccessToken(tx, token, ""); !errors.Is(err, ErrInvalidAccessToken) {
+				t.Fatalf("got %v, want %v", err, ErrInvalidAccessToken)
+			}
+		})
+	}
+
+	t.Run("unknown", func(t *testing.T) {
+		if _, err := AuthenticateAccessToken(tx, pmv1.AccessTokenPrefix+"unknown", ""); !errors.Is(err, ErrInvalidAccessToken) {
+			t.Fatalf("got %v, want %v", err, ErrInvalidAccessToken)
+		}
+	})
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// IsProjectArchived reports whether a project is archived and therefore read-only.
+func IsProjectArchived(tx *gorm.DB, projectID uuid.UUID) (bool, error) {
+	var count int64
+	if err := tx.Model(&v1.Project{}).
+		Where("id = ? AND status = ?", projectID, pmv1.ProjectStatusArchived).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// ArchiveProject marks a project as archived, remembering its current status so that it can be restored.
+func ArchiveProject(tx *gorm.DB, project *v1.Project, email string) (pmv1.ProjectArchive, error) {
+	archive := pmv1.ProjectArchive{
+		ProjectID:      project.ID,
+		PreviousStatus: project.Status,
+		ArchivedBy:     email,
+		ArchivedAt:     time.Now(),
+	}
+	if err := tx.Save(&archive).Error; err != nil {
+		return archive, err
+	}
+
+	project.Status = pmv1.ProjectStatusArchived
+	if err := tx.Model(project).Update("status", project.Status).Error; err != nil {
+		return archive, err
+	}
+	return archive, nil
+}
+
+// UnarchiveProject restores the status a project had before it was archived.
+func UnarchiveProject(tx *gorm.DB, project *v1.Project) error {
+	var archive pmv1.ProjectArchive
+	if err := tx.Where("project_id = ?", project.ID).First(&archive).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	project.Status = archive.PreviousStatus
+	if err := tx.Model(project).Update("status", project.Status).Error; err != nil {
+		return err
+	}
+	return tx.Where("project_id = ?", project.ID).Delete(&pmv1.ProjectArchive{}).Error
+}
@@ -0,0 +1,175 @@
+// Package services contains domain logic shared by the API handlers and background jobs,
+// such as rate resolution, budget calculations and scheduling.
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RateCard holds the resolved hourly rates for a single project.
+type RateCard struct {
+	Currency    string
+	ClientRate  float64
+	ProjectRate *float64
+	MemberRates map[string]float64
+}
+
+// RateFor returns the hourly rate of a member: the member override, then the project rate, then the client rate.
+func (r *RateCard) RateFor(email string) float64 {
+	if rate, ok := r.MemberRates[strings.ToLower(email)]; ok {
+		return rate
+	}
+	if r.ProjectRate != nil {
+		return *r.ProjectRate
+	}
+	return r.ClientRate
+}
+
+// LoadRateCard loads the client, project and member rates of a project.
+func LoadRateCard(tx *gorm.DB, projectID uuid.UUID) (*RateCard, error) {
+	var project v1.Project
+	if err := tx.Select("id, client_id").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return nil, err
+	}
+
+	card := &RateCard{
+		Currency:    pmv1.DefaultCurrency,
+		MemberRates: map[string]float64{},
+	}
+
+	var clientBilling pmv1.ClientBilling
+	err := tx.Where("client_id = ?", project.ClientID).First(&clientBilling).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err == nil {
+		card.ClientRate = clientBilling.HourlyRate
+		card.Currency = clientBilling.Currency
+	}
+
+	var projectBilling pmv1.ProjectBilling
+	err = tx.Where("project_id = ?", projectID).First(&projectBilling).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return nil, err
+	}
+	if err == nil {
+		card.ProjectRate = projectBilling.HourlyRate
+	}
+
+	var memberRates []pmv1.ProjectMemberRate
+	if err := tx.Where("project_id = ?", projectID).Find(&memberRates).Error; err != nil {
+		return nil, err
+	}
+	for _, rate := range memberRates {
+		card.MemberRates[strings.ToLower(rate.Email)] = rate.HourlyRate
+	}
+
+	return card, nil
+}
+
+// TimeEntryHours returns the logged hours of a time entry, falling back to its start and end time.
+func TimeEntryHours(te v1.TimeEntry) float64 {
+	if te.Hours > 0 {
+		return te.Hours
+	}
+	hours := te.EndTime.Sub(te.StartTime).Hours()
+	if hours < 0 {
+		return 0
+	}
+	return hours
+}
+
+// RoundAmount rounds a monetary amount to two decimals.
+func RoundAmount(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// LockTimeEntryBilling loads the billing row of a time entry, locked until the transaction ends so that it
+// cannot be invoiced concurrently. It returns nil when the entry has no billing row yet.
+func LockTimeEntryBilling(tx *gorm.DB, timeEntryID uuid.UUID) (*pmv1.TimeEntryBilling, error) {
+	var billing pmv1.TimeEntryBilling
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("time_entry_id = ?", timeEntryID).First(&billing).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &billing, nil
+}
+
+// ErrTimeEntryInvoiced is returned for a time entry billed on an invoice, which is frozen until the
+// invoice is voided.
+var ErrTimeEntryInvoiced = errors.New("time entry is already invoiced")
+
+// LockEditableTimeEntryBilling locks the billing row of a time entry like LockTimeEntryBilling before
+// the entry is changed, returning ErrTimeEntryInvoiced when it is invoiced.
+func LockEditableTimeEntryBilling(tx *gorm.DB, timeEntryID uuid.UUID) (*pmv1.TimeEntryBilling, error) {
+	billing, err := LockTimeEntryBilling(tx, timeEntryID)
+	if err != nil {
+		return nil, err
+	}
+	if billing != nil && billing.InvoiceID != nil {
+		return nil, ErrTimeEntryInvoiced
+	}
+	return billing, nil
+}
+
+// LockBillableTimeEntries loads the billing rows of the approved, billable and uninvoiced time entries
+// logged on the live projects of a client within a period. The rows stay locked until the transaction
+// ends, so that concurrent drafts cannot bill the same entries twice: a draft waiting for the lock no
+// longer finds the entries another one invoiced.
+func LockBillableTimeEntries(tx *gorm.DB, clientID uuid.UUID, periodStart, periodEnd time.Time) ([]pmv1.TimeEntryBilling, error) {
+	var billings []pmv1.TimeEntryBilling
+	err := tx.Model(&pmv1.TimeEntryBilling{}).
+		Joins("JOIN time_entries ON time_entries.id = time_entry_billings.time_entry_id").
+		Joins("JOIN projects ON projects.id = time_entries.project_id").
+		Where("projects.client_id = ? AND projects.deleted_at IS NULL", clientID).
+		Where("time_entries.deleted_at IS NULL AND time_entries.date BETWEEN ? AND ?", periodStart, periodEnd).
+		Where("time_entry_billings.is_billable = ? AND time_entry_billings.approved_at IS NOT NULL AND time_entry_billings.invoice_id IS NULL", true).
+		Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: "time_entry_billings"}}).
+		Find(&billings).Error
+	return billings, err
+}
+
+// MarkTimeEntriesInvoiced bills time entries on an invoice.
+func MarkTimeEntriesInvoiced(tx *gorm.DB, timeEntryIDs []uuid.UUID, invoiceID uuid.UUID) error {
+	return tx.Model(&pmv1.TimeEntryBilling{}).Where("time_entry_id IN ?", timeEntryIDs).Update("invoice_id", invoiceID).Error
+}
+
+// ReleaseInvoicedTimeEntries releases the time entries of a voided invoice so that they can be billed
+// again.
+func ReleaseInvoicedTimeEntries(tx *gorm.DB, invoiceID uuid.UUID) error {
+	return tx.Model(&pmv1.TimeEntryBilling{}).Where("invoice_id = ?", invoiceID).Update("invoice_id", nil).Error
+}
+
+// NextInvoiceNumber returns the next number of an invoice created at the given time, INV-YYYYMM-NNNNN
+// with a sequence restarting every month. The sequence row of the month stays locked until the
+// transaction ends.
+func NextInvoiceNumber(tx *gorm.DB, at time.Time) (string, error) {
+	period := at.Format("200601")
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pmv1.InvoiceSequence{Period: period}).Error; err != nil {
+		return "", err
+	}
+
+	var sequence pmv1.InvoiceSequence
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("period = ?", period).First(&sequence).Error; err != nil {
+		return "", err
+	}
+	sequence.LastNumber++
+	if err := tx.Model(&sequence).Update("last_number", sequence.LastNumber).Error; err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("INV-%s-%05d", period, sequence.LastNumber), nil
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// lockWait is how long a test waits before concluding that a transaction is blocked on a row lock.
+const lockWait = 200 * time.Millisecond
+
+// createBillableTimeEntries creates a client with a project and approved, billable time entries logged
+// on the given dates, returning the client and the entries. The rows are deleted when the test ends, for
+// tests committing them.
+func createBillableTimeEntries(t *testing.T, db *gorm.DB, dates ...time.Time) (v1.Client, []v1.TimeEntry) {
+	t.Helper()
+	client := v1.Client{ID: uuid.New(), Name: "Acme", CreatedBy: "jane@example.com"}
+	project := v1.Project{ID: uuid.New(), Name: "Website", Slug: "website-" + client.ID.String()[:8], ClientID: client.ID, CreatedBy: "jane@example.com"}
+	if err := db.Create(&client).Error; err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&project).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	approvedAt := time.Now()
+	var entries []v1.TimeEntry
+	for _, date := range dates {
+		entry := v1.TimeEntry{ID: uuid.New(), ProjectID: project.ID, IssueID: uuid.New(), CreatedBy: "jane@example.com", Date: date, Hours: 2}
+		if err := db.Create(&entry).Error; err != nil {
+			t.Fatal(err)
+		}
+		billing := pmv1.TimeEntryBilling{TimeEntryID: entry.ID, ProjectID: project.ID, IsBillable: true, ApprovedBy: "john@example.com", ApprovedAt: &approvedAt}
+		if err := db.Create(&billing).Error; err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	t.Cleanup(func() {
+		db.Where("project_id = ?", project.ID).Delete(&pmv1.TimeEntryBilling{})
+		db.Where("project_id = ?", project.ID).Delete(&v1.TimeEntry{})
+		db.Delete(&project)
+		db.Delete(&client)
+	})
+	return client, entries
+}
+
+// deleteInvoiceSequence deletes the sequence of a month when the test ends, for tests committing it.
+func deleteInvoiceSequence(t *testing.T, db *gorm.DB, at time.Time) {
+	t.Cleanup(func() { db.Where("period = ?", at.Format("200601")).Delete(&pmv1.InvoiceSequence{}) })
+}
+
+func TestNextInvoiceNumberRestartsEveryMonth(t *testing.T) {
+	tx := testDB(t)
+	january := time.Date(2199, time.January, 31, 23, 0, 0, 0, time.UTC)
+	february := time.Date(2199, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, want := range []struct {
+		at     time.Time
+		number string
+	}{
+		{january, "INV-219901-00001"},
+		{january, "INV-219901-00002"},
+		{february, "INV-219902-00001"},
+		{january, "INV-219901-00003"},
+	} {
+		number, err := NextInvoiceNumber(tx, want.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if number != want.number {
+			t.Errorf("NextInvoiceNumber(%s) = %s, want %s", want.at.Format("2006-01"), number, want.number)
+		}
+	}
+}
+
+func TestNextInvoiceNumberIsSequentialAcrossConcurrentDrafts(t *testing.T) {
+	db := openTestDB(t)
+	at := time.Date(2198, time.March, 15, 0, 0, 0, 0, time.UTC)
+	deleteInvoiceSequence(t, db, at)
+
+	const drafts = 10
+	numbers := make(chan string, drafts)
+	errs := make(chan error, drafts)
+	for i := 0; i < drafts; i++ {
+		go func() {
+			errs <- db.Transaction(func(tx *gorm.DB) error {
+				number, err := NextInvoiceNumber(tx, at)
+				if err != nil {
+					return err
+				}
+				numbers <- number
+				return nil
+			})
+		}()
+	}
+	for i := 0; i < drafts; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(numbers)
+
+	var got []string
+	for number := range numbers {
+		got = append(got, number)
+	}
+	sort.Strings(got)
+	for i, number := range got {
+		if want := fmt.Sprintf("INV-219803-%05d", i+1); number != want {
+			t.Fatalf("numbers = %v, want INV-219803-00001 to INV-219803-%05d without gaps or duplicates", got, drafts)
+		}
+	}
+}
+
+func TestNextInvoiceNumberLocksTheMonth(t *testing.T) {
+	db := openTestDB(t)
+	at := time.Date(2198, time.April, 15, 0, 0, 0, 0, time.UTC)
+	deleteInvoiceSequence(t, db, at)
+
+	first := db.Begin()
+	defer first.Rollback()
+	if number, err := NextInvoiceNumber(first, at); err != nil || number != "INV-219804-00001" {
+		t.Fatalf("first number = %s, %v", number, err)
+	}
+
+	second := make(chan string, 1)
+	go func() {
+		var number string
+		err := db.Transaction(func(tx *gorm.DB) error {
+			var err error
+			number, err = NextInvoiceNumber(tx, at)
+			return err
+		})
+		if err != nil {
+			number = err.Error()
+		}
+		second <- number
+	}()
+
+	select {
+	case number := <-second:
+		t.Fatalf("second draft numbered %s while the first one held the month", number)
+	case <-time.After(lockWait):
+	}
+	if err := first.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+	if number := <-second; number != "INV-219804-00002" {
+		t.Errorf("second number = %s, want INV-219804-00002", number)
+	}
+}
+
+func TestLockBillableTimeEntriesPreventsDoubleBilling(t *testing.T) {
+	db := openTestDB(t)
+	periodStart := time.Date(2197, time.May, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2197, time.May, 31, 0, 0, 0, 0, time.UTC)
+	client, entries := createBillableTimeEntries(t, db, periodStart, periodStart.AddDate(0, 0, 1), periodEnd.AddDate(0, 0, 1))
+
+	first := db.Begin()
+	defer first.Rollback()
+	billings, err := LockBillableTimeEntries(first, client.ID, periodStart, periodEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(billings) != 2 {
+		t.Fatalf("billable entries = %d, want the 2 logged within the period", len(billings))
+	}
+
+	// A concurrent draft waits for the entries, then finds them invoiced.
+	type result struct {
+		billings []pmv1.TimeEntryBilling
+		err      error
+	}
+	second := make(chan result, 1)
+	go func() {
+		var r result
+		r.err = db.Transaction(func(tx *gorm.DB) error {
+			r.billings, r.err = LockBillableTimeEntries(tx, client.ID, periodStart, periodEnd)
+			return r.err
+		})
+		second <- r
+	}()
+
+	select {
+	case r := <-second:
+		t.Fatalf("second draft found %d entries, %v, while the first one held them", len(r.billings), r.err)
+	case <-time.After(lockWait):
+	}
+	if err := MarkTimeEntriesInvoiced(first, []uuid.UUID{entries[0].ID, entries[1].ID}, uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.Commit().Error; err != nil {
+		t.Fatal(err)
+	}
+	if r := <-second; r.err != nil || len(r.billings) != 0 {
+		t.Errorf("second draft found %d entries, %v, want none", len(r.billings), r.err)
+	}
+}
+
+func TestVoidReleasesInvoicedTimeEntries(t *testing.T) {
+	tx := testDB(t)
+	day := time.Date(2197, time.June, 10, 0, 0, 0, 0, time.UTC)
+	client, entries := createBillableTimeEntries(t, tx, day, day)
+	invoiceID := uuid.New()
+
+	if err := MarkTimeEntriesInvoiced(tx, []uuid.UUID{entries[0].ID, entries[1].ID}, invoiceID); err != nil {
+		t.Fatal(err)
+	}
+	if billings, err := LockBillableTimeEntries(tx, client.ID, day, day); err != nil || len(billings) != 0 {
+		t.Fatalf("billable entries of an invoice = %d, %v, want none", len(billings), err)
+	}
+
+	if err := ReleaseInvoicedTimeEntries(tx, invoiceID); err != nil {
+		t.Fatal(err)
+	}
+	billings, err := LockBillableTimeEntries(tx, client.ID, day, day)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(billings) != 2 {
+		t.Fatalf("billable entries after voiding = %d, want 2", len(billings))
+	}
+	for _, billing := range billings {
+		if billing.InvoiceID != nil {
+			t.Errorf("entry %s still on invoice %s", billing.TimeEntryID, billing.InvoiceID)
+		}
+	}
+}
+
+func TestInvoicedTimeEntriesAreFrozen(t *testing.T) {
+	tx := testDB(t)
+	_, entries := createBillableTimeEntries(t, tx, time.Date(2197, time.July, 1, 0, 0, 0, 0, time.UTC))
+	entry := entries[0]
+	invoiceID := uuid.New()
+
+	if billing, err := LockEditableTimeEntryBilling(tx, entry.ID); err != nil || billing == nil {
+		t.Fatalf("uninvoiced entry = %v, %v, want it editable", billing, err)
+	}
+	if billing, err := LockEditableTimeEntryBilling(tx, uuid.New()); err != nil || billing != nil {
+		t.Errorf("entry without billing = %v, %v, want it editable", billing, err)
+	}
+
+	if err := MarkTimeEntriesInvoiced(tx, []uuid.UUID{entry.ID}, invoiceID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LockEditableTimeEntryBilling(tx, entry.ID); err != ErrTimeEntryInvoiced {
+		t.Errorf("invoiced entry = %v, want ErrTimeEntryInvoiced", err)
+	}
+
+	if err := ReleaseInvoicedTimeEntries(tx, invoiceID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LockEditableTimeEntryBilling(tx, entry.ID); err != nil {
+		t.Errorf("entry of a void invoice = %v, want it editable", err)
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WIPLimitError is returned when a move would exceed a hard work-in-progress limit.
+type WIPLimitError struct {
+	StateName string
+	Limit     int
+	Count     int64
+}
+
+// Error implements the error interface.
+func (e *WIPLimitError) Error() string {
+	return fmt.Sprintf("state %q is limited to %d issues and already holds %d", e.StateName, e.Limit, e.Count)
+}
+
+// CheckWIPLimit verifies that incoming more issues fit in a state.
+// Hard limits return a *WIPLimitError; soft limits return a warning instead. The limit row is locked
+// so that concurrent moves into the same state are serialized.
+func CheckWIPLimit(tx *gorm.DB, stateID uuid.UUID, incoming int) (string, error) {
+	if incoming <= 0 {
+		return "", nil
+	}
+
+	var limit pmv1.StateWIPLimit
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("state_id = ?", stateID).First(&limit).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var count int64
+	if err := tx.Model(&v1.Issue{}).
+		Where("state_id = ? AND deleted_at IS NULL AND is_draft = ?", stateID, false).
+		Count(&count).Error; err != nil {
+		return "", err
+	}
+	if count+int64(incoming) <= int64(limit.Limit) {
+		return "", nil // Skip loading the state name.
+	}
+
+	var state v1.ProjectState
+	if err := tx.Select("id, name").Where("id = ?", stateID).First(&state).Error; err != nil {
+		return "", err
+	}
+	return evaluateWIPLimit(limit, state.Name, count, incoming)
+}
+
+// evaluateWIPLimit applies the work-in-progress limit of a state holding count issues to incoming more.
+func evaluateWIPLimit(limit pmv1.StateWIPLimit, stateName string, count int64, incoming int) (string, error) {
+	if incoming <= 0 || count+int64(incoming) <= int64(limit.Limit) {
+		return "", nil
+	}
+	if limit.Mode == pmv1.WIPModeHard {
+		return "", &WIPLimitError{StateName: stateName, Limit: limit.Limit, Count: count}
+	}
+	return fmt.Sprintf("state %q is over its work-in-progress limit of %d (%d issues)", stateName, limit.Limit, count+int64(incoming)), nil
+}
+
+// LoadStateColumns returns the states of a project in board order with their category, issue count and work-in-progress limit.
+func LoadStateColumns(tx *gorm.DB, projectID uuid.UUID) ([]pmv1.StateColumn, error) {
+	var states []v1.ProjectState
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL", projectID).Order("sequence ASC").Find(&states).Error; err != nil {
+		return nil, err
+	}
+
+	categories, err := LoadStateCategories(tx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	var limits []pmv1.StateWIPLimit
+	if err := tx.Where("project_id = ?", projectID).Find(&limits).Error; err != nil {
+		return nil, err
+	}
+	limitsByState := make(map[uuid.UUID]pmv1.StateWIPLimit, len(limits))
+	for _, limit := range limits {
+		limitsByState[limit.StateID] = limit
+	}
+
+	var counts []struct {
+		StateID uuid.UUID
+		Count   int64
+	}
+	if err := tx.Model(&v1.Issue{}).
+		Select("state_id, COUNT(*) AS count").
+		Where("project_id = ? AND deleted_at IS NULL AND is_draft = ?", projectID, false).
+		Group("state_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	countsByState := make(map[uuid.UUID]int64, len(counts))
+	for _, row := range counts {
+		countsByState[row.StateID] = row.Count
+	}
+
+	columns := make([]pmv1.StateColumn, 0, len(states))
+	for _, state := range states {
+		column := pmv1.StateColumn{
+			StateID:    state.ID.String(),
+			Name:       state.Name,
+			Sequence:   state.Sequence,
+			Category:   pmv1.DefaultStateCategory,
+			IssueCount: countsByState[state.ID],
+		}
+		if category, ok := categories[state.ID]; ok {
+			column.Category = category
+		}
+		if limit, ok := limitsByState[state.ID]; ok {
+			value := limit.Limit
+			column.WIPLimit = &value
+			column.WIPMode = limit.Mode
+			column.OverLimit = column.IssueCount > int64(limit.Limit)
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// OverLimitStates returns the IDs of the columns holding more issues than their limit.
+func OverLimitStates(columns []pmv1.StateColumn) []string {
+	states := []string{}
+	for _, column := range columns {
+		if column.OverLimit {
+			states = append(states, column.StateID)
+		}
+	}
+	return states
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestEvaluateWIPLimit(t *testing.T) {
+	hard := pmv1.StateWIPLimit{Limit: 3, Mode: pmv1.WIPModeHard}
+	soft := pmv1.StateWIPLimit{Limit: 3, Mode: pmv1.WIPModeSoft}
+	tests := []struct {
+		name        string
+		limit       pmv1.StateWIPLimit
+		count       int64
+		incoming    int
+		wantWarning bool
+		wantErr     bool
+	}{
+		{"under a hard limit", hard, 1, 1, false, false},
+		{"reaching a hard limit", hard, 2, 1, false, false},
+		{"over a hard limit", hard, 3, 1, false, true},
+		{"several issues over a hard limit", hard, 1, 3, false, true},
+		{"state already over a hard limit, nothing incoming", hard, 5, 0, false, false},
+		{"reaching a soft limit", soft, 2, 1, false, false},
+		{"over a soft limit", soft, 3, 1, true, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			warning, err := evaluateWIPLimit(test.limit, "Doing", test.count, test.incoming)
+			if (warning != "") != test.wantWarning {
+				t.Errorf("warning = %q, want one: %t", warning, test.wantWarning)
+			}
+			var limitErr *WIPLimitError
+			if errors.As(err, &limitErr) != test.wantErr {
+				t.Fatalf("error = %v, want a *WIPLimitError: %t", err, test.wantErr)
+			}
+			if limitErr != nil && (limitErr.StateName != "Doing" || limitErr.Limit != 3 || limitErr.Count != test.count) {
+				t.Errorf("error = %+v", limitErr)
+			}
+		})
+	}
+}
+
+func TestCheckWIPLimit(t *testing.T) {
+	tx := testDB(t)
+	projectID := uuid.New()
+	state := v1.ProjectState{ID: uuid.New(), ProjectID: projectID, Name: "Doing", CreatedBy: "jane@example.com"}
+	if err := tx.Create(&state).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if warning, err := CheckWIPLimit(tx, state.ID, 10); warning != "" || err != nil {
+		t.Fatalf("state without a limit: %q, %v", warning, err)
+	}
+
+	limit := pmv1.StateWIPLimit{StateID: state.ID, ProjectID: projectID, Limit: 2, Mode: pmv1.WIPModeHard, UpdatedBy: "jane@example.com"}
+	if err := tx.Create(&limit).Error; err != nil {
+		t.Fatal(err)
+	}
+	// Drafts and deleted issues do not count towards the limit.
+	deletedAt := day(2)
+	for _, issue := range []v1.Issue{
+		{ID: uuid.New(), ProjectID: projectID, StateID: state.ID, Title: "Open", CreatedBy: "jane@example.com"},
+		{ID: uuid.New(), ProjectID: projectID, StateID: state.ID, Title: "Draft", IsDraft: true, CreatedBy: "jane@example.com"},
+		{ID: uuid.New(), ProjectID: projectID, StateID: state.ID, Title: "Deleted", DeletedAt: &deletedAt, CreatedBy: "jane@example.com"},
+	} {
+		if err := tx.Create(&issue).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if warning, err := CheckWIPLimit(tx, state.ID, 1); warning != "" || err != nil {
+		t.Fatalf("reaching the limit: %q, %v", warning, err)
+	}
+	var limitErr *WIPLimitError
+	if _, err := CheckWIPLimit(tx, state.ID, 2); !errors.As(err, &limitErr) || limitErr.Count != 1 || limitErr.StateName != "Doing" {
+		t.Fatalf("exceeding a hard limit: %v", err)
+	}
+
+	if err := tx.Model(&limit).Update("mode", pmv1.WIPModeSoft).Error; err != nil {
+		t.Fatal(err)
+	}
+	if warning, err := CheckWIPLimit(tx, state.ID, 2); warning == "" || err != nil {
+		t.Fatalf("exceeding a soft limit: %q, %v", warning, err)
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// TimeEntryHoursSQL computes the hours of a time_entries row, falling back to its start and end time.
+const TimeEntryHoursSQL = "CASE WHEN time_entries.hours > 0 THEN time_entries.hours ELSE EXTRACT(EPOCH FROM (time_entries.end_time - time_entries.start_time)) / 3600 END"
+
+// ProjectHoursByMember returns the hours logged on a project grouped by the member who logged them.
+func ProjectHoursByMember(tx *gorm.DB, projectID uuid.UUID) (map[string]float64, error) {
+	var rows []struct {
+		CreatedBy string
+		Hours     float64
+	}
+	if err := tx.Model(&v1.TimeEntry{}).
+		Select("time_entries.created_by, COALESCE(SUM("+TimeEntryHoursSQL+"), 0) AS hours").
+		Where("time_entries.project_id = ?", projectID).
+		Group("time_entries.created_by").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	hours := make(map[string]float64, len(rows))
+	for _, row := range rows {
+		hours[row.CreatedBy] = row.Hours
+	}
+	return hours, nil
+}
+
+// LoadProjectBudget returns the budget of a project, or nil when none is configured.
+func LoadProjectBudget(tx *gorm.DB, projectID uuid.UUID) (*pmv1.ProjectBudget, error) {
+	var budget pmv1.ProjectBudget
+	if err := tx.Where("project_id = ?", projectID).First(&budget).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(budget.Thresholds) == 0 {
+		budget.Thresholds = pmv1.DefaultBudgetThresholds
+	}
+	return &budget, nil
+}
+
+// ComputeBudgetConsumption calculates the hours and money consumed on a project against its budget.
+func ComputeBudgetConsumption(tx *gorm.DB, projectID uuid.UUID) (pmv1.BudgetConsumption, error) {
+	consumption := pmv1.BudgetConsumption{
+		ProjectID:  projectID.String(),
+		Thresholds: []int64(pmv1.DefaultBudgetThresholds),
+		Members:    []pmv1.MemberConsumption{},
+	}
+
+	budget, err := LoadProjectBudget(tx, projectID)
+	if err != nil {
+		return consumption, err
+	}
+
+	card, err := LoadRateCard(tx, projectID)
+	if err != nil {
+		return consumption, err
+	}
+	consumption.Currency = card.Currency
+
+	hoursByMember, err := ProjectHoursByMember(tx, projectID)
+	if err != nil {
+		return consumption, err
+	}
+
+	for email, hours := range hoursByMember {
+		rate := card.RateFor(email)
+		amount := RoundAmount(hours * rate)
+		consumption.Members = append(consumption.Members, pmv1.MemberConsumption{
+			Email:      email,
+			Hours:      RoundAmount(hours),
+			HourlyRate: rate,
+			Amount:     amount,
+		})
+		consumption.ConsumedHours += hours
+		consumption.ConsumedAmount += amount
+	}
+	sort.Slice(consumption.Members, func(i, j int) bool {
+		return consumption.Members[i].Email < consumption.Members[j].Email
+	})
+	consumption.ConsumedHours = RoundAmount(consumption.ConsumedHours)
+	consumption.ConsumedAmount = RoundAmount(consumption.ConsumedAmount)
+
+	if budget == nil {
+		return consumption, nil
+	}
+
+	consumption.Thresholds = []int64(budget.Thresholds)
+	consumption.BudgetHours = budget.BudgetHours
+	consumption.BudgetAmount = budget.BudgetAmount
+	if budget.BudgetHours != nil && *budget.BudgetHours > 0 {
+		percentage := RoundAmount(consumption.ConsumedHours / *budget.BudgetHours * 100)
+		consumption.HoursPercentage = &percentage
+	}
+	if budget.BudgetAmount != nil && *budget.BudgetAmount > 0 {
+		percentage := RoundAmount(consumption.ConsumedAmount / *budget.BudgetAmount * 100)
+		consumption.AmountPercentage = &percentage
+	}
+
+	return consumption, nil
+}
+
+// CheckBudgetThresholds raises a project activity entry and a notification to the project managers for every
+// budget threshold that has been crossed since the last check. Thresholds that are no longer crossed, for example
+// after the budget was raised, are re-armed.
+func CheckBudgetThresholds(tx *gorm.DB, projectID uuid.UUID, email string) error {
+	budget, err := LoadProjectBudget(tx, projectID)
+	if err != nil || budget == nil {
+		return err
+	}
+
+	consumption, err := ComputeBudgetConsumption(tx, projectID)
+	if err != nil {
+		return err
+	}
+
+	checks := []struct {
+		kind       string
+		percentage *float64
+		consumed   float64
+	}{
+		{pmv1.BudgetKindHours, consumption.HoursPercentage, consumption.ConsumedHours},
+		{pmv1.BudgetKindAmount, consumption.AmountPercentage, consumption.ConsumedAmount},
+	}
+
+	for _, check := range checks {
+		if check.percentage == nil {
+			continue
+		}
+
+		// Re-arm thresholds that are no longer reached
+		if err := tx.Where("project_id = ? AND kind = ? AND threshold > ?", projectID, check.kind, *check.percentage).
+			Delete(&pmv1.ProjectBudgetAlert{}).Error; err != nil {
+			return err
+		}
+
+		for _, threshold := range budget.Thresholds {
+			if *check.percentage < float64(threshold) {
+				continue
+			}
+
+			var existing int64
+			if err := tx.Model(&pmv1.ProjectBudgetAlert{}).
+				Where("project_id = ? AND kind = ? AND threshold = ?", projectID, check.kind, threshold).
+				Count(&existing).Error; err != nil {
+				return err
+			}
+			if existing > 0 {
+				continue
+			}
+
+			if err := raiseBudgetAlert(tx, projectID, email, check.kind, threshold, check.consumed, *check.percentage, consumption.Currency); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// raiseBudgetAlert records a crossed threshold, writes a project activity entry and notifies the project managers.
+func raiseBudgetAlert(tx *gorm.DB, projectID uuid.UUID, email, kind string, threshold int64, consumed, percentage float64, currency string) error {
+	alert := pmv1.ProjectBudgetAlert{
+		ProjectID: projectID,
+		Kind:      kind,
+		Threshold: threshold,
+		Consumed:  consumed,
+	}
+	if err := tx.Create(&alert).Error; err != nil {
+		return err
+	}
+
+	activity := v1.ProjectActivity{
+		ProjectID: projectID,
+		Email:     email,
+		Action:    "threshold_reached",
+		Entity:    "budget",
+		Column:    kind,
+		OldValue:  fmt.Sprintf("%d%%", threshold),
+		NewValue:  fmt.Sprintf("%.2f%%", percentage),
+	}
+	if err := tx.Create(&activity).Error; err != nil {
+		return err
+	}
+
+	var project v1.Project
+	if err := tx.Select("id, name").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return err
+	}
+
+	recipients, err := ProjectManagerEmails(tx, projectID)
+	if err != nil {
+		return err
+	}
+
+	unit := "hours"
+	if kind == pmv1.BudgetKindAmount {
+		unit = currency
+	}
+	title := fmt.Sprintf("%s reached %d%% of its %s budget", project.Name, threshold, kind)
+	message := fmt.Sprintf("Project %s has consumed %.2f %s, which is %.2f%% of its %s budget.", project.Name, consumed, unit, percentage, kind)
+
+	return NotifyUsers(tx, recipients, &projectID, pmv1.NotificationTypeBudgetThreshold, title, message)
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// Reasons reported for days on which a member does not work.
+const (
+	NonWorkingReasonWeekend = "non-working day"
+	NonWorkingReasonTimeOff = "time off"
+)
+
+// Calendar is the effective working calendar of a member over a date range:
+// the organization calendar, overridden by the member calendar, minus holidays and time off.
+type Calendar struct {
+	Email       string
+	WorkingDays map[time.Weekday]bool
+	HoursPerDay float64
+	Holidays    map[time.Time]string
+	TimeOff     []pmv1.TimeOff
+}
+
+// NewCalendar returns a calendar with the given working weekdays and hours per day, without holidays or time off.
+func NewCalendar(workingDays pq.Int64Array, hoursPerDay float64) *Calendar {
+	calendar := &Calendar{
+		WorkingDays: map[time.Weekday]bool{},
+		HoursPerDay: hoursPerDay,
+		Holidays:    map[time.Time]string{},
+	}
+	for _, day := range workingDays {
+		calendar.WorkingDays[time.Weekday(day)] = true
+	}
+	return calendar
+}
+
+// NonWorkingReason returns why day is not a working day, or an empty string when it is one.
+func (cal *Calendar) NonWorkingReason(day time.Time) string {
+	day = TruncateDay(day)
+	if name, ok := cal.Holidays[day]; ok {
+		return name
+	}
+	if !cal.WorkingDays[day.Weekday()] {
+		return NonWorkingReasonWeekend
+	}
+	for _, off := range cal.TimeOff {
+		if !day.Before(TruncateDay(off.StartDate)) && !day.After(TruncateDay(off.EndDate)) {
+			return NonWorkingReasonTimeOff
+		}
+	}
+	return ""
+}
+
+// IsWorkingDay reports whether the member works on day.
+func (cal *Calendar) IsWorkingDay(day time.Time) bool {
+	return cal.NonWorkingReason(day) == ""
+}
+
+// HoursOn returns the working hours of the member on day.
+func (cal *Calendar) HoursOn(day time.Time) float64 {
+	if !cal.IsWorkingDay(day) {
+		return 0
+	}
+	return cal.HoursPerDay
+}
+
+// WeeklyHours returns the regular working hours per week, ignoring holidays and time off.
+func (cal *Calendar) WeeklyHours() float64 {
+	return cal.HoursPerDay * float64(len(cal.WorkingDays))
+}
+
+// WorkingDaysBetween returns the working days between from and to, inclusive.
+func (cal *Calendar) WorkingDaysBetween(from, to time.Time) []time.Time {
+	var days []time.Time
+	for day := TruncateDay(from); !day.After(TruncateDay(to)); day = day.AddDate(0, 0, 1) {
+		if cal.IsWorkingDay(day) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// AddWorkingDays returns the date reached after n working days from start, start being the first one when it is a working day.
+func (cal *Calendar) AddWorkingDays(start time.Time, n int) time.Time {
+	day := TruncateDay(start)
+	if len(cal.WorkingDays) == 0 || n <= 0 {
+		return day
+	}
+	for {
+		if cal.IsWorkingDay(day) {
+			n--
+			if n == 0 {
+				return day
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// Days lists the effective calendar between from and to, inclusive.
+func (cal *Calendar) Days(from, to time.Time) []pmv1.CalendarDay {
+	days := []pmv1.CalendarDay{}
+	for day := TruncateDay(from); !day.After(TruncateDay(to)); day = day.AddDate(0, 0, 1) {
+		reason := cal.NonWorkingReason(day)
+		days = append(days, pmv1.CalendarDay{Date: day, Working: reason == "", Hours: cal.HoursOn(day), Reason: reason})
+	}
+	return days
+}
+
+// LoadOrganizationCalendar returns the calendar row of an organization, or the defaults when none is configured.
+func LoadOrganizationCalendar(tx *gorm.DB, organizationID uuid.UUID) (pmv1.WorkingCalendar, bool, error) {
+	calendar := pmv1.WorkingCalendar{OrganizationID: organizationID, WorkingDays: pmv1.DefaultWorkingDays, HoursPerDay: pmv1.DefaultHoursPerDay}
+	err := tx.Where("organization_id = ? AND email = ?", organizationID, "").First(&calendar).Error
+	if err == gorm.ErrRecordNotFound {
+		return calendar, false, nil
+	}
+	return calendar, err == nil, err
+}
+
+// LoadOrganizationWorkingCalendar returns the calendar of an organization with its holidays from the given
+// day on, the calendar of work not assigned to a member yet, such as the seed issues of a template.
+func LoadOrganizationWorkingCalendar(tx *gorm.DB, organizationID uuid.UUID, from time.Time) (*Calendar, error) {
+	organization, _, err := LoadOrganizationCalendar(tx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []pmv1.Holiday
+	if err := tx.Where("organization_id = ? AND date >= ?", organizationID, TruncateDay(from)).Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	calendar := NewCalendar(organization.WorkingDays, organization.HoursPerDay)
+	for _, holiday := range holidays {
+		calendar.Holidays[TruncateDay(holiday.Date)] = holiday.Name
+	}
+	return calendar, nil
+}
+
+// LoadCalendars returns the effective calendar of each email in an organization between from and to, keyed
+// by lower-cased email.
+func LoadCalendars(tx *gorm.DB, organizationID uuid.UUID, emails []string, from, to time.Time) (map[string]*Calendar, error) {
+	organization, _, err := LoadOrganizationCalendar(tx, organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	var holidays []pmv1.Holiday
+	if err := tx.Where("organization_id = ? AND date BETWEEN ? AND ?", organizationID, TruncateDay(from), TruncateDay(to)).Find(&holidays).Error; err != nil {
+		return nil, err
+	}
+
+	var overrides []pmv1.WorkingCalendar
+	var timeOff []pmv1.TimeOff
+	if len(emails) > 0 {
+		if err := tx.Where("organization_id = ? AND LOWER(email) IN ?", organizationID, lowerEmails(emails)).Find(&overrides).Error; err != nil {
+			return nil, err
+		}
+		if err := tx.Where("LOWER(email) IN ? AND start_date <= ? AND end_date >= ?", lowerEmails(emails), TruncateDay(to), TruncateDay(from)).
+			Find(&timeOff).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	memberCalendars := map[string]pmv1.WorkingCalendar{}
+	for _, override := range overrides {
+		memberCalendars[strings.ToLower(override.Email)] = override
+	}
+
+	calendars := make(map[string]*Calendar, len(emails))
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		base := organization
+		if override, ok := memberCalendars[key]; ok {
+			base = override
+		}
+		calendar := NewCalendar(base.WorkingDays, base.HoursPerDay)
+		calendar.Email = email
+		for _, holiday := range holidays {
+			calendar.Holidays[TruncateDay(holiday.Date)] = holiday.Name
+		}
+		for _, off := range timeOff {
+			if strings.EqualFold(off.Email, email) {
+				calendar.TimeOff = append(calendar.TimeOff, off)
+			}
+		}
+		calendars[key] = calendar
+	}
+	return calendars, nil
+}
+
+// LoadCalendar returns the effective calendar of a single member of an organization between from and to.
+func LoadCalendar(tx *gorm.DB, organizationID uuid.UUID, email string, from, to time.Time) (*Calendar, error) {
+	calendars, err := LoadCalendars(tx, organizationID, []string{email}, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return calendars[strings.ToLower(email)], nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// day returns the date of the given day of March 2026, which starts on a Sunday.
+func day(n int) time.Time {
+	return time.Date(2026, time.March, n, 0, 0, 0, 0, time.UTC)
+}
+
+// testCalendar returns a Monday to Friday calendar of 8 hours a day, with a holiday on Wednesday 4 March
+// and time off from Thursday 12 to Monday 16 March.
+func testCalendar() *Calendar {
+	calendar := NewCalendar(pmv1.DefaultWorkingDays, 8)
+	calendar.Holidays[day(4)] = "Company day"
+	calendar.TimeOff = []pmv1.TimeOff{{StartDate: day(12), EndDate: day(16)}}
+	return calendar
+}
+
+func TestCalendarNonWorkingReason(t *testing.T) {
+	calendar := testCalendar()
+	tests := []struct {
+		day  time.Time
+		want string
+	}{
+		{day(2), ""},
+		{day(2).Add(15 * time.Hour), ""},
+		{day(4), "Company day"},
+		{day(7), NonWorkingReasonWeekend},
+		{day(8), NonWorkingReasonWeekend},
+		{day(11), ""},
+		{day(12), NonWorkingReasonTimeOff},
+		{day(16), NonWorkingReasonTimeOff},
+		{day(17), ""},
+	}
+	for _, test := range tests {
+		if got := calendar.NonWorkingReason(test.day); got != test.want {
+			t.Errorf("NonWorkingReason(%s) = %q, want %q", test.day.Format(time.DateOnly), got, test.want)
+		}
+		wantHours := 0.0
+		if test.want == "" {
+			wantHours = 8
+		}
+		if got := calendar.HoursOn(test.day); got != wantHours {
+			t.Errorf("HoursOn(%s) = %v, want %v", test.day.Format(time.DateOnly), got, wantHours)
+		}
+	}
+}
+
+func TestCalendarHolidayOnWeekend(t *testing.T) {
+	calendar := testCalendar()
+	calendar.Holidays[day(8)] = "Sunday holiday"
+	if got := calendar.NonWorkingReason(day(8)); got != "Sunday holiday" {
+		t.Errorf("NonWorkingReason = %q, want the holiday", got)
+	}
+}
+
+func TestCalendarWeeklyHours(t *testing.T) {
+	if got := testCalendar().WeeklyHours(); got != 40 {
+		t.Errorf("WeeklyHours() = %v, want 40 regardless of holidays and time off", got)
+	}
+	if got := NewCalendar(nil, 8).WeeklyHours(); got != 0 {
+		t.Errorf("WeeklyHours() without working days = %v, want 0", got)
+	}
+}
+
+func TestCalendarWorkingDaysBetween(t *testing.T) {
+	calendar := testCalendar()
+	got := calendar.WorkingDaysBetween(day(1), day(17))
+	want := []time.Time{day(2), day(3), day(5), day(6), day(9), day(10), day(11), day(17)}
+	if len(got) != len(want) {
+		t.Fatalf("WorkingDaysBetween = %v, want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Fatalf("WorkingDaysBetween = %v, want %v", got, want)
+		}
+	}
+
+	if got := calendar.WorkingDaysBetween(day(5), day(2)); len(got) != 0 {
+		t.Errorf("WorkingDaysBetween of an inverted range = %v, want none", got)
+	}
+}
+
+func TestCalendarAddWorkingDays(t *testing.T) {
+	calendar := testCalendar()
+	tests := []struct {
+		name  string
+		start time.Time
+		n     int
+		want  time.Time
+	}{
+		{"start is the first working day", day(2), 1, day(2)},
+		{"skips the holiday", day(2), 3, day(5)},
+		{"skips the weekend", day(5), 3, day(9)},
+		{"starts on the next working day", day(7), 1, day(9)},
+		{"skips the time off", day(11), 2, day(17)},
+		{"ignores the time of day", day(2).Add(20 * time.Hour), 2, day(3)},
+		{"zero days", day(7), 0, day(7)},
+		{"negative days", day(9), -2, day(9)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := calendar.AddWorkingDays(test.start, test.n); !got.Equal(test.want) {
+				t.Errorf("AddWorkingDays(%s, %d) = %s, want %s", test.start.Format(time.DateOnly), test.n, got.Format(time.DateOnly), test.want.Format(time.DateOnly))
+			}
+		})
+	}
+
+	if got := NewCalendar(nil, 8).AddWorkingDays(day(2), 5); !got.Equal(day(2)) {
+		t.Errorf("AddWorkingDays without working days = %s, want the start", got.Format(time.DateOnly))
+	}
+}
+
+func TestCalendarDays(t *testing.T) {
+	days := testCalendar().Days(day(3), day(5))
+	want := []pmv1.CalendarDay{
+		{Date: day(3), Working: true, Hours: 8},
+		{Date: day(4), Reason: "Company day"},
+		{Date: day(5), Working: true, Hours: 8},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("Days = %+v, want %+v", days, want)
+	}
+	for i := range want {
+		if days[i] != want[i] {
+			t.Errorf("Days[%d] = %+v, want %+v", i, days[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	v1 "github.com/san-data-systems/common/models/v1"
+	"github.com/san-data-systems/project-management-api/migrations"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// testDB returns a transaction on the scratch Postgres database named by TEST_DATABASE_URL, with the
+// shared tables of the common module and every migration of the service applied. The transaction is
+// rolled back when the test ends. Tests using it are skipped when TEST_DATABASE_URL is unset.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	tx := openTestDB(t).Begin()
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+// openTestDB opens the scratch database of testDB, migrated, for tests that need connections of their
+// own rather than a transaction. It is closed when the test ends.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&v1.Client{}, &v1.Project{}, &v1.ProjectMember{}, &v1.ProjectState{}, &v1.ProjectLabel{},
+		&v1.ProjectFile{}, &v1.Issue{}, &v1.IssueAssignee{}, &v1.IssueLink{}, &v1.IssueFile{}, &v1.IssueActivity{},
+		&v1.TimeEntry{}); err != nil {
+		t.Fatalf("creating the shared tables: %v", err)
+	}
+	if _, err := migrations.Up(context.Background(), sqlDB); err != nil {
+		t.Fatalf("migrating: %v", err)
+	}
+	return db
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/san-data-systems/common/clients/minio"
+	"github.com/san-data-systems/common/config"
+)
+
+// PingPostgres checks that a database connection pool can reach Postgres.
+func PingPostgres(ctx context.Context, db *sql.DB) error {
+	return db.PingContext(ctx)
+}
+
+// PingMinIO checks that MinIO can be reached, with the shared client when it is initialized and with a
+// client for the configured endpoint and credentials otherwise.
+func PingMinIO(ctx context.Context) error {
+	client, err := minio.GetMinIOClient()
+	if err != nil || client == nil {
+		client, err = minio.NewMinIOClient(
+			config.Config.MinIOEndpoint,
+			config.Config.MinIOAccessKey,
+			config.Config.MinIOSecretKey,
+			config.Config.MinIOSSL,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return client.CheckConnection(ctx)
+}
+
+// PingRedis checks that the configured Redis server accepts the configured password and answers a
+// PING. It speaks the Redis protocol directly so that the check does not depend on a client library.
+func PingRedis(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(config.Config.RedisHost, config.Config.RedisPort))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	reader := bufio.NewReader(conn)
+	if config.Config.RedisPassword != "" {
+		if err := redisCommand(conn, reader, "+OK", "AUTH", config.Config.RedisPassword); err != nil {
+			return err
+		}
+	}
+	return redisCommand(conn, reader, "+PONG", "PING")
+}
+
+// redisCommand sends a command to Redis and checks that it answers with the expected simple string.
+func redisCommand(conn net.Conn, reader *bufio.Reader, expected string, args ...string) error {
+	command := fmt.Sprintf("*%d\r\n", len(args))
+	for _, arg := range args {
+		command += fmt.Sprintf("$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := conn.Write([]byte(command)); err != nil {
+		return err
+	}
+
+	reply, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	if reply = strings.TrimRight(reply, "\r\n"); reply != expected {
+		return fmt.Errorf("redis %s: %s", args[0], strings.TrimPrefix(reply, "-"))
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// SyncGroupTeams brings the team memberships a user holds through group mappings in line with the
+// identity provider groups the user currently belongs to. The user is added to the teams mapped to
+// those groups, joining their organizations, and removed from mapped teams that group sync added them
+// to when no group maps to them anymore. Memberships added by hand are left alone.
+func SyncGroupTeams(tx *gorm.DB, email string, groups []string) error {
+	var mappings []pmv1.GroupMapping
+	if err := tx.Find(&mappings).Error; err != nil {
+		return err
+	}
+	if len(mappings) == 0 {
+		return nil
+	}
+
+	inGroup := make(map[string]bool, len(groups))
+	for _, group := range groups {
+		inGroup[group] = true
+	}
+
+	granted := map[uuid.UUID]uuid.UUID{}
+	var mapped []uuid.UUID
+	for _, mapping := range mappings {
+		mapped = append(mapped, mapping.TeamID)
+		if inGroup[mapping.Group] {
+			granted[mapping.TeamID] = mapping.OrganizationID
+		}
+	}
+
+	for teamID, organizationID := range granted {
+		if err := JoinOrganization(tx, organizationID, email, pmv1.GroupSyncAddedBy); err != nil {
+			return err
+		}
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pmv1.TeamMember{
+			TeamID:  teamID,
+			Email:   email,
+			AddedBy: pmv1.GroupSyncAddedBy,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	revoked := tx.Where("LOWER(email) = LOWER(?) AND added_by = ? AND team_id IN ?", email, pmv1.GroupSyncAddedBy, mapped)
+	if len(granted) > 0 {
+		kept := make([]uuid.UUID, 0, len(granted))
+		for teamID := range granted {
+			kept = append(kept, teamID)
+		}
+		revoked = revoked.Where("team_id NOT IN ?", kept)
+	}
+	return revoked.Delete(&pmv1.TeamMember{}).Error
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/san-data-systems/common/config"
+	"github.com/san-data-systems/common/databases"
+)
+
+// Readiness statuses.
+const (
+	ReadinessReady    = "ready"
+	ReadinessNotReady = "not ready"
+	ReadinessDraining = "draining"
+)
+
+// defaultReadinessTimeout bounds each dependency check when READINESS_TIMEOUT is not set.
+const defaultReadinessTimeout = 2 * time.Second
+
+// draining is set once the server starts shutting down.
+var draining atomic.Bool
+
+// SetDraining marks the service as shutting down, which fails readiness so that no new traffic is
+// routed to it while in-flight requests complete.
+func SetDraining() {
+	draining.Store(true)
+}
+
+// Draining reports whether the service is shutting down.
+func Draining() bool {
+	return draining.Load()
+}
+
+// DependencyStatus is the result of checking a dependency.
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMS int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Readiness is the readiness of the service and of each of its dependencies.
+type Readiness struct {
+	Status string                      `json:"status"`
+	Checks map[string]DependencyStatus `json:"checks"`
+}
+
+// Ready reports whether the service can take traffic.
+func (r Readiness) Ready() bool {
+	return r.Status == ReadinessReady
+}
+
+// CheckReadiness checks Postgres, and MinIO and Redis when they are enabled, concurrently and each within
+// READINESS_TIMEOUT (2s by default). A draining service is not ready, whatever its dependencies.
+func CheckReadiness(ctx context.Context) Readiness {
+	timeout := defaultReadinessTimeout
+	if value, err := time.ParseDuration(os.Getenv("READINESS_TIMEOUT")); err == nil && value > 0 {
+		timeout = value
+	}
+
+	checks := map[string]func(ctx context.Context) error{"postgres": pingDatabase}
+	if config.Config.UseMinIO {
+		checks["minio"] = PingMinIO
+	}
+	if config.Config.UseRedis {
+		checks["redis"] = PingRedis
+	}
+
+	readiness := Readiness{Status: ReadinessReady, Checks: make(map[string]DependencyStatus, len(checks))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			start := time.Now()
+			err := check(checkCtx)
+			status := DependencyStatus{Status: "ok", LatencyMS: time.Since(start).Milliseconds()}
+			if err != nil {
+				status.Status = "error"
+				status.Error = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			readiness.Checks[name] = status
+			if err != nil && readiness.Status == ReadinessReady {
+				readiness.Status = ReadinessNotReady
+			}
+		}(name, check)
+	}
+	wg.Wait()
+
+	if Draining() {
+		readiness.Status = ReadinessDraining
+	}
+	return readiness
+}
+
+// pingDatabase pings the shared database connection pool.
+func pingDatabase(ctx context.Context) error {
+	db, err := databases.DB.DB()
+	if err != nil {
+		return err
+	}
+	return PingPostgres(ctx, db)
+}
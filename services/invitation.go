@@ -0,0 +1,219 @@
+package services
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InvitationError is returned when an invitation cannot be created or answered in its current state.
+// Expired is set when the invitation can no longer be used because it expired.
+type InvitationError struct {
+	Reason  string
+	Expired bool
+}
+
+// Error implements the error interface.
+func (e *InvitationError) Error() string {
+	return e.Reason
+}
+
+// NewInvitationToken returns a random single-use token and the hash stored in its place.
+func NewInvitationToken() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+	return token, HashInvitationToken(token), nil
+}
+
+// HashInvitationToken returns the hash under which an invitation token is stored.
+func HashInvitationToken(token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	return hex.EncodeToString(sum[:])
+}
+
+// InvitationExpiry returns the expiry of an invitation sent now and valid for days, or for
+// DefaultInvitationExpiryDays when days is nil.
+func InvitationExpiry(days *int) time.Time {
+	valid := pmv1.DefaultInvitationExpiryDays
+	if days != nil {
+		valid = *days
+	}
+	return time.Now().AddDate(0, 0, valid)
+}
+
+// CreateInvitation invites an email address to a project and returns the invitation with its token.
+// Inviting an address that already has a pending invitation replaces its role, token and expiry.
+func CreateInvitation(tx *gorm.DB, projectID uuid.UUID, email, role, invitedBy string, expiresAt time.Time) (pmv1.ProjectInvitation, string, error) {
+	var invitation pmv1.ProjectInvitation
+
+	existing, err := ProjectRole(tx, projectID, email)
+	if err != nil {
+		return invitation, "", err
+	}
+	if existing != "" {
+		return invitation, "", &InvitationError{Reason: fmt.Sprintf("%s is already a member of the project.", email)}
+	}
+
+	token, hash, err := NewInvitationToken()
+	if err != nil {
+		return invitation, "", err
+	}
+
+	err = tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("project_id = ? AND LOWER(email) = LOWER(?) AND status = ?", projectID, email, pmv1.InvitationStatusPending).
+		First(&invitation).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return invitation, "", err
+	}
+
+	invitation.ProjectID = projectID
+	invitation.Email = email
+	invitation.Role = role
+	invitation.TokenHash = hash
+	invitation.Status = pmv1.InvitationStatusPending
+	invitation.InvitedBy = invitedBy
+	invitation.ExpiresAt = expiresAt
+	if err := tx.Save(&invitation).Error; err != nil {
+		return invitation, "", err
+	}
+	return invitation, token, nil
+}
+
+// RenewInvitation issues a new token for a pending invitation, invalidating the previous one, and moves
+// its expiry.
+func RenewInvitation(tx *gorm.DB, invitation *pmv1.ProjectInvitation, expiresAt time.Time) (string, error) {
+	if invitation.Status != pmv1.InvitationStatusPending {
+		return "", &InvitationError{Reason: fmt.Sprintf("Invitation is %s.", invitation.Status)}
+	}
+
+	token, hash, err := NewInvitationToken()
+	if err != nil {
+		return "", err
+	}
+	invitation.TokenHash = hash
+	invitation.ExpiresAt = expiresAt
+	return token, tx.Save(invitation).Error
+}
+
+// RevokeInvitation withdraws a pending invitation.
+func RevokeInvitation(tx *gorm.DB, invitation *pmv1.ProjectInvitation) error {
+	return respondToInvitation(tx, invitation, pmv1.InvitationStatusRevoked)
+}
+
+// AcceptInvitation makes the invitee a member of the project with the invited role, and of the
+// organization of the project. The invitation is only found when the token was sent to email, so that a
+// leaked token cannot be used by another user.
+func AcceptInvitation(tx *gorm.DB, token, email string) (pmv1.ProjectInvitation, error) {
+	invitation, err := usableInvitation(tx, token, email)
+	if err != nil {
+		return invitation, err
+	}
+
+	organizationID, err := ProjectOrganizationID(tx, invitation.ProjectID)
+	if err != nil {
+		return invitation, err
+	}
+	if _, exists, err := RolePermissions(tx, organizationID, invitation.Role); err != nil {
+		return invitation, err
+	} else if !exists {
+		return invitation, &InvitationError{Reason: fmt.Sprintf("Role %q no longer exists. Ask for a new invitation.", invitation.Role)}
+	}
+
+	var project v1.Project
+	if err := tx.Where("id = ? AND deleted_at IS NULL", invitation.ProjectID).First(&project).Error; err != nil {
+		return invitation, err
+	}
+
+	existing, err := ProjectRole(tx, invitation.ProjectID, email)
+	if err != nil {
+		return invitation, err
+	}
+	if existing == "" {
+		if err := tx.Create(&v1.ProjectMember{ProjectID: invitation.ProjectID, Email: invitation.Email, Role: invitation.Role}).Error; err != nil {
+			return invitation, err
+		}
+	}
+	if organizationID != uuid.Nil {
+		if err := JoinOrganization(tx, organizationID, invitation.Email, invitation.InvitedBy); err != nil {
+			return invitation, err
+		}
+	}
+
+	return invitation, respondToInvitation(tx, &invitation, pmv1.InvitationStatusAccepted)
+}
+
+// DeclineInvitation declines an invitation sent to email.
+func DeclineInvitation(tx *gorm.DB, token, email string) (pmv1.ProjectInvitation, error) {
+	invitation, err := usableInvitation(tx, token, email)
+	if err != nil {
+		return invitation, err
+	}
+	return invitation, respondToInvitation(tx, &invitation, pmv1.InvitationStatusDeclined)
+}
+
+// InvitationMail builds the email inviting the invitee of an invitation to a project. The accept link
+// is built from INVITATION_URL, to which the token is appended as a query parameter.
+func InvitationMail(invitation pmv1.ProjectInvitation, projectName, token string) Mail {
+	var body strings.Builder
+	fmt.Fprintf(&body, "%s invited you to join the project %s as %s.\n\n", invitation.InvitedBy, projectName, invitation.Role)
+	if base := os.Getenv("INVITATION_URL"); base != "" {
+		separator := "?"
+		if strings.Contains(base, "?") {
+			separator = "&"
+		}
+		fmt.Fprintf(&body, "Accept or decline the invitation at %s%stoken=%s\n\n", base, separator, token)
+	} else {
+		fmt.Fprintf(&body, "Your invitation token is: %s\n\n", token)
+	}
+	fmt.Fprintf(&body, "The invitation expires on %s and can be used once.\n", invitation.ExpiresAt.UTC().Format(time.RFC1123))
+
+	return Mail{
+		To:      invitation.Email,
+		Subject: fmt.Sprintf("You are invited to join %s", projectName),
+		Body:    body.String(),
+	}
+}
+
+// usableInvitation loads and locks the pending invitation of a token sent to email.
+func usableInvitation(tx *gorm.DB, token, email string) (pmv1.ProjectInvitation, error) {
+	var invitation pmv1.ProjectInvitation
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND LOWER(email) = LOWER(?)", HashInvitationToken(token), email).
+		First(&invitation).Error; err != nil {
+		return invitation, err
+	}
+
+	if invitation.Status != pmv1.InvitationStatusPending {
+		return invitation, &InvitationError{Reason: fmt.Sprintf("Invitation is %s.", invitation.Status)}
+	}
+	if time.Now().After(invitation.ExpiresAt) {
+		return invitation, &InvitationError{Reason: "Invitation has expired.", Expired: true}
+	}
+	return invitation, nil
+}
+
+// respondToInvitation closes a pending invitation with a status. Its token can no longer be used.
+func respondToInvitation(tx *gorm.DB, invitation *pmv1.ProjectInvitation, status string) error {
+	if invitation.Status != pmv1.InvitationStatusPending {
+		return &InvitationError{Reason: fmt.Sprintf("Invitation is %s.", invitation.Status)}
+	}
+
+	now := time.Now()
+	invitation.Status = status
+	invitation.RespondedAt = &now
+	return tx.Model(invitation).Updates(map[string]interface{}{"status": status, "responded_at": now}).Error
+}
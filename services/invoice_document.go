@@ -0,0 +1,62 @@
+package services
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+// InvoiceDocument is the data rendered into a printable invoice.
+type InvoiceDocument struct {
+	Invoice    pmv1.InvoiceResponse
+	ClientName string
+	Country    string
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"money": func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"date":  func(v time.Time) string { return v.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.Number}}</title>
+<style>
+body { font-family: Helvetica, Arial, sans-serif; margin: 40px; color: #222; }
+h1 { margin-bottom: 0; }
+table { width: 100%; border-collapse: collapse; margin-top: 24px; }
+th, td { border-bottom: 1px solid #ddd; padding: 6px 8px; text-align: left; }
+td.num, th.num { text-align: right; }
+tfoot td { font-weight: bold; border-top: 2px solid #222; }
+@media print { body { margin: 0; } }
+</style>
+</head>
+<body>
+<h1>Invoice {{.Invoice.Number}}</h1>
+<p>Status: {{.Invoice.Status}}</p>
+<p>
+<strong>{{.ClientName}}</strong>{{if .Country}}<br>{{.Country}}{{end}}<br>
+Period: {{date .Invoice.PeriodStart}} to {{date .Invoice.PeriodEnd}}
+</p>
+<table>
+<thead>
+<tr><th>Date</th><th>Member</th><th>Description</th><th class="num">Hours</th><th class="num">Rate ({{.Invoice.Currency}})</th><th class="num">Amount ({{.Invoice.Currency}})</th></tr>
+</thead>
+<tbody>
+{{range .Invoice.Lines}}<tr><td>{{date .Date}}</td><td>{{.Email}}</td><td>{{.Description}}</td><td class="num">{{money .Hours}}</td><td class="num">{{money .HourlyRate}}</td><td class="num">{{money .Amount}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+<tr><td colspan="3">Total</td><td class="num">{{money .Invoice.TotalHours}}</td><td></td><td class="num">{{money .Invoice.TotalAmount}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// RenderInvoiceHTML writes a printable HTML document for an invoice.
+func RenderInvoiceHTML(w io.Writer, doc InvoiceDocument) error {
+	return invoiceTemplate.Execute(w, doc)
+}
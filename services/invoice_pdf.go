@@ -0,0 +1,212 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PDF page geometry, in points: A4 with the table columns of the HTML invoice.
+const (
+	pdfPageWidth    = 595.28
+	pdfPageHeight   = 841.89
+	pdfMargin       = 50.0
+	pdfTableSize    = 9.0
+	pdfRowHeight    = 14.0
+	pdfColumnMember = 112.0
+	pdfColumnDesc   = 250.0
+	pdfColumnHours  = 405.0
+	pdfColumnRate   = 475.0
+	pdfColumnAmount = pdfPageWidth - pdfMargin
+)
+
+// helveticaWidths are the widths of the printable ASCII characters in Helvetica, in thousandths of the
+// font size, used to right-align numbers and to cut text to its column.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+// winAnsi maps the common typographic characters outside Latin-1 to their WinAnsi codes.
+var winAnsi = map[rune]byte{
+	'€': 0x80, '…': 0x85, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+}
+
+// pdfDocument lays out text on the pages of a PDF document using the standard Helvetica fonts.
+type pdfDocument struct {
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+// RenderInvoicePDF writes a printable PDF document for an invoice, with the same content as the HTML one.
+func RenderInvoicePDF(w io.Writer, doc InvoiceDocument) error {
+	invoice := doc.Invoice
+	pdf := &pdfDocument{}
+	pdf.newPage()
+
+	pdf.text("F2", 18, pdfMargin, pdf.y, "Invoice "+invoice.Number)
+	pdf.y -= 24
+	pdf.text("F1", 10, pdfMargin, pdf.y, "Status: "+invoice.Status)
+	pdf.y -= 20
+	pdf.text("F2", 10, pdfMargin, pdf.y, doc.ClientName)
+	if doc.Country != "" {
+		pdf.y -= 14
+		pdf.text("F1", 10, pdfMargin, pdf.y, doc.Country)
+	}
+	pdf.y -= 14
+	pdf.text("F1", 10, pdfMargin, pdf.y, fmt.Sprintf("Period: %s to %s", invoice.PeriodStart.Format("2006-01-02"), invoice.PeriodEnd.Format("2006-01-02")))
+	pdf.y -= 30
+
+	header := func() {
+		pdf.text("F2", pdfTableSize, pdfMargin, pdf.y, "Date")
+		pdf.text("F2", pdfTableSize, pdfColumnMember, pdf.y, "Member")
+		pdf.text("F2", pdfTableSize, pdfColumnDesc, pdf.y, "Description")
+		pdf.textRight("F2", pdfTableSize, pdfColumnHours, pdf.y, "Hours")
+		pdf.textRight("F2", pdfTableSize, pdfColumnRate, pdf.y, "Rate ("+invoice.Currency+")")
+		pdf.textRight("F2", pdfTableSize, pdfColumnAmount, pdf.y, "Amount ("+invoice.Currency+")")
+		pdf.rule(pdf.y-4, 0.5)
+		pdf.y -= pdfRowHeight + 2
+	}
+	header()
+
+	for _, line := range invoice.Lines {
+		if pdf.y < pdfMargin+2*pdfRowHeight {
+			pdf.newPage()
+			header()
+		}
+		pdf.text("F1", pdfTableSize, pdfMargin, pdf.y, line.Date.Format("2006-01-02"))
+		pdf.text("F1", pdfTableSize, pdfColumnMember, pdf.y, fitText(line.Email, pdfTableSize, pdfColumnDesc-pdfColumnMember-8))
+		pdf.text("F1", pdfTableSize, pdfColumnDesc, pdf.y, fitText(line.Description, pdfTableSize, pdfColumnHours-pdfColumnDesc-40))
+		pdf.textRight("F1", pdfTableSize, pdfColumnHours, pdf.y, fmt.Sprintf("%.2f", line.Hours))
+		pdf.textRight("F1", pdfTableSize, pdfColumnRate, pdf.y, fmt.Sprintf("%.2f", line.HourlyRate))
+		pdf.textRight("F1", pdfTableSize, pdfColumnAmount, pdf.y, fmt.Sprintf("%.2f", line.Amount))
+		pdf.y -= pdfRowHeight
+	}
+
+	if pdf.y < pdfMargin+2*pdfRowHeight {
+		pdf.newPage()
+	}
+	pdf.rule(pdf.y+pdfRowHeight-4, 1.5)
+	pdf.text("F2", pdfTableSize, pdfMargin, pdf.y, "Total")
+	pdf.textRight("F2", pdfTableSize, pdfColumnHours, pdf.y, fmt.Sprintf("%.2f", invoice.TotalHours))
+	pdf.textRight("F2", pdfTableSize, pdfColumnAmount, pdf.y, fmt.Sprintf("%.2f", invoice.TotalAmount))
+
+	_, err := w.Write(pdf.bytes(invoice.Number))
+	return err
+}
+
+// newPage starts a page and moves to its top.
+func (p *pdfDocument) newPage() {
+	p.page = &bytes.Buffer{}
+	p.pages = append(p.pages, p.page)
+	p.y = pdfPageHeight - pdfMargin
+}
+
+// text writes text starting at the given position.
+func (p *pdfDocument) text(font string, size, x, y float64, s string) {
+	fmt.Fprintf(p.page, "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, pdfString(s))
+}
+
+// textRight writes text ending at the given position. Bold text is measured with the regular widths,
+// which are close enough for the short labels and numbers aligned this way.
+func (p *pdfDocument) textRight(font string, size, x, y float64, s string) {
+	p.text(font, size, x-textWidth(s, size), y, s)
+}
+
+// rule draws a horizontal line across the table.
+func (p *pdfDocument) rule(y, width float64) {
+	fmt.Fprintf(p.page, "%.1f w %.2f %.2f m %.2f %.2f l S\n", width, pdfMargin, y, pdfColumnAmount, y)
+}
+
+// bytes assembles the pages into a PDF file, numbering them in their footers.
+func (p *pdfDocument) bytes(title string) []byte {
+	var out bytes.Buffer
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 6+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Title (%s) /Producer (project-management-api) >>", pdfString(title)))
+
+	for i, page := range p.pages {
+		footer := fmt.Sprintf("Page %d of %d", i+1, len(p.pages))
+		fmt.Fprintf(page, "BT /F1 8.0 Tf %.2f %.2f Td (%s) Tj ET\n", pdfColumnAmount-textWidth(footer, 8), pdfMargin/2, footer)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, 7+2*i))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", page.Len(), page.Bytes()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes()
+}
+
+// pdfString encodes text as the content of a PDF string in the WinAnsi encoding of the fonts. Characters
+// WinAnsi cannot encode are replaced with a question mark.
+func pdfString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r == '\n' || r == '\r' || r == '\t':
+			b.WriteByte(' ')
+		case r >= 0x20 && r < 0x7f, r >= 0xa0 && r <= 0xff:
+			b.WriteByte(byte(r))
+		case winAnsi[r] != 0:
+			b.WriteByte(winAnsi[r])
+		default:
+			b.WriteByte('?')
+		}
+	}
+	return b.String()
+}
+
+// textWidth returns the width of text in Helvetica at the given size.
+func textWidth(s string, size float64) float64 {
+	width := 0
+	for _, r := range s {
+		if r >= 0x20 && r < 0x7f {
+			width += helveticaWidths[r-0x20]
+		} else {
+			width += 556
+		}
+	}
+	return float64(width) * size / 1000
+}
+
+// fitText cuts text to the given width, ending it with an ellipsis when it is cut.
+func fitText(s string, size, width float64) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if textWidth(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && textWidth(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestRenderInvoicePDF(t *testing.T) {
+	day := time.Date(2025, time.March, 3, 0, 0, 0, 0, time.UTC)
+	invoice := pmv1.InvoiceResponse{
+		Number:      "INV-202503-00007",
+		Status:      pmv1.InvoiceStatusDraft,
+		Currency:    "EUR",
+		PeriodStart: day,
+		PeriodEnd:   day.AddDate(0, 0, 30),
+	}
+	// Enough lines to fill more than one page.
+	for i := 0; i < 80; i++ {
+		invoice.Lines = append(invoice.Lines, pmv1.InvoiceLineResponse{
+			Email:       "jane@example.com",
+			Date:        day,
+			Description: fmt.Sprintf("Review (round %d) \\ fixes – €", i),
+			Hours:       1.5,
+			HourlyRate:  100,
+			Amount:      150,
+		})
+		invoice.TotalHours += 1.5
+		invoice.TotalAmount += 150
+	}
+
+	var buf bytes.Buffer
+	if err := RenderInvoicePDF(&buf, InvoiceDocument{Invoice: invoice, ClientName: "Acme (EU)", Country: "Germany"}); err != nil {
+		t.Fatal(err)
+	}
+	pdf := buf.Bytes()
+
+	if !bytes.HasPrefix(pdf, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(pdf, []byte("%%EOF\n")) {
+		t.Fatalf("document is not framed as a PDF file: %q ... %q", pdf[:16], pdf[len(pdf)-16:])
+	}
+
+	// startxref points at the cross-reference table, whose entries point at the objects in order.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n%%EOF\n$`).FindSubmatch(pdf)
+	if startxref == nil {
+		t.Fatal("no startxref")
+	}
+	xref, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(pdf[xref:], []byte("xref\n0 ")) {
+		t.Fatalf("startxref %d does not point at the cross-reference table", xref)
+	}
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(pdf[xref:], -1)
+	for i, offset := range offsets {
+		at, _ := strconv.Atoi(string(offset[1]))
+		if want := fmt.Sprintf("%d 0 obj\n", i+1); !bytes.HasPrefix(pdf[at:], []byte(want)) {
+			t.Errorf("cross-reference entry %d points at %q, want %q", i+1, pdf[at:at+10], want)
+		}
+	}
+	if !bytes.Contains(pdf, []byte(fmt.Sprintf("trailer\n<< /Size %d /Root 1 0 R", len(offsets)+1))) {
+		t.Errorf("trailer does not count the %d objects", len(offsets))
+	}
+
+	// Every content stream is as long as its declared length.
+	streams := regexp.MustCompile(`(?s)<< /Length (\d+) >>\nstream\n(.*?)\nendstream`).FindAllSubmatch(pdf, -1)
+	for i, stream := range streams {
+		if length, _ := strconv.Atoi(string(stream[1])); length != len(stream[2]) {
+			t.Errorf("stream %d declares %d bytes, holds %d", i+1, length, len(stream[2]))
+		}
+	}
+	pages := bytes.Count(pdf, []byte("/Type /Page /Parent"))
+	if pages < 2 || len(streams) != pages || !bytes.Contains(pdf, []byte(fmt.Sprintf("/Count %d", pages))) {
+		t.Fatalf("%d pages with %d content streams, want the lines spread over several pages", pages, len(streams))
+	}
+
+	for _, text := range []string{
+		"(Invoice INV-202503-00007)",
+		"(Acme \\(EU\\))",
+		"(Review \\(round 0\\) \\\\ fixes \x96 \x80)",
+		"(Amount \\(EUR\\))",
+		"(12000.00)",
+		fmt.Sprintf("(Page %d of %d)", pages, pages),
+	} {
+		if !bytes.Contains(pdf, []byte(text)) {
+			t.Errorf("document does not show %q", text)
+		}
+	}
+}
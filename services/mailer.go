@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/san-data-systems/common/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// Mail is an email sent by the service.
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails. Implementations must be safe for concurrent use.
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+// LogMailer writes emails to the application log instead of sending them. It is meant for development.
+type LogMailer struct{}
+
+// Send implements Mailer.
+func (LogMailer) Send(_ context.Context, mail Mail) error {
+	logger.LogInfo("Email not sent, logged instead.", logrus.Fields{"to": mail.To, "subject": mail.Subject, "body": mail.Body})
+	return nil
+}
+
+// FileMailer appends emails to a local file instead of sending them. It is meant for development.
+type FileMailer struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Send implements Mailer.
+func (m *FileMailer) Send(_ context.Context, mail Mail) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = fmt.Fprintf(file, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n---\n\n", time.Now().Format(time.RFC1123Z), mail.To, mail.Subject, mail.Body)
+	return err
+}
+
+// SMTPMailer sends emails through an SMTP server, authenticating when a username is set.
+type SMTPMailer struct {
+	Addr     string
+	From     string
+	Username string
+	Password string
+}
+
+// Send implements Mailer.
+func (m SMTPMailer) Send(_ context.Context, mail Mail) error {
+	var auth smtp.Auth
+	if m.Username != "" {
+		host := m.Addr
+		if i := strings.LastIndex(host, ":"); i >= 0 {
+			host = host[:i]
+		}
+		auth = smtp.PlainAuth("", m.Username, m.Password, host)
+	}
+
+	message := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		m.From, mail.To, mail.Subject, strings.ReplaceAll(mail.Body, "\n", "\r\n"))
+	return smtp.SendMail(m.Addr, auth, m.From, []string{mail.To}, []byte(message))
+}
+
+var (
+	mailerMu sync.RWMutex
+	mailer   Mailer = LogMailer{}
+)
+
+// SetMailer replaces the mailer used to send emails.
+func SetMailer(m Mailer) {
+	mailerMu.Lock()
+	defer mailerMu.Unlock()
+	mailer = m
+}
+
+// SendMail sends an email with the configured mailer.
+func SendMail(ctx context.Context, mail Mail) error {
+	mailerMu.RLock()
+	m := mailer
+	mailerMu.RUnlock()
+	return m.Send(ctx, mail)
+}
+
+// MailerFromEnv builds the mailer selected by the MAIL_SENDER environment variable: "smtp" (configured by
+// SMTP_ADDR, SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM), "file" (writing to MAIL_FILE_PATH, mail.log by
+// default) or "log", the default.
+func MailerFromEnv() (Mailer, error) {
+	switch sender := strings.ToLower(os.Getenv("MAIL_SENDER")); sender {
+	case "", "log":
+		return LogMailer{}, nil
+	case "file":
+		path := os.Getenv("MAIL_FILE_PATH")
+		if path == "" {
+			path = "mail.log"
+		}
+		return &FileMailer{Path: path}, nil
+	case "smtp":
+		addr, from := os.Getenv("SMTP_ADDR"), os.Getenv("MAIL_FROM")
+		if addr == "" || from == "" {
+			return nil, fmt.Errorf("SMTP_ADDR and MAIL_FROM are required by the smtp mail sender")
+		}
+		return SMTPMailer{
+			Addr:     addr,
+			From:     from,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail sender %q", sender)
+	}
+}
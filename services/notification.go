@@ -0,0 +1,75 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// ProjectManagerEmails returns the creator of a project together with every member holding a role allowed
+// to manage its members.
+func ProjectManagerEmails(tx *gorm.DB, projectID uuid.UUID) ([]string, error) {
+	var project v1.Project
+	if err := tx.Select("id, created_by").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return nil, err
+	}
+
+	organizationID, err := ProjectOrganizationID(tx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := RolesWithPermission(tx, organizationID, pmv1.PermissionMemberManage)
+	if err != nil {
+		return nil, err
+	}
+
+	var memberEmails []string
+	if err := tx.Model(&v1.ProjectMember{}).
+		Where("project_id = ? AND role IN ?", projectID, roles).
+		Pluck("email", &memberEmails).Error; err != nil {
+		return nil, err
+	}
+
+	var teamEmails []string
+	if err := tx.Model(&pmv1.TeamMember{}).
+		Joins("JOIN project_teams ON project_teams.team_id = team_members.team_id").
+		Where("project_teams.project_id = ? AND project_teams.role IN ?", projectID, roles).
+		Pluck("team_members.email", &teamEmails).Error; err != nil {
+		return nil, err
+	}
+	memberEmails = append(memberEmails, teamEmails...)
+
+	seen := map[string]bool{}
+	var emails []string
+	for _, email := range append([]string{project.CreatedBy}, memberEmails...) {
+		key := strings.ToLower(email)
+		if email == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+// NotifyUsers creates an in-app notification for each recipient.
+func NotifyUsers(tx *gorm.DB, recipients []string, projectID *uuid.UUID, notificationType, title, message string) error {
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	notifications := make([]pmv1.Notification, 0, len(recipients))
+	for _, recipient := range recipients {
+		notifications = append(notifications, pmv1.Notification{
+			Email:     recipient,
+			ProjectID: projectID,
+			Type:      notificationType,
+			Title:     title,
+			Message:   message,
+		})
+	}
+	return tx.Create(&notifications).Error
+}
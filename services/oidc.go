@@ -0,0 +1,320 @@
+package services
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/san-data-systems/project-management-api/tracing"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrUnknownSigningKey is returned when a token is signed with a key missing from the JWKS, even after
+// refreshing it.
+var ErrUnknownSigningKey = errors.New("unknown signing key")
+
+// jwksMinRefreshInterval limits how often the JWKS is fetched, so that tokens with made-up key IDs or
+// an identity provider outage do not turn every request into a fetch.
+var jwksMinRefreshInterval = time.Minute
+
+// JWKS is a cached JSON Web Key Set. Keys are fetched on first use and refreshed once the cache is
+// older than RefreshInterval, or sooner when a token names a key ID the cache does not know, which is
+// how key rotation at the identity provider is picked up. Fetches happen without holding the cache
+// lock, and concurrent lookups share a single fetch.
+type JWKS struct {
+	URL             string
+	RefreshInterval time.Duration
+	Client          *http.Client
+
+	fetches     singleflight.Group
+	mu          sync.Mutex
+	keys        map[string]crypto.PublicKey
+	fetchedAt   time.Time
+	attemptedAt time.Time
+}
+
+// Key returns the public key with the given key ID, refreshing the key set when needed.
+func (s *JWKS) Key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	s.mu.Lock()
+	_, known := s.keys[kid]
+	stale := !known || time.Since(s.fetchedAt) >= s.RefreshInterval
+	due := stale && time.Since(s.attemptedAt) >= jwksMinRefreshInterval
+	s.mu.Unlock()
+
+	if due {
+		_, err, _ := s.fetches.Do("", func() (interface{}, error) {
+			return nil, s.refresh(ctx)
+		})
+		s.mu.Lock()
+		unavailable := s.keys == nil
+		s.mu.Unlock()
+		if err != nil && unavailable {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.keys == nil {
+		return nil, errors.New("JWKS is not available")
+	}
+	key, ok := s.keys[kid]
+	if !ok {
+		return nil, ErrUnknownSigningKey
+	}
+	return key, nil
+}
+
+// refresh fetches the key set and swaps it in. Keys of unsupported types are skipped. On failure the
+// previous keys are kept so that an identity provider outage does not sign everyone out.
+func (s *JWKS) refresh(ctx context.Context) error {
+	s.mu.Lock()
+	if time.Since(s.attemptedAt) < jwksMinRefreshInterval {
+		// Another lookup fetched the key set since this one found it stale.
+		s.mu.Unlock()
+		return nil
+	}
+	s.attemptedAt = time.Now()
+	s.mu.Unlock()
+
+	var set struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := getJSON(ctx, s.Client, s.URL, &set); err != nil {
+		return fmt.Errorf("fetching JWKS: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil {
+				continue
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+		case "EC":
+			var curve elliptic.Curve
+			switch jwk.Crv {
+			case "P-256":
+				curve = elliptic.P256()
+			case "P-384":
+				curve = elliptic.P384()
+			case "P-521":
+				curve = elliptic.P521()
+			default:
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.mu.Unlock()
+	return nil
+}
+
+// OIDCIdentity is the user a valid OIDC token identifies.
+type OIDCIdentity struct {
+	Email  string
+	Groups []string
+}
+
+// OIDCVerifier validates JWTs issued by an OpenID Connect identity provider. Audience is required: the
+// identity provider signs tokens for all of its clients, and only those issued for this service may be
+// accepted.
+type OIDCVerifier struct {
+	Issuer      string
+	Audience    string
+	EmailClaim  string
+	GroupsClaim string
+	JWKS        *JWKS
+}
+
+// Handles reports whether a token was issued by the verifier's identity provider, judging by its
+// unverified iss claim. Tokens it does not handle are left to the service's own JWT validation.
+func (v *OIDCVerifier) Handles(token string) bool {
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return false
+	}
+	issuer, _ := claims.GetIssuer()
+	return issuer == v.Issuer
+}
+
+// Verify checks the signature, issuer, audience and lifetime of a token and returns the identity it
+// carries. Tokens without an email, or whose email the identity provider marks as unverified, are
+// rejected, as are all tokens when the verifier has no audience.
+func (v *OIDCVerifier) Verify(ctx context.Context, token string) (OIDCIdentity, error) {
+	if v.Audience == "" {
+		return OIDCIdentity{}, errors.New("OIDC verifier has no audience")
+	}
+	options := []jwt.ParserOption{
+		jwt.WithIssuer(v.Issuer),
+		jwt.WithAudience(v.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(30 * time.Second),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}),
+	}
+
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.JWKS.Key(ctx, kid)
+	}, options...)
+	if err != nil {
+		return OIDCIdentity{}, err
+	}
+
+	email, _ := claims[v.EmailClaim].(string)
+	if email == "" {
+		return OIDCIdentity{}, fmt.Errorf("token has no %s claim", v.EmailClaim)
+	}
+	if verified, ok := claims["email_verified"].(bool); ok && !verified {
+		return OIDCIdentity{}, errors.New("token email is not verified")
+	}
+
+	identity := OIDCIdentity{Email: email}
+	switch groups := claims[v.GroupsClaim].(type) {
+	case string:
+		identity.Groups = []string{groups}
+	case []interface{}:
+		for _, group := range groups {
+			if name, ok := group.(string); ok {
+				identity.Groups = append(identity.Groups, name)
+			}
+		}
+	}
+	return identity, nil
+}
+
+var (
+	oidcVerifierMu sync.RWMutex
+	oidcVerifier   *OIDCVerifier
+)
+
+// SetOIDCVerifier sets the verifier used for OIDC tokens. A nil verifier disables OIDC authentication.
+func SetOIDCVerifier(v *OIDCVerifier) {
+	oidcVerifierMu.Lock()
+	defer oidcVerifierMu.Unlock()
+	oidcVerifier = v
+}
+
+// CurrentOIDCVerifier returns the verifier used for OIDC tokens, or nil when OIDC is not configured.
+func CurrentOIDCVerifier() *OIDCVerifier {
+	oidcVerifierMu.RLock()
+	defer oidcVerifierMu.RUnlock()
+	return oidcVerifier
+}
+
+// OIDCVerifierFromEnv builds the verifier configured by the OIDC_ISSUER environment variable, or returns
+// nil when it is unset. OIDC_AUDIENCE, the aud claim of the tokens issued for this service (usually its
+// client ID), is then required: without it any token of the issuer would be accepted. The JWKS is read
+// from OIDC_JWKS_URL, discovered from the issuer's openid-configuration by default, and refreshed every
+// OIDC_JWKS_REFRESH_MINUTES (60 by default). OIDC_EMAIL_CLAIM and OIDC_GROUPS_CLAIM name the claims
+// holding the user's email and groups, "email" and "groups" by default.
+func OIDCVerifierFromEnv(ctx context.Context) (*OIDCVerifier, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+	audience := os.Getenv("OIDC_AUDIENCE")
+	if audience == "" {
+		return nil, errors.New("OIDC_AUDIENCE is required when OIDC_ISSUER is set")
+	}
+
+	refresh := 60
+	if value := os.Getenv("OIDC_JWKS_REFRESH_MINUTES"); value != "" {
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 1 {
+			return nil, fmt.Errorf("invalid OIDC_JWKS_REFRESH_MINUTES %q", value)
+		}
+		refresh = minutes
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second, Transport: tracing.Transport(nil)}
+	jwksURL := os.Getenv("OIDC_JWKS_URL")
+	if jwksURL == "" {
+		var discovery struct {
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := getJSON(ctx, client, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", &discovery); err != nil {
+			return nil, fmt.Errorf("discovering OIDC configuration: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return nil, errors.New("OIDC configuration has no jwks_uri")
+		}
+		jwksURL = discovery.JWKSURI
+	}
+
+	verifier := &OIDCVerifier{
+		Issuer:      issuer,
+		Audience:    audience,
+		EmailClaim:  envOrDefault("OIDC_EMAIL_CLAIM", "email"),
+		GroupsClaim: envOrDefault("OIDC_GROUPS_CLAIM", "groups"),
+		JWKS:        &JWKS{URL: jwksURL, RefreshInterval: time.Duration(refresh) * time.Minute, Client: client},
+	}
+	if _, err := verifier.JWKS.Key(ctx, ""); err != nil && err != ErrUnknownSigningKey {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// getJSON fetches a JSON document.
+func getJSON(ctx context.Context, client *http.Client, url string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// envOrDefault returns an environment variable, or a default when it is unset or empty.
+func envOrDefault(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,381 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testIssuer = "https://idp.example.com"
+
+// testSigningKey is an RSA key published by testIdentityProvider under its key ID.
+type testSigningKey struct {
+	kid string
+	key *rsa.PrivateKey
+}
+
+func newTestSigningKey(t *testing.T, kid string) testSigningKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testSigningKey{kid: kid, key: key}
+}
+
+// sign returns a token signed with the key, with default claims overridden by the given ones.
+func (k testSigningKey) sign(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	defaults := jwt.MapClaims{
+		"iss":   testIssuer,
+		"aud":   "project-management",
+		"exp":   time.Now().Add(time.Hour).Unix(),
+		"email": "jane@example.com",
+	}
+	for name, value := range claims {
+		if value == nil {
+			delete(defaults, name)
+		} else {
+			defaults[name] = value
+		}
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, defaults)
+	token.Header["kid"] = k.kid
+	signed, err := token.SignedString(k.key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+// testIdentityProvider serves a JWKS whose keys can be rotated, counting the fetches.
+type testIdentityProvider struct {
+	*httptest.Server
+	mu      sync.Mutex
+	keys    []testSigningKey
+	fetches atomic.Int32
+	// block, when set, holds JWKS responses until it is closed.
+	block chan struct{}
+	// fetching receives a value when a JWKS request arrives.
+	fetching chan struct{}
+}
+
+func newTestIdentityProvider(t *testing.T, keys ...testSigningKey) *testIdentityProvider {
+	t.Helper()
+	idp := &testIdentityProvider{keys: keys, fetching: make(chan struct{}, 16)}
+	idp.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		idp.fetches.Add(1)
+		idp.fetching <- struct{}{}
+		idp.mu.Lock()
+		block := idp.block
+		var set struct {
+			Keys []map[string]string `json:"keys"`
+		}
+		for _, key := range idp.keys {
+			set.Keys = append(set.Keys, map[string]string{
+				"kty": "RSA",
+				"kid": key.kid,
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.key.E)).Bytes()),
+			})
+		}
+		idp.mu.Unlock()
+		if block != nil {
+			<-block
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+func (idp *testIdentityProvider) rotate(keys ...testSigningKey) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.keys = keys
+}
+
+func (idp *testIdentityProvider) verifier() *OIDCVerifier {
+	return &OIDCVerifier{
+		Issuer:      testIssuer,
+		Audience:    "project-management",
+		EmailClaim:  "email",
+		GroupsClaim: "groups",
+		JWKS:        &JWKS{URL: idp.URL, RefreshInterval: time.Hour, Client: idp.Client()},
+	}
+}
+
+// withMinRefreshInterval overrides jwksMinRefreshInterval for the duration of a test.
+func withMinRefreshInterval(t *testing.T, interval time.Duration) {
+	previous := jwksMinRefreshInterval
+	jwksMinRefreshInterval = interval
+	t.Cleanup(func() { jwksMinRefreshInterval = previous })
+}
+
+func TestJWKSRefetchesOnUnknownKeyID(t *testing.T) {
+	withMinRefreshInterval(t, 0)
+	ctx := context.Background()
+	oldKey, newKey := newTestSigningKey(t, "old"), newTestSigningKey(t, "new")
+	idp := newTestIdentityProvider(t, oldKey)
+	verifier := idp.verifier()
+
+	if _, err := verifier.Verify(ctx, oldKey.sign(t, nil)); err != nil {
+		t.Fatalf("verifying a token of the published key: %v", err)
+	}
+	if _, err := verifier.Verify(ctx, oldKey.sign(t, nil)); err != nil {
+		t.Fatalf("verifying a token of the cached key: %v", err)
+	}
+	if got := idp.fetches.Load(); got != 1 {
+		t.Fatalf("fetches after two tokens of a known key = %d, want 1", got)
+	}
+
+	idp.rotate(newKey)
+	if _, err := verifier.Verify(ctx, newKey.sign(t, nil)); err != nil {
+		t.Fatalf("verifying a token of the rotated key: %v", err)
+	}
+	if got := idp.fetches.Load(); got != 2 {
+		t.Fatalf("fetches after a token of an unknown key = %d, want 2", got)
+	}
+
+	_, err := verifier.Verify(ctx, oldKey.sign(t, nil))
+	if !errors.Is(err, ErrUnknownSigningKey) {
+		t.Fatalf("verifying a token of the retired key: got %v, want %v", err, ErrUnknownSigningKey)
+	}
+}
+
+func TestJWKSLimitsRefetches(t *testing.T) {
+	withMinRefreshInterval(t, time.Hour)
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	jwks := idp.verifier().JWKS
+
+	if _, err := jwks.Key(ctx, "current"); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := jwks.Key(ctx, "made-up"); !errors.Is(err, ErrUnknownSigningKey) {
+			t.Fatalf("looking up an unknown key: got %v, want %v", err, ErrUnknownSigningKey)
+		}
+	}
+	if got := idp.fetches.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+}
+
+func TestJWKSKeepsKeysWhenRefreshFails(t *testing.T) {
+	withMinRefreshInterval(t, 0)
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	jwks := idp.verifier().JWKS
+
+	if _, err := jwks.Key(ctx, "current"); err != nil {
+		t.Fatal(err)
+	}
+	idp.Close()
+	jwks.RefreshInterval = 0
+	if _, err := jwks.Key(ctx, "current"); err != nil {
+		t.Fatalf("looking up a cached key while the identity provider is down: %v", err)
+	}
+}
+
+func TestJWKSLookupsDoNotWaitForFetch(t *testing.T) {
+	withMinRefreshInterval(t, time.Hour)
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	jwks := idp.verifier().JWKS
+
+	if _, err := jwks.Key(ctx, "current"); err != nil {
+		t.Fatal(err)
+	}
+	<-idp.fetching
+
+	// Make the cache stale and hold the next fetch.
+	idp.mu.Lock()
+	idp.block = make(chan struct{})
+	idp.mu.Unlock()
+	release := sync.OnceFunc(func() { close(idp.block) })
+	t.Cleanup(release)
+	jwks.mu.Lock()
+	jwks.fetchedAt = time.Time{}
+	jwks.attemptedAt = time.Time{}
+	jwks.mu.Unlock()
+
+	fetched := make(chan error)
+	go func() {
+		_, err := jwks.Key(ctx, "current")
+		fetched <- err
+	}()
+	<-idp.fetching
+
+	looked := make(chan error)
+	go func() {
+		_, err := jwks.Key(ctx, "current")
+		looked <- err
+	}()
+	select {
+	case err := <-looked:
+		if err != nil {
+			t.Fatalf("looking up a cached key during a fetch: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("looking up a cached key waited for the fetch")
+	}
+
+	release()
+	if err := <-fetched; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSSharesConcurrentFetches(t *testing.T) {
+	withMinRefreshInterval(t, 0)
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	idp.block = make(chan struct{})
+	jwks := idp.verifier().JWKS
+
+	const lookups = 8
+	errs := make(chan error, lookups)
+	go func() {
+		_, err := jwks.Key(ctx, "current")
+		errs <- err
+	}()
+	<-idp.fetching
+	for i := 1; i < lookups; i++ {
+		go func() {
+			_, err := jwks.Key(ctx, "current")
+			errs <- err
+		}()
+	}
+	// Give the other lookups time to join the fetch in progress.
+	time.Sleep(100 * time.Millisecond)
+	close(idp.block)
+
+	for i := 0; i < lookups; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := idp.fetches.Load(); got != 1 {
+		t.Fatalf("fetches = %d, want 1", got)
+	}
+}
+
+func TestOIDCVerifyRejectsInvalidTokens(t *testing.T) {
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	verifier := idp.verifier()
+
+	hmacToken := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"iss": testIssuer, "aud": "project-management", "exp": time.Now().Add(time.Hour).Unix(), "email": "jane@example.com",
+	})
+	hmacToken.Header["kid"] = "current"
+	hmacSigned, err := hmacToken.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{"bad issuer", key.sign(t, jwt.MapClaims{"iss": "https://evil.example.com"})},
+		{"bad audience", key.sign(t, jwt.MapClaims{"aud": "another-service"})},
+		{"no audience", key.sign(t, jwt.MapClaims{"aud": nil})},
+		{"expired", key.sign(t, jwt.MapClaims{"exp": time.Now().Add(-time.Hour).Unix()})},
+		{"no expiry", key.sign(t, jwt.MapClaims{"exp": nil})},
+		{"not yet valid", key.sign(t, jwt.MapClaims{"nbf": time.Now().Add(time.Hour).Unix()})},
+		{"no email", key.sign(t, jwt.MapClaims{"email": nil})},
+		{"unverified email", key.sign(t, jwt.MapClaims{"email_verified": false})},
+		{"signed by another key", newTestSigningKey(t, "current").sign(t, nil)},
+		{"symmetric algorithm", hmacSigned},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if identity, err := verifier.Verify(ctx, test.token); err == nil {
+				t.Fatalf("token accepted as %+v", identity)
+			}
+		})
+	}
+}
+
+func TestOIDCVerifyRequiresAudience(t *testing.T) {
+	key := newTestSigningKey(t, "current")
+	verifier := newTestIdentityProvider(t, key).verifier()
+	verifier.Audience = ""
+	if identity, err := verifier.Verify(context.Background(), key.sign(t, nil)); err == nil {
+		t.Fatalf("token accepted without an audience as %+v", identity)
+	}
+
+	t.Setenv("OIDC_ISSUER", testIssuer)
+	t.Setenv("OIDC_AUDIENCE", "")
+	if _, err := OIDCVerifierFromEnv(context.Background()); err == nil {
+		t.Error("OIDC configured without OIDC_AUDIENCE")
+	}
+}
+
+func TestOIDCVerifyReturnsIdentity(t *testing.T) {
+	ctx := context.Background()
+	key := newTestSigningKey(t, "current")
+	idp := newTestIdentityProvider(t, key)
+	verifier := idp.verifier()
+
+	tests := []struct {
+		name   string
+		claims jwt.MapClaims
+		want   OIDCIdentity
+	}{
+		{"no groups", nil, OIDCIdentity{Email: "jane@example.com"}},
+		{"single group", jwt.MapClaims{"groups": "engineering"}, OIDCIdentity{Email: "jane@example.com", Groups: []string{"engineering"}}},
+		{
+			"group list",
+			jwt.MapClaims{"groups": []string{"engineering", "managers"}, "email_verified": true},
+			OIDCIdentity{Email: "jane@example.com", Groups: []string{"engineering", "managers"}},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			identity, err := verifier.Verify(ctx, key.sign(t, test.claims))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(identity, test.want) {
+				t.Fatalf("identity = %+v, want %+v", identity, test.want)
+			}
+		})
+	}
+}
+
+func TestOIDCVerifierHandles(t *testing.T) {
+	key := newTestSigningKey(t, "current")
+	verifier := &OIDCVerifier{Issuer: testIssuer}
+
+	if !verifier.Handles(key.sign(t, nil)) {
+		t.Error("token of the issuer is not handled")
+	}
+	if verifier.Handles(key.sign(t, jwt.MapClaims{"iss": "https://other.example.com"})) {
+		t.Error("token of another issuer is handled")
+	}
+	if verifier.Handles("not-a-jwt") {
+		t.Error("malformed token is handled")
+	}
+}
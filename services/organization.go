@@ -0,0 +1,141 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAmbiguousOrganization is returned by OnlyOrganization when the user belongs to several organizations.
+var ErrAmbiguousOrganization = errors.New("user belongs to several organizations")
+
+// organizationSlugPattern matches slugs usable as a subdomain label.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// ValidOrganizationSlug reports whether a slug can identify an organization, including as a subdomain.
+func ValidOrganizationSlug(slug string) bool {
+	return organizationSlugPattern.MatchString(slug)
+}
+
+// FindOrganization loads an organization by ID or slug.
+func FindOrganization(tx *gorm.DB, reference string) (pmv1.Organization, error) {
+	var organization pmv1.Organization
+	if id, err := uuid.Parse(reference); err == nil {
+		return organization, tx.Where("id = ?", id).First(&organization).Error
+	}
+	return organization, tx.Where("slug = ?", strings.ToLower(reference)).First(&organization).Error
+}
+
+// OrganizationRole returns the role of a user in an organization, or an empty string when the user is
+// not a member.
+func OrganizationRole(tx *gorm.DB, organizationID uuid.UUID, email string) (string, error) {
+	var member pmv1.OrganizationMember
+	err := tx.Where("organization_id = ? AND LOWER(email) = LOWER(?)", organizationID, email).First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return "", nil
+	}
+	return member.Role, err
+}
+
+// UserOrganizations returns the organizations a user belongs to, by name.
+func UserOrganizations(tx *gorm.DB, email string) ([]pmv1.Organization, error) {
+	var organizations []pmv1.Organization
+	err := tx.Where("id IN (?)", tx.Model(&pmv1.OrganizationMember{}).Select("organization_id").Where("LOWER(email) = LOWER(?)", email)).
+		Order("name ASC").
+		Find(&organizations).Error
+	return organizations, err
+}
+
+// OnlyOrganization returns the organization of a user who belongs to exactly one. It returns
+// gorm.ErrRecordNotFound when the user belongs to none.
+func OnlyOrganization(tx *gorm.DB, email string) (pmv1.Organization, error) {
+	organizations, err := UserOrganizations(tx, email)
+	if err != nil {
+		return pmv1.Organization{}, err
+	}
+	switch len(organizations) {
+	case 0:
+		return pmv1.Organization{}, gorm.ErrRecordNotFound
+	case 1:
+		return organizations[0], nil
+	default:
+		return pmv1.Organization{}, ErrAmbiguousOrganization
+	}
+}
+
+// JoinOrganization makes a user a member of an organization. Users who already belong to it keep their role.
+func JoinOrganization(tx *gorm.DB, organizationID uuid.UUID, email, addedBy string) error {
+	role, err := OrganizationRole(tx, organizationID, email)
+	if err != nil || role != "" {
+		return err
+	}
+	return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&pmv1.OrganizationMember{
+		OrganizationID: organizationID,
+		Email:          email,
+		Role:           pmv1.OrganizationRoleMember,
+		AddedBy:        addedBy,
+	}).Error
+}
+
+// AssignClient records the organization a client belongs to.
+func AssignClient(tx *gorm.DB, organizationID, clientID uuid.UUID) error {
+	return tx.Create(&pmv1.OrganizationClient{ClientID: clientID, OrganizationID: organizationID}).Error
+}
+
+// AssignProject records the organization a project belongs to.
+func AssignProject(tx *gorm.DB, organizationID, projectID uuid.UUID) error {
+	return tx.Create(&pmv1.OrganizationProject{ProjectID: projectID, OrganizationID: organizationID}).Error
+}
+
+// OrganizationClients returns a subquery selecting the IDs of the clients of an organization.
+func OrganizationClients(tx *gorm.DB, organizationID uuid.UUID) *gorm.DB {
+	return tx.Model(&pmv1.OrganizationClient{}).Select("client_id").Where("organization_id = ?", organizationID)
+}
+
+// OrganizationProjects returns a subquery selecting the IDs of the projects of an organization.
+func OrganizationProjects(tx *gorm.DB, organizationID uuid.UUID) *gorm.DB {
+	return tx.Model(&pmv1.OrganizationProject{}).Select("project_id").Where("organization_id = ?", organizationID)
+}
+
+// ProjectOrganizationID returns the organization a project belongs to, or uuid.Nil when it is not
+// assigned to any.
+func ProjectOrganizationID(tx *gorm.DB, projectID uuid.UUID) (uuid.UUID, error) {
+	var assignment pmv1.OrganizationProject
+	err := tx.Where("project_id = ?", projectID).First(&assignment).Error
+	if err == gorm.ErrRecordNotFound {
+		return uuid.Nil, nil
+	}
+	return assignment.OrganizationID, err
+}
+
+// ClientInOrganization reports whether a client belongs to an organization.
+func ClientInOrganization(tx *gorm.DB, organizationID, clientID uuid.UUID) (bool, error) {
+	var count int64
+	err := tx.Model(&pmv1.OrganizationClient{}).Where("organization_id = ? AND client_id = ?", organizationID, clientID).Count(&count).Error
+	return count > 0, err
+}
+
+// LeaveOrganization removes a user from an organization and from its teams.
+func LeaveOrganization(tx *gorm.DB, organizationID uuid.UUID, email string) (int64, error) {
+	if err := tx.Where("LOWER(email) = LOWER(?) AND team_id IN (?)", email,
+		tx.Model(&pmv1.Team{}).Select("id").Where("organization_id = ?", organizationID)).
+		Delete(&pmv1.TeamMember{}).Error; err != nil {
+		return 0, err
+	}
+	result := tx.Where("organization_id = ? AND LOWER(email) = LOWER(?)", organizationID, email).Delete(&pmv1.OrganizationMember{})
+	return result.RowsAffected, result.Error
+}
+
+// CountOrganizationAdmins returns the number of admins of an organization.
+func CountOrganizationAdmins(tx *gorm.DB, organizationID uuid.UUID) (int64, error) {
+	var count int64
+	err := tx.Model(&pmv1.OrganizationMember{}).
+		Where("organization_id = ? AND role = ?", organizationID, pmv1.OrganizationRoleAdmin).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"strings"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LastOwnerError is returned when a change would leave a project without an Owner.
+type LastOwnerError struct{}
+
+// Error implements the error interface.
+func (e *LastOwnerError) Error() string {
+	return "A project must keep at least one Owner."
+}
+
+// ProjectRole returns the role of a user in a project from its membership, or an empty string when
+// the user is not a member.
+func ProjectRole(tx *gorm.DB, projectID uuid.UUID, email string) (string, error) {
+	var member v1.ProjectMember
+	if err := tx.Where("project_id = ? AND LOWER(email) = LOWER(?)", projectID, email).First(&member).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	return member.Role, nil
+}
+
+// SetProjectRole gives a user a role in a project, adding them as a member when needed.
+func SetProjectRole(tx *gorm.DB, projectID uuid.UUID, email, role string) error {
+	var member v1.ProjectMember
+	err := tx.Where("project_id = ? AND LOWER(email) = LOWER(?)", projectID, email).First(&member).Error
+	if err == gorm.ErrRecordNotFound {
+		return tx.Create(&v1.ProjectMember{ProjectID: projectID, Email: email, Role: role}).Error
+	}
+	if err != nil {
+		return err
+	}
+	if member.Role == role {
+		return nil
+	}
+	return tx.Model(&member).Update("role", role).Error
+}
+
+// ProjectOwners returns the emails of the Owners of a project, locking their memberships so that
+// concurrent ownership changes are serialized.
+func ProjectOwners(tx *gorm.DB, projectID uuid.UUID) ([]string, error) {
+	var owners []string
+	err := tx.Model(&v1.ProjectMember{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("project_id = ? AND role = ?", projectID, pmv1.RoleOwner).
+		Order("email ASC").
+		Pluck("email", &owners).Error
+	return owners, err
+}
+
+// GuardLastOwner returns a *LastOwnerError when removing or demoting the leaving users would leave
+// the project without an Owner.
+func GuardLastOwner(tx *gorm.DB, projectID uuid.UUID, leaving ...string) error {
+	owners, err := ProjectOwners(tx, projectID)
+	if err != nil {
+		return err
+	}
+
+	if len(owners) == 0 {
+		return nil
+	}
+
+	left := make(map[string]bool, len(leaving))
+	for _, email := range leaving {
+		left[strings.ToLower(email)] = true
+	}
+	for _, owner := range owners {
+		if !left[strings.ToLower(owner)] {
+			return nil
+		}
+	}
+	return &LastOwnerError{}
+}
+
+// TransferOwnership makes to an Owner of a project and demotes, or removes, from.
+func TransferOwnership(tx *gorm.DB, projectID uuid.UUID, from, to, previousRole string, removePrevious bool) error {
+	if err := SetProjectRole(tx, projectID, to, pmv1.RoleOwner); err != nil {
+		return err
+	}
+	if strings.EqualFold(from, to) {
+		return nil
+	}
+	if err := GuardLastOwner(tx, projectID, from); err != nil {
+		return err
+	}
+	if removePrevious {
+		return tx.Where("project_id = ? AND LOWER(email) = LOWER(?)", projectID, from).Delete(&v1.ProjectMember{}).Error
+	}
+	if previousRole == "" {
+		previousRole = pmv1.RoleManager
+	}
+	return SetProjectRole(tx, projectID, from, previousRole)
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// UnknownPermissionError is returned when a custom role refers to a permission that does not exist.
+type UnknownPermissionError struct {
+	Permission string
+}
+
+// Error implements the error interface.
+func (e *UnknownPermissionError) Error() string {
+	return fmt.Sprintf("Unknown permission %q.", e.Permission)
+}
+
+// IsBuiltInRole reports whether a role name is one of the built-in roles, ignoring case.
+func IsBuiltInRole(name string) bool {
+	for role := range pmv1.BuiltInRolePermissions {
+		if strings.EqualFold(role, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizePermissions validates a list of permissions and returns it without duplicates, in the
+// documented order.
+func NormalizePermissions(permissions []string) ([]string, error) {
+	requested := make(map[string]bool, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.ToLower(strings.TrimSpace(permission))
+		if !isPermission(permission) {
+			return nil, &UnknownPermissionError{Permission: permission}
+		}
+		requested[permission] = true
+	}
+
+	normalized := make([]string, 0, len(requested))
+	for _, permission := range pmv1.Permissions {
+		if requested[permission] {
+			normalized = append(normalized, permission)
+		}
+	}
+	return normalized, nil
+}
+
+// RolePermissions returns the permissions granted by a built-in role or a custom role of an
+// organization. The boolean is false when no such role exists.
+func RolePermissions(tx *gorm.DB, organizationID uuid.UUID, role string) ([]string, bool, error) {
+	if permissions, ok := pmv1.BuiltInRolePermissions[role]; ok {
+		return permissions, true, nil
+	}
+
+	var custom pmv1.CustomRole
+	if err := tx.Where("organization_id = ? AND name = ?", organizationID, role).First(&custom).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return []string(custom.Permissions), true, nil
+}
+
+// ProjectPermissions returns the effective role of a user in a project and the permissions they hold.
+// The permissions combine the user's direct role with the roles of their teams in the project, and the
+// effective role is the one granting the most of them, preferring the direct role. Both are empty when
+// the user is neither a member nor in a team of the project.
+func ProjectPermissions(tx *gorm.DB, projectID uuid.UUID, email string) (string, []string, error) {
+	organizationID, err := ProjectOrganizationID(tx, projectID)
+	if err != nil {
+		return "", nil, err
+	}
+	direct, err := ProjectRole(tx, projectID, email)
+	if err != nil {
+		return "", nil, err
+	}
+	teamRoles, err := TeamProjectRoles(tx, projectID, email)
+	if err != nil {
+		return "", nil, err
+	}
+
+	roles := teamRoles
+	if direct != "" {
+		roles = append([]string{direct}, teamRoles...)
+	}
+
+	var effective string
+	var granted []string
+	best := -1
+	for _, role := range roles {
+		permissions, _, err := RolePermissions(tx, organizationID, role)
+		if err != nil {
+			return "", nil, err
+		}
+		if len(permissions) > best {
+			effective, best = role, len(permissions)
+		}
+		granted = append(granted, permissions...)
+	}
+	if effective == "" {
+		return "", nil, nil
+	}
+
+	permissions, err := NormalizePermissions(granted)
+	if err != nil {
+		return "", nil, err
+	}
+	return effective, permissions, nil
+}
+
+// HasPermission reports whether a user holds a permission in a project.
+func HasPermission(tx *gorm.DB, projectID uuid.UUID, email, permission string) (bool, error) {
+	_, permissions, err := ProjectPermissions(tx, projectID, email)
+	if err != nil {
+		return false, err
+	}
+	return Grants(permissions, permission), nil
+}
+
+// Grants reports whether a list of permissions contains permission.
+func Grants(permissions []string, permission string) bool {
+	for _, granted := range permissions {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// RolesWithPermission returns the names of the built-in roles and the custom roles of an organization
+// granting a permission.
+func RolesWithPermission(tx *gorm.DB, organizationID uuid.UUID, permission string) ([]string, error) {
+	var roles []string
+	for role, permissions := range pmv1.BuiltInRolePermissions {
+		if Grants(permissions, permission) {
+			roles = append(roles, role)
+		}
+	}
+
+	var custom []string
+	if err := tx.Model(&pmv1.CustomRole{}).Where("organization_id = ? AND ? = ANY(permissions)", organizationID, permission).Pluck("name", &custom).Error; err != nil {
+		return nil, err
+	}
+	return append(roles, custom...), nil
+}
+
+// isPermission reports whether a permission exists.
+func isPermission(permission string) bool {
+	for _, known := range pmv1.Permissions {
+		if known == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"testing"
+
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestBuiltInRolesReadTheIssuesTheyActOn(t *testing.T) {
+	for role, permissions := range pmv1.BuiltInRolePermissions {
+		for _, permission := range []string{pmv1.PermissionIssueCreate, pmv1.PermissionIssueEdit, pmv1.PermissionIssueDelete, pmv1.PermissionIssueComment} {
+			if Grants(permissions, permission) && !Grants(permissions, pmv1.PermissionIssueView) {
+				t.Errorf("%s grants %s without %s", role, permission, pmv1.PermissionIssueView)
+			}
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/san-data-systems/common/clients/minio"
+	"github.com/san-data-systems/common/logger"
+	"github.com/san-data-systems/project-management-api/metrics"
+	"github.com/san-data-systems/project-management-api/tracing"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// UploadObject uploads a file to object storage and records the transfer.
+func UploadObject(ctx context.Context, client *minio.MinIOClient, bucket, name string, data []byte) error {
+	ctx, span := startStorageSpan(ctx, "minio.upload", bucket, name, int64(len(data)))
+	defer span.End()
+
+	start := time.Now()
+	err := client.UploadFile(ctx, bucket, name, data)
+	metrics.ObserveStorage(metrics.StorageUpload, int64(len(data)), time.Since(start), err)
+	endStorageSpan(span, err)
+	return err
+}
+
+// DownloadURL returns a presigned URL to download a file of the given size from object storage and
+// records it as a download, since the file itself is then fetched from storage directly.
+func DownloadURL(ctx context.Context, client *minio.MinIOClient, bucket, name string, size int64, expiry time.Duration) (string, error) {
+	ctx, span := startStorageSpan(ctx, "minio.presign_download", bucket, name, size)
+	defer span.End()
+
+	start := time.Now()
+	url, err := client.GetPresignedURL(ctx, bucket, name, expiry)
+	metrics.ObserveStorage(metrics.StorageDownload, size, time.Since(start), err)
+	endStorageSpan(span, err)
+	return url, err
+}
+
+// ObjectURL returns a presigned URL to a file of the given size in object storage, such as the one
+// returned for a file just uploaded. Unlike DownloadURL it does not record a download.
+func ObjectURL(ctx context.Context, client *minio.MinIOClient, bucket, name string, size int64, expiry time.Duration) (string, error) {
+	ctx, span := startStorageSpan(ctx, "minio.presign", bucket, name, size)
+	defer span.End()
+
+	url, err := client.GetPresignedURL(ctx, bucket, name, expiry)
+	endStorageSpan(span, err)
+	return url, err
+}
+
+// RemoveObject deletes a file from object storage.
+func RemoveObject(ctx context.Context, client *minio.MinIOClient, bucket, name string) error {
+	ctx, span := startStorageSpan(ctx, "minio.remove", bucket, name, 0)
+	defer span.End()
+
+	err := client.DeleteFile(ctx, bucket, name)
+	endStorageSpan(span, err)
+	return err
+}
+
+// StoredObject is a file in object storage: the bucket of its project and its name.
+type StoredObject struct {
+	Bucket string
+	Name   string
+}
+
+// RemoveStoredObjects removes the files of purged rows from object storage. Files that cannot be removed
+// are logged and left behind, since the rows referring to them are already gone.
+func RemoveStoredObjects(ctx context.Context, objects []StoredObject) {
+	if len(objects) == 0 {
+		return
+	}
+	client, err := minio.GetMinIOClient()
+	if err != nil {
+		logger.LogError("Failed to get MinIO client to remove purged files.", logrus.Fields{"error": err.Error(), "files": len(objects)})
+		return
+	}
+	for _, object := range objects {
+		if err := RemoveObject(ctx, client, object.Bucket, object.Name); err != nil {
+			logger.LogError("Failed to remove purged file from storage.", logrus.Fields{"bucket": object.Bucket, "name": object.Name, "error": err.Error()})
+		}
+	}
+}
+
+// startStorageSpan starts the span of an object storage call.
+func startStorageSpan(ctx context.Context, name, bucket, object string, size int64) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("storage.bucket", bucket),
+			attribute.String("storage.object", object),
+			attribute.Int64("storage.size", size),
+		),
+	)
+}
+
+// endStorageSpan records the error of a failed object storage call on its span.
+func endStorageSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
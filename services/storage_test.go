@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/san-data-systems/project-management-api/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// recordSpans installs a tracer provider recording every span in memory for the duration of a test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		_ = provider.Shutdown(context.Background())
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+// dryRunPool is a connection pool that runs nothing, for databases in dry run mode. It supports
+// transactions, so that queries can be run on one as controllers do.
+type dryRunPool struct{}
+
+func (dryRunPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunPool) ExecContext(context.Context, string, ...interface{}) (sql.Result, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunPool) QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error) {
+	return nil, errors.New("dry run")
+}
+
+func (dryRunPool) QueryRowContext(context.Context, string, ...interface{}) *sql.Row {
+	return nil
+}
+
+func (pool dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &dryRunTx{pool}, nil
+}
+
+type dryRunTx struct{ dryRunPool }
+
+func (*dryRunTx) Commit() error   { return nil }
+func (*dryRunTx) Rollback() error { return nil }
+
+// dryRunDB returns a traced database generating SQL without running it.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: dryRunPool{}}), &gorm.Config{
+		DryRun: true,
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Use(tracing.GORMPlugin{}); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// spanNamed returns the ended span with the given name.
+func spanNamed(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	t.Helper()
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	var names []string
+	for _, span := range spans {
+		names = append(names, span.Name())
+	}
+	t.Fatalf("no span named %q in %v", name, names)
+	return nil
+}
+
+// attributeValue returns the value of an attribute of a span.
+func attributeValue(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestRequestSpans(t *testing.T) {
+	recorder := recordSpans(t)
+	db := dryRunDB(t)
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.ContextWithFallback = true
+	r.Use(tracing.Middleware())
+	r.GET("/projects/:project_id/files", func(c *gin.Context) {
+		// A query on a transaction bound to the request, as controllers run them.
+		tx := db.Begin().WithContext(c.Request.Context())
+		var holidays []pmv1.Holiday
+		tx.Where("organization_id = ?", c.Param("project_id")).Find(&holidays)
+		tx.Rollback()
+
+		// A storage call given the gin context, as controllers make them.
+		_, span := startStorageSpan(c, "minio.presign", "bucket", "files/report.pdf", 42)
+		endStorageSpan(span, nil)
+		span.End()
+
+		c.Status(http.StatusOK)
+	})
+
+	caller := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x0a, 0xf7, 0x65, 0x19, 0x16, 0xcd, 0x43, 0xdd, 0x84, 0x48, 0xeb, 0x21, 0x1c, 0x80, 0x31, 0x9c},
+		SpanID:     trace.SpanID{0xb7, 0xad, 0x6b, 0x71, 0x69, 0x20, 0x33, 0x1c},
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	})
+	req := httptest.NewRequest(http.MethodGet, "/projects/7b0c3e76-0d36-4d5b-9d8b-5c8f8e2f8f5e/files", nil)
+	req.Header.Set("traceparent", "00-"+caller.TraceID().String()+"-"+caller.SpanID().String()+"-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	request := spanNamed(t, spans, "/projects/:project_id/files")
+	query := spanNamed(t, spans, "gorm.query holidays")
+	storage := spanNamed(t, spans, "minio.presign")
+
+	if request.Parent().SpanID() != caller.SpanID() || request.SpanContext().TraceID() != caller.TraceID() {
+		t.Errorf("request span does not continue the caller's trace: parent %s", request.Parent().SpanID())
+	}
+	if request.SpanKind() != trace.SpanKindServer {
+		t.Errorf("request span kind = %s, want server", request.SpanKind())
+	}
+	for _, child := range []sdktrace.ReadOnlySpan{query, storage} {
+		if child.Parent().SpanID() != request.SpanContext().SpanID() || child.SpanContext().TraceID() != caller.TraceID() {
+			t.Errorf("%s span is not a child of the request span", child.Name())
+		}
+		if child.SpanKind() != trace.SpanKindClient {
+			t.Errorf("%s span kind = %s, want client", child.Name(), child.SpanKind())
+		}
+	}
+
+	if got := attributeValue(query, "db.system").AsString(); got != "postgresql" {
+		t.Errorf("db.system = %q, want postgresql", got)
+	}
+	if got := attributeValue(query, "db.query.text").AsString(); got == "" {
+		t.Error("query span has no db.query.text")
+	}
+	if got := attributeValue(storage, "storage.object").AsString(); got != "files/report.pdf" {
+		t.Errorf("storage.object = %q, want files/report.pdf", got)
+	}
+}
+
+func TestQuerySpanWithoutRequest(t *testing.T) {
+	recorder := recordSpans(t)
+	db := dryRunDB(t)
+
+	var holidays []pmv1.Holiday
+	db.Find(&holidays)
+
+	query := spanNamed(t, recorder.Ended(), "gorm.query holidays")
+	if query.Parent().IsValid() {
+		t.Error("query run outside a request has a parent span")
+	}
+}
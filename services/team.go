@@ -0,0 +1,67 @@
+package services
+
+import (
+	"github.com/google/uuid"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// memberProjectsSQL selects the projects a user belongs to directly or through a team, restricted to the
+// roles listed when the restriction is enabled.
+const memberProjectsSQL = `SELECT project_id FROM project_members
+	WHERE LOWER(email) = LOWER(@email) AND (@all OR role IN @roles)
+	UNION
+	SELECT project_teams.project_id FROM project_teams
+	JOIN team_members ON team_members.team_id = project_teams.team_id
+	WHERE LOWER(team_members.email) = LOWER(@email) AND (@all OR project_teams.role IN @roles)`
+
+// TeamProjectRoles returns the roles a user holds in a project through their teams.
+func TeamProjectRoles(tx *gorm.DB, projectID uuid.UUID, email string) ([]string, error) {
+	var roles []string
+	err := tx.Model(&pmv1.ProjectTeam{}).
+		Joins("JOIN team_members ON team_members.team_id = project_teams.team_id").
+		Where("project_teams.project_id = ? AND LOWER(team_members.email) = LOWER(?)", projectID, email).
+		Distinct().
+		Pluck("project_teams.role", &roles).Error
+	return roles, err
+}
+
+// MemberProjects returns a subquery selecting the projects a user belongs to, directly or through a team.
+func MemberProjects(tx *gorm.DB, email string) *gorm.DB {
+	return tx.Raw(memberProjectsSQL, map[string]interface{}{"email": email, "all": true, "roles": []string{""}})
+}
+
+// ProjectsWithPermission returns a subquery selecting the projects of an organization in which a user
+// holds a permission, directly or through a team.
+func ProjectsWithPermission(tx *gorm.DB, organizationID uuid.UUID, email, permission string) (*gorm.DB, error) {
+	roles, err := RolesWithPermission(tx, organizationID, permission)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		roles = []string{""}
+	}
+	return OrganizationProjects(tx, organizationID).
+		Where("project_id IN (?)", tx.Raw(memberProjectsSQL, map[string]interface{}{"email": email, "all": false, "roles": roles})), nil
+}
+
+// TeamMemberEmails returns the emails of the members of a team.
+func TeamMemberEmails(tx *gorm.DB, teamID uuid.UUID) ([]string, error) {
+	var emails []string
+	err := tx.Model(&pmv1.TeamMember{}).Where("team_id = ?", teamID).Order("email ASC").Pluck("email", &emails).Error
+	return emails, err
+}
+
+// DeleteTeam deletes a team with its memberships and group mappings and removes it from every project.
+func DeleteTeam(tx *gorm.DB, team pmv1.Team) error {
+	if err := tx.Where("team_id = ?", team.ID).Delete(&pmv1.ProjectTeam{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("team_id = ?", team.ID).Delete(&pmv1.TeamMember{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("team_id = ?", team.ID).Delete(&pmv1.GroupMapping{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&team).Error
+}
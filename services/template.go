@@ -0,0 +1,319 @@
+package services
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// CaptureProjectDefinition captures the states, labels, members and saved views of a project,
+// and optionally its open issues, as a template definition.
+func CaptureProjectDefinition(tx *gorm.DB, projectID uuid.UUID, includeOpenIssues bool) (pmv1.TemplateDefinition, error) {
+	definition := pmv1.TemplateDefinition{
+		States:  []pmv1.TemplateState{},
+		Labels:  []pmv1.TemplateLabel{},
+		Members: []pmv1.TemplateMember{},
+		Views:   []pmv1.TemplateView{},
+		Issues:  []pmv1.TemplateIssue{},
+	}
+
+	var project v1.Project
+	if err := tx.Where("id = ?", projectID).First(&project).Error; err != nil {
+		return definition, err
+	}
+
+	columns, err := LoadStateColumns(tx, projectID)
+	if err != nil {
+		return definition, err
+	}
+	stateNames := make(map[string]string, len(columns))
+	for _, column := range columns {
+		definition.States = append(definition.States, pmv1.TemplateState{
+			Name:     column.Name,
+			Sequence: column.Sequence,
+			Category: column.Category,
+			WIPLimit: column.WIPLimit,
+			WIPMode:  column.WIPMode,
+		})
+		stateNames[column.StateID] = column.Name
+	}
+
+	var labels []v1.ProjectLabel
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL", projectID).Order("created_at ASC").Find(&labels).Error; err != nil {
+		return definition, err
+	}
+	labelNames := make(map[string]string, len(labels))
+	for _, label := range labels {
+		definition.Labels = append(definition.Labels, pmv1.TemplateLabel{Name: label.Name, Color: label.Color})
+		labelNames[label.ID.String()] = label.Name
+	}
+
+	var members []v1.ProjectMember
+	if err := tx.Where("project_id = ?", projectID).Order("created_at ASC").Find(&members).Error; err != nil {
+		return definition, err
+	}
+	for _, member := range members {
+		definition.Members = append(definition.Members, pmv1.TemplateMember{Email: member.Email, Role: member.Role})
+	}
+
+	var views []pmv1.SavedView
+	if err := tx.Where("project_id = ?", projectID).Order("created_at ASC").Find(&views).Error; err != nil {
+		return definition, err
+	}
+	for _, view := range views {
+		definition.Views = append(definition.Views, pmv1.TemplateView{Name: view.Name, Filters: json.RawMessage(view.Filters)})
+	}
+
+	if !includeOpenIssues {
+		return definition, nil
+	}
+
+	var issues []v1.Issue
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL AND completed_at IS NULL", projectID).
+		Order("sequence_id ASC").
+		Find(&issues).Error; err != nil {
+		return definition, err
+	}
+	keys := make(map[uuid.UUID]int32, len(issues))
+	for _, issue := range issues {
+		keys[issue.ID] = issue.SequenceID
+	}
+	projectStart := TruncateDay(project.StartDate)
+	calendar, err := loadProjectWorkingCalendar(tx, project.ID, projectStart)
+	if err != nil {
+		return definition, err
+	}
+	for _, issue := range issues {
+		seed := pmv1.TemplateIssue{
+			Key:             issue.SequenceID,
+			ParentKey:       keys[issue.ParentID],
+			Title:           issue.Title,
+			Description:     issue.Description,
+			Priority:        issue.Priority,
+			Point:           issue.Point,
+			EstimatedHours:  issue.EstimatedHours,
+			State:           stateNames[issue.StateID.String()],
+			Labels:          []string{},
+			StartOffsetDays: workingDaysBetween(calendar, projectStart, TruncateDay(issue.StartDate)),
+			DurationDays:    workingDaysBetween(calendar, TruncateDay(issue.StartDate), TruncateDay(issue.EndDate)),
+		}
+		for _, labelID := range issue.LabelIDs {
+			if name, ok := labelNames[labelID]; ok {
+				seed.Labels = append(seed.Labels, name)
+			}
+		}
+		definition.Issues = append(definition.Issues, seed)
+	}
+
+	return definition, nil
+}
+
+// ApplyProjectDefinition creates the states, labels, members, saved views and seed issues of a definition
+// in a newly created project.
+func ApplyProjectDefinition(tx *gorm.DB, project v1.Project, definition pmv1.TemplateDefinition, email string) error {
+	stateIDs := make(map[string]uuid.UUID, len(definition.States))
+	states := append([]pmv1.TemplateState{}, definition.States...)
+	sort.SliceStable(states, func(i, j int) bool { return states[i].Sequence < states[j].Sequence })
+	var firstStateID uuid.UUID
+	for i, templateState := range states {
+		state := v1.ProjectState{
+			Name:      templateState.Name,
+			ProjectID: project.ID,
+			Sequence:  int32(i + 1),
+			CreatedBy: email,
+		}
+		if err := tx.Create(&state).Error; err != nil {
+			return err
+		}
+		if i == 0 {
+			firstStateID = state.ID
+		}
+		stateIDs[strings.ToLower(templateState.Name)] = state.ID
+
+		if templateState.Category != "" {
+			if err := tx.Create(&pmv1.ProjectStateCategory{
+				StateID:   state.ID,
+				ProjectID: project.ID,
+				Category:  templateState.Category,
+				UpdatedBy: email,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if templateState.WIPLimit != nil {
+			mode := templateState.WIPMode
+			if mode == "" {
+				mode = pmv1.WIPModeSoft
+			}
+			if err := tx.Create(&pmv1.StateWIPLimit{
+				StateID:   state.ID,
+				ProjectID: project.ID,
+				Limit:     *templateState.WIPLimit,
+				Mode:      mode,
+				UpdatedBy: email,
+			}).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	labelIDs := make(map[string]string, len(definition.Labels))
+	for _, templateLabel := range definition.Labels {
+		label := v1.ProjectLabel{
+			Name:      templateLabel.Name,
+			Color:     templateLabel.Color,
+			ProjectID: project.ID,
+			CreatedBy: email,
+		}
+		if err := tx.Create(&label).Error; err != nil {
+			return err
+		}
+		labelIDs[strings.ToLower(templateLabel.Name)] = label.ID.String()
+	}
+
+	seen := map[string]bool{strings.ToLower(project.CreatedBy): true}
+	for _, templateMember := range definition.Members {
+		key := strings.ToLower(templateMember.Email)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if err := tx.Create(&v1.ProjectMember{
+			ProjectID: project.ID,
+			Email:     templateMember.Email,
+			Role:      templateMember.Role,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	for _, templateView := range definition.Views {
+		filters := string(templateView.Filters)
+		if filters == "" {
+			filters = "{}"
+		}
+		if err := tx.Create(&pmv1.SavedView{
+			ProjectID: project.ID,
+			Name:      templateView.Name,
+			Filters:   filters,
+			CreatedBy: email,
+		}).Error; err != nil {
+			return err
+		}
+	}
+
+	if len(definition.Issues) == 0 {
+		return nil
+	}
+
+	categories, err := LoadStateCategories(tx, project.ID)
+	if err != nil {
+		return err
+	}
+
+	projectStart := TruncateDay(project.StartDate)
+	calendar, err := loadProjectWorkingCalendar(tx, project.ID, projectStart)
+	if err != nil {
+		return err
+	}
+	issueIDs := make(map[int32]uuid.UUID, len(definition.Issues))
+	created := make([]v1.Issue, 0, len(definition.Issues))
+	for i, seed := range definition.Issues {
+		stateID, ok := stateIDs[strings.ToLower(seed.State)]
+		if !ok {
+			stateID = firstStateID
+		}
+
+		labels := pq.StringArray{}
+		for _, name := range seed.Labels {
+			if labelID, ok := labelIDs[strings.ToLower(name)]; ok {
+				labels = append(labels, labelID)
+			}
+		}
+
+		// Seed issues are scheduled on working days: the start is the working day after the offset, and
+		// the end as many working days later as the duration
+		startDate := calendar.AddWorkingDays(projectStart, seed.StartOffsetDays+1)
+		endDate := startDate
+		if seed.DurationDays > 0 {
+			endDate = calendar.AddWorkingDays(startDate, seed.DurationDays+1)
+		}
+		issue := v1.Issue{
+			Title:          seed.Title,
+			Description:    seed.Description,
+			ProjectID:      project.ID,
+			CreatedBy:      email,
+			UpdatedBy:      email,
+			Priority:       seed.Priority,
+			StartDate:      startDate,
+			EndDate:        endDate,
+			Point:          seed.Point,
+			LabelIDs:       labels,
+			StateID:        stateID,
+			SequenceID:     int32(i + 1),
+			EstimatedHours: seed.EstimatedHours,
+		}
+		category, ok := categories[stateID]
+		if !ok {
+			category = pmv1.DefaultStateCategory
+		}
+		ApplyStateCategory(&issue, category, time.Now())
+
+		if err := tx.Create(&issue).Error; err != nil {
+			return err
+		}
+		if seed.Key != 0 {
+			issueIDs[seed.Key] = issue.ID
+		}
+		created = append(created, issue)
+	}
+
+	for i, seed := range definition.Issues {
+		parentID, ok := issueIDs[seed.ParentKey]
+		if seed.ParentKey == 0 || !ok {
+			continue
+		}
+		if err := tx.Model(&v1.Issue{}).Where("id = ?", created[i].ID).Update("parent_id", parentID).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DecodeTemplateDefinition parses the stored definition of a template.
+func DecodeTemplateDefinition(template pmv1.ProjectTemplate) (pmv1.TemplateDefinition, error) {
+	var definition pmv1.TemplateDefinition
+	err := json.Unmarshal([]byte(template.Definition), &definition)
+	return definition, err
+}
+
+// EncodeTemplateDefinition serializes a definition for storage on a template.
+func EncodeTemplateDefinition(definition pmv1.TemplateDefinition) (string, error) {
+	encoded, err := json.Marshal(definition)
+	return string(encoded), err
+}
+
+// loadProjectWorkingCalendar returns the calendar of the organization of a project from the given day on.
+func loadProjectWorkingCalendar(tx *gorm.DB, projectID uuid.UUID, from time.Time) (*Calendar, error) {
+	organizationID, err := ProjectOrganizationID(tx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return LoadOrganizationWorkingCalendar(tx, organizationID, from)
+}
+
+// workingDaysBetween returns the number of working days from one date, inclusive, to another, exclusive.
+func workingDaysBetween(calendar *Calendar, from, to time.Time) int {
+	if from.IsZero() || to.IsZero() || !to.After(from) {
+		return 0
+	}
+	return len(calendar.WorkingDaysBetween(from, to.AddDate(0, 0, -1)))
+}
@@ -0,0 +1,487 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/san-data-systems/common/logger"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TrashConflictError is returned when a trashed item cannot be restored or purged in its current state.
+type TrashConflictError struct {
+	Reason string
+}
+
+// Error implements the error interface.
+func (e *TrashConflictError) Error() string {
+	return e.Reason
+}
+
+// DeletionTime returns the timestamp stamped on a deleted row and its dependents. It is truncated to
+// the precision of the database so that restores can match the dependents deleted with the row.
+func DeletionTime() time.Time {
+	return time.Now().Truncate(time.Microsecond)
+}
+
+// issueTreeSQL selects an issue and its sub-issues recursively, following only sub-issues matching the condition.
+const issueTreeSQL = `WITH RECURSIVE tree AS (
+	SELECT id FROM issues WHERE id = ?
+	UNION ALL
+	SELECT issues.id FROM issues JOIN tree ON issues.parent_id = tree.id WHERE %s
+) SELECT id FROM tree`
+
+// SoftDeleteIssueTree moves an issue and its live sub-issues to the trash.
+func SoftDeleteIssueTree(tx *gorm.DB, issueID uuid.UUID, at time.Time) error {
+	ids, err := issueTree(tx, issueID, "issues.deleted_at IS NULL")
+	if err != nil {
+		return err
+	}
+	return tx.Model(&v1.Issue{}).Where("id IN ? AND deleted_at IS NULL", ids).Update("deleted_at", at).Error
+}
+
+// SoftDeleteProject moves a project and its issues, states and labels to the trash.
+func SoftDeleteProject(tx *gorm.DB, project *v1.Project, at time.Time) error {
+	for _, model := range []interface{}{&v1.Issue{}, &v1.ProjectState{}, &v1.ProjectLabel{}} {
+		if err := tx.Model(model).Where("project_id = ? AND deleted_at IS NULL", project.ID).Update("deleted_at", at).Error; err != nil {
+			return err
+		}
+	}
+	project.DeletedAt = &at
+	return tx.Model(project).Update("deleted_at", at).Error
+}
+
+// RestoreIssue restores a trashed issue and the sub-issues deleted with it.
+func RestoreIssue(tx *gorm.DB, issue v1.Issue) (int64, error) {
+	var state v1.ProjectState
+	if err := tx.Where("id = ?", issue.StateID).First(&state).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+	if state.DeletedAt != nil {
+		return 0, &TrashConflictError{Reason: fmt.Sprintf("Restore the state %q first.", state.Name)}
+	}
+
+	if issue.ParentID != uuid.Nil {
+		var parent v1.Issue
+		err := tx.Select("id, deleted_at").Where("id = ?", issue.ParentID).First(&parent).Error
+		if err != nil && err != gorm.ErrRecordNotFound {
+			return 0, err
+		}
+		if err == nil && parent.DeletedAt != nil {
+			return 0, &TrashConflictError{Reason: "Restore the parent issue first."}
+		}
+	}
+
+	ids, err := issueTree(tx, issue.ID, "issues.deleted_at = ?", *issue.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	result := tx.Model(&v1.Issue{}).Where("id IN ?", ids).Update("deleted_at", nil)
+	return result.RowsAffected - 1, result.Error
+}
+
+// RestoreProject restores a trashed project and the issues, states and labels deleted with it.
+func RestoreProject(tx *gorm.DB, project v1.Project) (int64, error) {
+	var client v1.Client
+	if err := tx.Where("id = ?", project.ClientID).First(&client).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return 0, err
+	}
+	if client.DeletedAt != nil {
+		return 0, &TrashConflictError{Reason: fmt.Sprintf("Restore the client %q first.", client.Name)}
+	}
+
+	var slugs int64
+	if err := tx.Model(&v1.Project{}).Where("slug = ? AND id <> ? AND deleted_at IS NULL", project.Slug, project.ID).Count(&slugs).Error; err != nil {
+		return 0, err
+	}
+	if slugs > 0 {
+		return 0, &TrashConflictError{Reason: fmt.Sprintf("Another project now uses the slug %q.", project.Slug)}
+	}
+
+	var dependents int64
+	for _, model := range []interface{}{&v1.Issue{}, &v1.ProjectState{}, &v1.ProjectLabel{}} {
+		result := tx.Model(model).Where("project_id = ? AND deleted_at = ?", project.ID, *project.DeletedAt).Update("deleted_at", nil)
+		if result.Error != nil {
+			return 0, result.Error
+		}
+		dependents += result.RowsAffected
+	}
+	return dependents, tx.Model(&project).Update("deleted_at", nil).Error
+}
+
+// RestoreState restores a trashed project state.
+func RestoreState(tx *gorm.DB, state v1.ProjectState) error {
+	if err := requireLiveProject(tx, state.ProjectID); err != nil {
+		return err
+	}
+	return tx.Model(&state).Update("deleted_at", nil).Error
+}
+
+// RestoreLabel restores a trashed project label.
+func RestoreLabel(tx *gorm.DB, label v1.ProjectLabel) error {
+	if err := requireLiveProject(tx, label.ProjectID); err != nil {
+		return err
+	}
+	return tx.Model(&label).Update("deleted_at", nil).Error
+}
+
+// RestoreClient restores a trashed client.
+func RestoreClient(tx *gorm.DB, client v1.Client) error {
+	return tx.Model(&client).Update("deleted_at", nil).Error
+}
+
+// PurgeIssue permanently deletes a trashed issue, the sub-issues deleted with it and their time entries,
+// assignees, links, files and activities. Issues with invoiced time cannot be purged. It returns the
+// stored files of the deleted file rows, to be removed with RemoveStoredObjects once the transaction
+// commits.
+func PurgeIssue(tx *gorm.DB, issue v1.Issue) ([]StoredObject, error) {
+	ids, err := issueTree(tx, issue.ID, "issues.deleted_at = ?", *issue.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	var invoiced int64
+	if err := tx.Model(&pmv1.InvoiceLine{}).Where("issue_id IN ?", ids).Count(&invoiced).Error; err != nil {
+		return nil, err
+	}
+	if invoiced > 0 {
+		return nil, &TrashConflictError{Reason: "Issue has invoiced time entries and cannot be purged."}
+	}
+
+	objects, err := storedFiles(tx.Model(&v1.IssueFile{}).Where("issue_id IN ?", ids))
+	if err != nil {
+		return nil, err
+	}
+	timeEntries := tx.Model(&v1.TimeEntry{}).Select("id").Where("issue_id IN ?", ids)
+	if err := tx.Where("time_entry_id IN (?)", timeEntries).Delete(&pmv1.TimeEntryBilling{}).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{&v1.TimeEntry{}, &v1.IssueAssignee{}, &v1.IssueLink{}, &v1.IssueFile{}, &v1.IssueActivity{}} {
+		if err := tx.Where("issue_id IN ?", ids).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Model(&v1.Issue{}).Where("parent_id IN ? AND id NOT IN ?", ids, ids).Update("parent_id", uuid.Nil).Error; err != nil {
+		return nil, err
+	}
+	return objects, tx.Where("id IN ?", ids).Delete(&v1.Issue{}).Error
+}
+
+// PurgeState permanently deletes a trashed state and its workflow settings. States still referenced
+// by issues, trashed or not, cannot be purged.
+func PurgeState(tx *gorm.DB, state v1.ProjectState) error {
+	var issues int64
+	if err := tx.Model(&v1.Issue{}).Where("state_id = ?", state.ID).Count(&issues).Error; err != nil {
+		return err
+	}
+	if issues > 0 {
+		return &TrashConflictError{Reason: fmt.Sprintf("State %q is still used by %d issues.", state.Name, issues)}
+	}
+
+	for _, model := range []interface{}{&pmv1.ProjectStateCategory{}, &pmv1.StateWIPLimit{}} {
+		if err := tx.Where("state_id = ?", state.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("from_state_id = ? OR to_state_id = ?", state.ID, state.ID).Delete(&pmv1.StateTransition{}).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&state).Error
+}
+
+// PurgeLabel permanently deletes a trashed label and removes it from the issues carrying it.
+func PurgeLabel(tx *gorm.DB, label v1.ProjectLabel) error {
+	if err := tx.Exec("UPDATE issues SET label_ids = array_remove(label_ids, ?) WHERE project_id = ? AND ? = ANY(label_ids)",
+		label.ID.String(), label.ProjectID, label.ID.String()).Error; err != nil {
+		return err
+	}
+	return tx.Delete(&label).Error
+}
+
+// PurgeProject permanently deletes a trashed project with everything it owns. Projects with invoiced
+// time cannot be purged. It returns the stored files of the project and its issues, to be removed with
+// RemoveStoredObjects once the transaction commits.
+func PurgeProject(tx *gorm.DB, project v1.Project) ([]StoredObject, error) {
+	var invoiced int64
+	if err := tx.Model(&pmv1.InvoiceLine{}).Where("project_id = ?", project.ID).Count(&invoiced).Error; err != nil {
+		return nil, err
+	}
+	if invoiced > 0 {
+		return nil, &TrashConflictError{Reason: "Project has invoiced time entries and cannot be purged."}
+	}
+
+	var objects []StoredObject
+	for _, model := range []interface{}{&v1.ProjectFile{}, &v1.IssueFile{}} {
+		files, err := storedFiles(tx.Model(model).Where("project_id = ?", project.ID))
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, files...)
+	}
+
+	if err := tx.Model(&pmv1.ProjectTemplate{}).Where("source_project_id = ?", project.ID).Update("source_project_id", nil).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{
+		&pmv1.TimeEntryBilling{}, &v1.TimeEntry{},
+		&v1.IssueAssignee{}, &v1.IssueLink{}, &v1.IssueFile{}, &v1.IssueActivity{}, &v1.Issue{},
+		&pmv1.ProjectStateCategory{}, &pmv1.StateTransition{}, &pmv1.StateWIPLimit{}, &v1.ProjectState{},
+		&v1.ProjectLabel{}, &v1.ProjectMember{}, &v1.ProjectFile{}, &v1.ProjectActivity{},
+		&pmv1.ProjectBilling{}, &pmv1.ProjectMemberRate{}, &pmv1.ProjectBudget{}, &pmv1.ProjectBudgetAlert{},
+		&pmv1.SavedView{}, &pmv1.ProjectArchive{}, &pmv1.Notification{},
+		&pmv1.IssueComment{}, &pmv1.ProjectInvitation{}, &pmv1.ProjectTeam{}, &pmv1.OrganizationProject{},
+	} {
+		if err := tx.Where("project_id = ?", project.ID).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	return objects, tx.Delete(&project).Error
+}
+
+// PurgeClient permanently deletes a trashed client. Clients that still have projects or invoices cannot be purged.
+func PurgeClient(tx *gorm.DB, client v1.Client) error {
+	var projects int64
+	if err := tx.Model(&v1.Project{}).Where("client_id = ?", client.ID).Count(&projects).Error; err != nil {
+		return err
+	}
+	if projects > 0 {
+		return &TrashConflictError{Reason: fmt.Sprintf("Client %q still has %d projects.", client.Name, projects)}
+	}
+
+	var invoices int64
+	if err := tx.Model(&pmv1.Invoice{}).Where("client_id = ?", client.ID).Count(&invoices).Error; err != nil {
+		return err
+	}
+	if invoices > 0 {
+		return &TrashConflictError{Reason: fmt.Sprintf("Client %q has invoices and cannot be purged.", client.Name)}
+	}
+
+	for _, model := range []interface{}{&pmv1.ClientBilling{}, &pmv1.OrganizationClient{}} {
+		if err := tx.Where("client_id = ?", client.ID).Delete(model).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Delete(&client).Error
+}
+
+// PurgeExpired permanently deletes the rows trashed before the cutoff. Each row is purged in its own
+// transaction; rows that cannot be purged are skipped and counted. The stored files of purged rows are
+// removed from object storage. Cancelling the context stops the purge.
+func PurgeExpired(ctx context.Context, db *gorm.DB, cutoff time.Time) (pmv1.PurgeSummary, error) {
+	db = db.WithContext(ctx)
+	var summary pmv1.PurgeSummary
+	count := func(purged bool, kind *int) {
+		if purged {
+			*kind++
+		} else {
+			summary.Skipped++
+		}
+	}
+
+	var projects []v1.Project
+	if err := db.Where("deleted_at < ?", cutoff).Find(&projects).Error; err != nil {
+		return summary, err
+	}
+	for _, project := range projects {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		count(purgeInTransaction(ctx, db, pmv1.TrashKindProject, project.ID, func(tx *gorm.DB) ([]StoredObject, error) {
+			return PurgeProject(tx, project)
+		}), &summary.Projects)
+	}
+
+	var issues []v1.Issue
+	if err := db.Where(expiredInLiveProject("issues"), cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM issues parents WHERE parents.id = issues.parent_id AND parents.deleted_at = issues.deleted_at)").
+		Find(&issues).Error; err != nil {
+		return summary, err
+	}
+	for _, issue := range issues {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		count(purgeInTransaction(ctx, db, pmv1.TrashKindIssue, issue.ID, func(tx *gorm.DB) ([]StoredObject, error) {
+			return PurgeIssue(tx, issue)
+		}), &summary.Issues)
+	}
+
+	var labels []v1.ProjectLabel
+	if err := db.Where(expiredInLiveProject("project_labels"), cutoff).Find(&labels).Error; err != nil {
+		return summary, err
+	}
+	for _, label := range labels {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		count(purgeInTransaction(ctx, db, pmv1.TrashKindLabel, label.ID, func(tx *gorm.DB) ([]StoredObject, error) {
+			return nil, PurgeLabel(tx, label)
+		}), &summary.Labels)
+	}
+
+	var states []v1.ProjectState
+	if err := db.Where(expiredInLiveProject("project_states"), cutoff).Find(&states).Error; err != nil {
+		return summary, err
+	}
+	for _, state := range states {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		count(purgeInTransaction(ctx, db, pmv1.TrashKindState, state.ID, func(tx *gorm.DB) ([]StoredObject, error) {
+			return nil, PurgeState(tx, state)
+		}), &summary.States)
+	}
+
+	var clients []v1.Client
+	if err := db.Where("deleted_at < ?", cutoff).Find(&clients).Error; err != nil {
+		return summary, err
+	}
+	for _, client := range clients {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		count(purgeInTransaction(ctx, db, pmv1.TrashKindClient, client.ID, func(tx *gorm.DB) ([]StoredObject, error) {
+			return nil, PurgeClient(tx, client)
+		}), &summary.Clients)
+	}
+
+	return summary, nil
+}
+
+// trashRetentionLockID is the key of the advisory lock held while purging expired trash, so that a
+// single instance purges at a time.
+const trashRetentionLockID int64 = 7_261_146_502_117_926
+
+// RunTrashRetention purges rows trashed for longer than the retention period every interval until the
+// context is cancelled. A run is skipped when another instance holds the retention lock.
+func RunTrashRetention(ctx context.Context, db *gorm.DB, retentionDays int, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		cutoff := time.Now().AddDate(0, 0, -retentionDays)
+		var summary pmv1.PurgeSummary
+		var purgeErr error
+		acquired, err := withTryLock(ctx, db, trashRetentionLockID, func() {
+			summary, purgeErr = PurgeExpired(ctx, db, cutoff)
+		})
+		switch {
+		case err != nil:
+			logger.LogError("Failed to acquire the trash retention lock.", logrus.Fields{"error": err.Error()})
+		case !acquired:
+			logger.LogInfo("Skipped purging expired trash, another instance is purging it.", nil)
+		case purgeErr != nil:
+			logger.LogError("Failed to purge expired trash.", logrus.Fields{"error": purgeErr.Error()})
+		default:
+			logger.LogInfo("Purged expired trash.", logrus.Fields{
+				"projects": summary.Projects,
+				"clients":  summary.Clients,
+				"issues":   summary.Issues,
+				"states":   summary.States,
+				"labels":   summary.Labels,
+				"skipped":  summary.Skipped,
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// withTryLock runs a function holding a session advisory lock on a dedicated connection and reports
+// whether it ran: it does not when the lock is held elsewhere.
+func withTryLock(ctx context.Context, db *gorm.DB, lockID int64, fn func()) (bool, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", lockID).Scan(&acquired); err != nil {
+		return false, err
+	}
+	if !acquired {
+		return false, nil
+	}
+	defer func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", lockID)
+	}()
+
+	fn()
+	return true, nil
+}
+
+// purgeInTransaction runs a purge in its own transaction, removes the stored files of the purged rows
+// once it commits and reports whether it succeeded.
+func purgeInTransaction(ctx context.Context, db *gorm.DB, kind string, id uuid.UUID, purge func(tx *gorm.DB) ([]StoredObject, error)) bool {
+	var objects []StoredObject
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		objects, err = purge(tx)
+		return err
+	})
+	if err != nil {
+		logger.LogInfo("Skipped purging expired trash.", logrus.Fields{"kind": kind, "id": id.String(), "reason": err.Error()})
+		return false
+	}
+	RemoveStoredObjects(ctx, objects)
+	return true
+}
+
+// storedFiles returns the stored files of the file rows selected by a query on project or issue files.
+func storedFiles(query *gorm.DB) ([]StoredObject, error) {
+	var objects []StoredObject
+	err := query.Select("project_id AS bucket, file_path AS name").Scan(&objects).Error
+	return objects, err
+}
+
+// expiredInLiveProject returns the condition selecting the rows of a table trashed before a cutoff whose
+// project is not itself trashed; those are purged together with their project.
+func expiredInLiveProject(table string) string {
+	return fmt.Sprintf("%[1]s.deleted_at < ? AND NOT EXISTS (SELECT 1 FROM projects WHERE projects.id = %[1]s.project_id AND projects.deleted_at IS NOT NULL)", table)
+}
+
+// requireLiveProject returns a conflict when the project has been trashed.
+func requireLiveProject(tx *gorm.DB, projectID uuid.UUID) error {
+	var project v1.Project
+	if err := tx.Select("id, name, deleted_at").Where("id = ?", projectID).First(&project).Error; err != nil {
+		return err
+	}
+	if project.DeletedAt != nil {
+		return &TrashConflictError{Reason: fmt.Sprintf("Restore the project %q first.", project.Name)}
+	}
+	return nil
+}
+
+// issueTree returns the ID of an issue and of its sub-issues matching the condition, recursively.
+func issueTree(tx *gorm.DB, issueID uuid.UUID, condition string, args ...interface{}) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := tx.Raw(fmt.Sprintf(issueTreeSQL, condition), append([]interface{}{issueID}, args...)...).Scan(&ids).Error
+	return ids, err
+}
+
+// TrashRetentionDays returns the number of days trashed rows are kept before the retention job purges
+// them, read from TRASH_RETENTION_DAYS. Zero, the default, disables the job and keeps trashed rows until
+// they are purged by hand.
+func TrashRetentionDays() int {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days < 0 {
+		return 0
+	}
+	return days
+}
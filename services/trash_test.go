@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+)
+
+func TestTrashRetentionDays(t *testing.T) {
+	for value, want := range map[string]int{"": 0, "0": 0, "-5": 0, "thirty": 0, "30": 30} {
+		t.Setenv("TRASH_RETENTION_DAYS", value)
+		if got := TrashRetentionDays(); got != want {
+			t.Errorf("TrashRetentionDays() with %q = %d, want %d", value, got, want)
+		}
+	}
+}
+
+func TestPurgeIssueReturnsStoredFiles(t *testing.T) {
+	tx := testDB(t)
+	projectID := uuid.New()
+	deletedAt := DeletionTime()
+	parent := v1.Issue{ID: uuid.New(), ProjectID: projectID, Title: "Parent", DeletedAt: &deletedAt, CreatedBy: "jane@example.com"}
+	child := v1.Issue{ID: uuid.New(), ProjectID: projectID, ParentID: parent.ID, Title: "Child", DeletedAt: &deletedAt, CreatedBy: "jane@example.com"}
+	for _, issue := range []*v1.Issue{&parent, &child} {
+		if err := tx.Create(issue).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A file deleted before the issue is still in storage.
+	fileDeletedAt := deletedAt.Add(-time.Hour)
+	for _, file := range []v1.IssueFile{
+		{ID: uuid.New(), IssueID: parent.ID, ProjectID: projectID, FileName: "spec.pdf", FilePath: "issues/" + parent.ID.String() + "/files/a.pdf", UploadedBy: "jane@example.com"},
+		{ID: uuid.New(), IssueID: child.ID, ProjectID: projectID, FileName: "old.png", FilePath: "issues/" + child.ID.String() + "/files/b.png", UploadedBy: "jane@example.com", DeletedAt: &fileDeletedAt},
+	} {
+		if err := tx.Create(&file).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	objects, err := PurgeIssue(tx, parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 2 {
+		t.Fatalf("stored files = %+v, want both files of the issue and its sub-issue", objects)
+	}
+	for _, object := range objects {
+		if object.Bucket != projectID.String() || object.Name == "" {
+			t.Errorf("stored file = %+v, want a file in the bucket of the project", object)
+		}
+	}
+	var remaining int64
+	if err := tx.Model(&v1.IssueFile{}).Where("project_id = ?", projectID).Count(&remaining).Error; err != nil || remaining != 0 {
+		t.Errorf("%d file rows left, %v", remaining, err)
+	}
+}
+
+func TestWithTryLock(t *testing.T) {
+	db := openTestDB(t)
+	ctx := context.Background()
+	const lockID = 7_261_146_502_117_999
+
+	ran, err := withTryLock(ctx, db, lockID, func() {
+		// Another instance cannot purge while this one holds the lock.
+		nested, err := withTryLock(ctx, db, lockID, func() { t.Error("ran while the lock was held") })
+		if err != nil || nested {
+			t.Errorf("nested run = %t, %v, want it skipped", nested, err)
+		}
+	})
+	if err != nil || !ran {
+		t.Fatalf("first run = %t, %v", ran, err)
+	}
+
+	if ran, err := withTryLock(ctx, db, lockID, func() {}); err != nil || !ran {
+		t.Errorf("run after the lock was released = %t, %v", ran, err)
+	}
+}
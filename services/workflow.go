@@ -0,0 +1,201 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// Errors returned when a state transition is refused.
+var (
+	ErrTransitionNotAllowed = errors.New("transition between these states is not allowed")
+	ErrTransitionForbidden  = errors.New("your role is not allowed to make this transition")
+)
+
+// LoadStateCategories returns the category of every categorized state of a project.
+// States absent from the result belong to DefaultStateCategory.
+func LoadStateCategories(tx *gorm.DB, projectID uuid.UUID) (map[uuid.UUID]string, error) {
+	var rows []pmv1.ProjectStateCategory
+	if err := tx.Where("project_id = ?", projectID).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	categories := make(map[uuid.UUID]string, len(rows))
+	for _, row := range rows {
+		categories[row.StateID] = row.Category
+	}
+	return categories, nil
+}
+
+// StateCategory returns the category of a single state.
+func StateCategory(tx *gorm.DB, stateID uuid.UUID) (string, error) {
+	var row pmv1.ProjectStateCategory
+	if err := tx.Where("state_id = ?", stateID).First(&row).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return pmv1.DefaultStateCategory, nil
+		}
+		return "", err
+	}
+	return row.Category, nil
+}
+
+// CheckTransition verifies that a member with the given role may move an issue of a project from one state to another.
+// Projects without transition rules allow every move.
+func CheckTransition(tx *gorm.DB, projectID, fromStateID, toStateID uuid.UUID, role string) error {
+	if fromStateID == toStateID {
+		return nil
+	}
+
+	var rules []pmv1.StateTransition
+	if err := tx.Where("project_id = ?", projectID).Find(&rules).Error; err != nil {
+		return err
+	}
+	return evaluateTransition(rules, &fromStateID, toStateID, role)
+}
+
+// CheckEntry verifies that a member with the given role may create an issue of a project directly in a state.
+// The first state of the project and states no rule leads to are open to every member; other states need a
+// rule allowing the move from any state.
+func CheckEntry(tx *gorm.DB, projectID, toStateID uuid.UUID, role string) error {
+	var rules []pmv1.StateTransition
+	if err := tx.Where("project_id = ?", projectID).Find(&rules).Error; err != nil {
+		return err
+	}
+	if len(rules) == 0 {
+		return nil
+	}
+
+	var first v1.ProjectState
+	if err := tx.Where("project_id = ? AND deleted_at IS NULL", projectID).Order("sequence ASC").First(&first).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+	if first.ID == toStateID {
+		return nil
+	}
+	return evaluateTransition(rules, nil, toStateID, role)
+}
+
+// evaluateTransition applies the transition rules of a project to a move into a state, from the given state
+// or, when fromStateID is nil, from no state at all as when an issue is created. A rule listing roles lets
+// those roles and the more privileged ones through.
+func evaluateTransition(rules []pmv1.StateTransition, fromStateID *uuid.UUID, toStateID uuid.UUID, role string) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	targeted, matched := false, false
+	for _, rule := range rules {
+		if rule.ToStateID != toStateID {
+			continue
+		}
+		targeted = true
+		if rule.FromStateID != nil && (fromStateID == nil || *rule.FromStateID != *fromStateID) {
+			continue
+		}
+		matched = true
+		if len(rule.Roles) == 0 {
+			return nil
+		}
+		for _, allowed := range rule.Roles {
+			if pmv1.RoleAtLeast(role, allowed) {
+				return nil
+			}
+		}
+	}
+
+	switch {
+	case matched:
+		return ErrTransitionForbidden
+	case fromStateID == nil && !targeted:
+		return nil
+	default:
+		return ErrTransitionNotAllowed
+	}
+}
+
+// MoveIssueToState moves an issue to a state after checking the transition rules of its project.
+// CompletedAt is set when the issue enters a completed or cancelled state and cleared when it leaves one,
+// and the move is recorded in the issue activity. The caller saves the issue.
+func MoveIssueToState(tx *gorm.DB, issue *v1.Issue, toStateID uuid.UUID, email, role string) error {
+	fromStateID := issue.StateID
+	if fromStateID == toStateID {
+		return nil
+	}
+
+	if err := CheckTransition(tx, issue.ProjectID, fromStateID, toStateID, role); err != nil {
+		return err
+	}
+
+	category, err := StateCategory(tx, toStateID)
+	if err != nil {
+		return err
+	}
+
+	return setIssueState(tx, issue, toStateID, category, email)
+}
+
+// MigrateIssuesToState moves issues to a state without checking transition rules, saving each issue.
+// It is used when their current state is deleted.
+func MigrateIssuesToState(tx *gorm.DB, issues []v1.Issue, toStateID uuid.UUID, email string) error {
+	category, err := StateCategory(tx, toStateID)
+	if err != nil {
+		return err
+	}
+
+	for i := range issues {
+		issue := &issues[i]
+		if issue.StateID == toStateID {
+			continue
+		}
+		if err := setIssueState(tx, issue, toStateID, category, email); err != nil {
+			return err
+		}
+		issue.UpdatedBy = email
+		if err := tx.Save(issue).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setIssueState moves an issue to a state of the given category and records the move in the issue activity.
+func setIssueState(tx *gorm.DB, issue *v1.Issue, toStateID uuid.UUID, category, email string) error {
+	fromStateID := issue.StateID
+	issue.StateID = toStateID
+	ApplyStateCategory(issue, category, time.Now())
+
+	return tx.Create(&v1.IssueActivity{
+		IssueID:   issue.ID,
+		ProjectID: issue.ProjectID,
+		Email:     email,
+		Action:    "updated",
+		Entity:    "issue",
+		Column:    "state_id",
+		OldValue:  fromStateID.String(),
+		NewValue:  toStateID.String(),
+	}).Error
+}
+
+// ApplyStateCategory sets or clears the CompletedAt of an issue according to the category of its state.
+func ApplyStateCategory(issue *v1.Issue, category string, now time.Time) {
+	if !pmv1.IsClosedStateCategory(category) {
+		issue.CompletedAt = nil
+		return
+	}
+	if issue.CompletedAt == nil {
+		issue.CompletedAt = &now
+	}
+}
+
+// SyncCompletedAt updates the CompletedAt of every issue in a state after its category changed.
+func SyncCompletedAt(tx *gorm.DB, stateID uuid.UUID, category string) error {
+	query := tx.Model(&v1.Issue{}).Where("state_id = ? AND deleted_at IS NULL", stateID)
+	if pmv1.IsClosedStateCategory(category) {
+		return query.Where("completed_at IS NULL").Update("completed_at", time.Now()).Error
+	}
+	return query.Where("completed_at IS NOT NULL").Update("completed_at", nil).Error
+}
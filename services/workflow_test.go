@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+)
+
+func TestEvaluateTransition(t *testing.T) {
+	backlog, doing, review, done := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	rules := []pmv1.StateTransition{
+		{FromStateID: &backlog, ToStateID: doing},
+		{FromStateID: &doing, ToStateID: review, Roles: []string{pmv1.RoleContributor}},
+		{FromStateID: &review, ToStateID: done, Roles: []string{pmv1.RoleManager}},
+		{ToStateID: backlog, Roles: []string{"Release manager"}},
+	}
+
+	tests := []struct {
+		name    string
+		rules   []pmv1.StateTransition
+		from    *uuid.UUID
+		to      uuid.UUID
+		role    string
+		wantErr error
+	}{
+		{"no rules", nil, &done, backlog, pmv1.RoleWatcher, nil},
+		{"rule without roles", rules, &backlog, doing, pmv1.RoleWatcher, nil},
+		{"role of the rule", rules, &doing, review, pmv1.RoleContributor, nil},
+		{"more privileged role", rules, &doing, review, pmv1.RoleOwner, nil},
+		{"Owner through a Manager rule", rules, &review, done, pmv1.RoleOwner, nil},
+		{"less privileged role", rules, &review, done, pmv1.RoleContributor, ErrTransitionForbidden},
+		{"custom role of the rule", rules, &done, backlog, "Release manager", nil},
+		{"other custom role", rules, &done, backlog, "Designer", ErrTransitionForbidden},
+		{"Owner through a custom role rule", rules, &done, backlog, pmv1.RoleOwner, ErrTransitionForbidden},
+		{"no rule from the state", rules, &backlog, done, pmv1.RoleOwner, ErrTransitionNotAllowed},
+		{"no rule to the state", rules, &review, uuid.New(), pmv1.RoleOwner, ErrTransitionNotAllowed},
+		{"entry into a state a from-any rule allows", rules, nil, backlog, "Release manager", nil},
+		{"entry forbidden by a from-any rule", rules, nil, backlog, pmv1.RoleContributor, ErrTransitionForbidden},
+		{"entry into a state only reached from others", rules, nil, review, pmv1.RoleOwner, ErrTransitionNotAllowed},
+		{"entry into a state no rule leads to", rules, nil, uuid.New(), pmv1.RoleWatcher, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := evaluateTransition(test.rules, test.from, test.to, test.role); !errors.Is(err, test.wantErr) {
+				t.Errorf("evaluateTransition = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTransition(t *testing.T) {
+	state := uuid.New()
+	if err := CheckTransition(nil, uuid.New(), state, state, pmv1.RoleWatcher); err != nil {
+		t.Errorf("staying in a state: %v", err)
+	}
+
+	tx := testDB(t)
+	projectID := uuid.New()
+	states := make([]v1.ProjectState, 3)
+	for i := range states {
+		states[i] = v1.ProjectState{ID: uuid.New(), ProjectID: projectID, Name: []string{"To do", "Doing", "Done"}[i], Sequence: int32(i), CreatedBy: "jane@example.com"}
+		if err := tx.Create(&states[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	todo, doing, done := states[0].ID, states[1].ID, states[2].ID
+
+	if err := CheckTransition(tx, projectID, todo, done, pmv1.RoleWatcher); err != nil {
+		t.Errorf("moving in a project without rules: %v", err)
+	}
+	if err := CheckEntry(tx, projectID, done, pmv1.RoleWatcher); err != nil {
+		t.Errorf("creating in a project without rules: %v", err)
+	}
+
+	for _, rule := range []pmv1.StateTransition{
+		{ProjectID: projectID, FromStateID: &todo, ToStateID: doing, CreatedBy: "jane@example.com"},
+		{ProjectID: projectID, FromStateID: &doing, ToStateID: done, Roles: []string{pmv1.RoleManager}, CreatedBy: "jane@example.com"},
+		{ProjectID: projectID, FromStateID: &doing, ToStateID: todo, CreatedBy: "jane@example.com"},
+	} {
+		if err := tx.Create(&rule).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		check   func() error
+		wantErr error
+	}{
+		{"allowed move", func() error { return CheckTransition(tx, projectID, todo, doing, pmv1.RoleWatcher) }, nil},
+		{"move by a more privileged role", func() error { return CheckTransition(tx, projectID, doing, done, pmv1.RoleOwner) }, nil},
+		{"move by a less privileged role", func() error { return CheckTransition(tx, projectID, doing, done, pmv1.RoleContributor) }, ErrTransitionForbidden},
+		{"move without a rule", func() error { return CheckTransition(tx, projectID, todo, done, pmv1.RoleOwner) }, ErrTransitionNotAllowed},
+		{"rules of another project", func() error { return CheckTransition(tx, uuid.New(), todo, done, pmv1.RoleWatcher) }, nil},
+		{"creation in the first state", func() error { return CheckEntry(tx, projectID, todo, pmv1.RoleWatcher) }, nil},
+		{"creation in a later state", func() error { return CheckEntry(tx, projectID, done, pmv1.RoleOwner) }, ErrTransitionNotAllowed},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.check(); !errors.Is(err, test.wantErr) {
+				t.Errorf("got %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,314 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	v1 "github.com/san-data-systems/common/models/v1"
+	pmv1 "github.com/san-data-systems/project-management-api/models/v1"
+	"gorm.io/gorm"
+)
+
+// MaxWorkloadDays bounds the date range a workload can be computed for.
+const MaxWorkloadDays = 92
+
+// assignedIssue is an open issue joined with one of its assignees.
+type assignedIssue struct {
+	Email          string
+	ID             uuid.UUID
+	ProjectID      uuid.UUID
+	Title          string
+	StartDate      time.Time
+	EndDate        time.Time
+	EstimatedHours float64
+}
+
+// TruncateDay returns the date of t at midnight UTC.
+func TruncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// LoadWeeklyCapacities returns the configured weekly capacity of each email, keyed by lower-cased email.
+// Users without a configured capacity are absent from the result; their calendar defines their capacity.
+func LoadWeeklyCapacities(tx *gorm.DB, emails []string) (map[string]float64, error) {
+	capacities := make(map[string]float64, len(emails))
+	if len(emails) == 0 {
+		return capacities, nil
+	}
+
+	var rows []pmv1.MemberCapacity
+	if err := tx.Where("LOWER(email) IN ?", lowerEmails(emails)).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		capacities[strings.ToLower(row.Email)] = row.WeeklyHours
+	}
+	return capacities, nil
+}
+
+// ProjectMemberEmails returns the creator and members of the given projects, de-duplicated.
+func ProjectMemberEmails(tx *gorm.DB, projectIDs []uuid.UUID) ([]string, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+
+	var creators []string
+	if err := tx.Model(&v1.Project{}).Where("id IN ?", projectIDs).Pluck("created_by", &creators).Error; err != nil {
+		return nil, err
+	}
+
+	var members []string
+	if err := tx.Model(&v1.ProjectMember{}).Where("project_id IN ?", projectIDs).Pluck("email", &members).Error; err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var emails []string
+	for _, email := range append(creators, members...) {
+		key := strings.ToLower(email)
+		if email == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		emails = append(emails, email)
+	}
+	sort.Strings(emails)
+	return emails, nil
+}
+
+// ComputeWorkload calculates the workload of the given members on the given projects between from and to.
+//
+// The remaining estimate of every open issue (EstimatedHours minus the hours already logged on it) is
+// split evenly between its assignees and spread over the member's working days left until the issue's
+// end date. Overdue work is planned on today. Daily capacity follows the member's calendar in the
+// organization, scaled to their weekly capacity when one is configured. A day is over-allocated when
+// planned and logged hours exceed the member's daily capacity.
+func ComputeWorkload(tx *gorm.DB, organizationID uuid.UUID, projectIDs []uuid.UUID, emails []string, from, to, today time.Time) (pmv1.WorkloadResponse, error) {
+	from, to, today = TruncateDay(from), TruncateDay(to), TruncateDay(today)
+	response := pmv1.WorkloadResponse{From: from, To: to, Members: []pmv1.MemberWorkload{}}
+	if len(projectIDs) == 0 || len(emails) == 0 {
+		return response, nil
+	}
+
+	capacities, err := LoadWeeklyCapacities(tx, emails)
+	if err != nil {
+		return response, err
+	}
+
+	var rows []assignedIssue
+	if err := tx.Table("issue_assignees").
+		Select("issue_assignees.email, issues.id, issues.project_id, issues.title, issues.start_date, issues.end_date, issues.estimated_hours").
+		Joins("JOIN issues ON issues.id = issue_assignees.issue_id").
+		Where("issues.project_id IN ?", projectIDs).
+		Where("issues.deleted_at IS NULL AND issues.completed_at IS NULL AND issues.is_draft = ?", false).
+		Order("issues.end_date ASC").
+		Scan(&rows).Error; err != nil {
+		return response, err
+	}
+
+	assigneeCount := map[uuid.UUID]int{}
+	var issueIDs []uuid.UUID
+	for _, row := range rows {
+		if assigneeCount[row.ID] == 0 {
+			issueIDs = append(issueIDs, row.ID)
+		}
+		assigneeCount[row.ID]++
+	}
+
+	loggedByIssue := map[uuid.UUID]float64{}
+	if len(issueIDs) > 0 {
+		var logged []struct {
+			IssueID uuid.UUID
+			Hours   float64
+		}
+		if err := tx.Model(&v1.TimeEntry{}).
+			Select("time_entries.issue_id, COALESCE(SUM("+TimeEntryHoursSQL+"), 0) AS hours").
+			Where("time_entries.issue_id IN ?", issueIDs).
+			Group("time_entries.issue_id").
+			Scan(&logged).Error; err != nil {
+			return response, err
+		}
+		for _, row := range logged {
+			loggedByIssue[row.IssueID] = row.Hours
+		}
+	}
+
+	var loggedByDay []struct {
+		CreatedBy string
+		Date      time.Time
+		Hours     float64
+	}
+	if err := tx.Model(&v1.TimeEntry{}).
+		Select("time_entries.created_by, time_entries.date, COALESCE(SUM("+TimeEntryHoursSQL+"), 0) AS hours").
+		Where("time_entries.project_id IN ? AND LOWER(time_entries.created_by) IN ?", projectIDs, lowerEmails(emails)).
+		Where("time_entries.date BETWEEN ? AND ?", from, to).
+		Group("time_entries.created_by, time_entries.date").
+		Scan(&loggedByDay).Error; err != nil {
+		return response, err
+	}
+
+	calendarFrom, calendarTo := from, to
+	if today.Before(calendarFrom) {
+		calendarFrom = today
+	}
+	for _, row := range rows {
+		if end := TruncateDay(row.EndDate); end.After(calendarTo) {
+			calendarTo = end
+		}
+	}
+	calendars, err := LoadCalendars(tx, organizationID, emails, calendarFrom, calendarTo)
+	if err != nil {
+		return response, err
+	}
+
+	members := make(map[string]*pmv1.MemberWorkload, len(emails))
+	for _, email := range emails {
+		key := strings.ToLower(email)
+		calendar := calendars[key]
+		weekly, configured := capacities[key]
+		if !configured {
+			weekly = calendar.WeeklyHours()
+		}
+		member := &pmv1.MemberWorkload{
+			Email:          email,
+			WeeklyCapacity: weekly,
+			Days:           []pmv1.WorkloadDay{},
+			Issues:         []pmv1.WorkloadIssue{},
+		}
+		for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+			capacity := calendar.HoursOn(day)
+			if configured && capacity > 0 {
+				capacity = weekly / float64(len(calendar.WorkingDays))
+			}
+			member.CapacityHours += capacity
+			member.Days = append(member.Days, pmv1.WorkloadDay{Date: day, CapacityHours: RoundHours(capacity)})
+		}
+		members[key] = member
+	}
+
+	for _, row := range loggedByDay {
+		member, ok := members[strings.ToLower(row.CreatedBy)]
+		if !ok {
+			continue
+		}
+		if index := dayIndex(from, row.Date); index >= 0 && index < len(member.Days) {
+			member.Days[index].LoggedHours += row.Hours
+			member.LoggedHours += row.Hours
+		}
+	}
+
+	for _, row := range rows {
+		member, ok := members[strings.ToLower(row.Email)]
+		if !ok {
+			continue
+		}
+		calendar := calendars[strings.ToLower(row.Email)]
+
+		remaining := math.Max(row.EstimatedHours-loggedByIssue[row.ID], 0)
+		share := remaining / float64(assigneeCount[row.ID])
+		endDate := TruncateDay(row.EndDate)
+		overdue := !row.EndDate.IsZero() && endDate.Before(today)
+
+		member.OpenIssues++
+		member.RemainingHours += share
+		member.Issues = append(member.Issues, pmv1.WorkloadIssue{
+			ID:             row.ID.String(),
+			ProjectID:      row.ProjectID.String(),
+			Title:          row.Title,
+			StartDate:      row.StartDate,
+			EndDate:        row.EndDate,
+			EstimatedHours: row.EstimatedHours,
+			RemainingHours: RoundHours(share),
+			Overdue:        overdue,
+			WorkingDaysLeft: len(calendar.WorkingDaysBetween(
+				maxDay(TruncateDay(row.StartDate), today), endDate)),
+		})
+
+		for day, hours := range spreadHours(calendar, share, row.StartDate, row.EndDate, today) {
+			if index := dayIndex(from, day); index >= 0 && index < len(member.Days) {
+				member.Days[index].AllocatedHours += hours
+				member.AllocatedHours += hours
+			}
+		}
+	}
+
+	for _, email := range emails {
+		member := members[strings.ToLower(email)]
+		for i := range member.Days {
+			day := &member.Days[i]
+			day.OverAllocated = day.AllocatedHours+day.LoggedHours > day.CapacityHours+0.005
+			if day.OverAllocated {
+				member.OverAllocatedDays++
+			}
+			day.AllocatedHours = RoundHours(day.AllocatedHours)
+			day.LoggedHours = RoundHours(day.LoggedHours)
+		}
+		member.OverAllocated = member.OverAllocatedDays > 0
+		member.CapacityHours = RoundHours(member.CapacityHours)
+		member.RemainingHours = RoundHours(member.RemainingHours)
+		member.AllocatedHours = RoundHours(member.AllocatedHours)
+		member.LoggedHours = RoundHours(member.LoggedHours)
+		response.Members = append(response.Members, *member)
+	}
+
+	return response, nil
+}
+
+// spreadHours distributes hours evenly over the calendar's working days between the later of start and today and end.
+// Work that is already overdue, or has no working day left, is planned on the first day.
+func spreadHours(calendar *Calendar, hours float64, start, end, today time.Time) map[time.Time]float64 {
+	if hours <= 0 {
+		return nil
+	}
+
+	first := TruncateDay(start)
+	if start.IsZero() || first.Before(today) {
+		first = today
+	}
+	last := TruncateDay(end)
+	if end.IsZero() || last.Before(first) {
+		return map[time.Time]float64{today: hours}
+	}
+
+	days := calendar.WorkingDaysBetween(first, last)
+	if len(days) == 0 {
+		return map[time.Time]float64{first: hours}
+	}
+
+	perDay := hours / float64(len(days))
+	spread := make(map[time.Time]float64, len(days))
+	for _, day := range days {
+		spread[day] = perDay
+	}
+	return spread
+}
+
+// maxDay returns the later of two days.
+func maxDay(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// dayIndex returns the offset in days of day from from.
+func dayIndex(from, day time.Time) int {
+	return int(TruncateDay(day).Sub(from).Hours() / 24)
+}
+
+// RoundHours rounds hours to two decimals.
+func RoundHours(hours float64) float64 {
+	return math.Round(hours*100) / 100
+}
+
+// lowerEmails returns the lower-cased form of each email.
+func lowerEmails(emails []string) []string {
+	lowered := make([]string, len(emails))
+	for i, email := range emails {
+		lowered[i] = strings.ToLower(email)
+	}
+	return lowered
+}
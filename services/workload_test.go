@@ -0,0 +1,85 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSpreadHours(t *testing.T) {
+	calendar := testCalendar()
+	tests := []struct {
+		name       string
+		hours      float64
+		start, end time.Time
+		today      time.Time
+		want       map[time.Time]float64
+	}{
+		{
+			name:  "evenly over the working days",
+			hours: 12, start: day(2), end: day(6), today: day(2),
+			want: map[time.Time]float64{day(2): 3, day(3): 3, day(5): 3, day(6): 3},
+		},
+		{
+			name:  "from today when the issue has started",
+			hours: 6, start: day(2), end: day(10), today: day(6),
+			want: map[time.Time]float64{day(6): 2, day(9): 2, day(10): 2},
+		},
+		{
+			name:  "from the start date when the issue starts later",
+			hours: 4, start: day(10), end: day(11), today: day(2),
+			want: map[time.Time]float64{day(10): 2, day(11): 2},
+		},
+		{
+			name:  "from today without a start date",
+			hours: 4, end: day(3), today: day(2),
+			want: map[time.Time]float64{day(2): 2, day(3): 2},
+		},
+		{
+			name:  "overdue work on today",
+			hours: 5, start: day(2), end: day(3), today: day(9),
+			want: map[time.Time]float64{day(9): 5},
+		},
+		{
+			name:  "no end date on today",
+			hours: 5, start: day(2), today: day(3),
+			want: map[time.Time]float64{day(3): 5},
+		},
+		{
+			name:  "no working day left on the first day",
+			hours: 5, start: day(12), end: day(15), today: day(2),
+			want: map[time.Time]float64{day(12): 5},
+		},
+		{
+			name:  "nothing remaining",
+			hours: 0, start: day(2), end: day(6), today: day(2),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got := spreadHours(calendar, test.hours, test.start, test.end, test.today)
+			if len(got) != len(test.want) {
+				t.Fatalf("spreadHours = %v, want %v", got, test.want)
+			}
+			total := 0.0
+			for day, hours := range test.want {
+				if math.Abs(got[day]-hours) > 1e-9 {
+					t.Errorf("hours on %s = %v, want %v", day.Format(time.DateOnly), got[day], hours)
+				}
+				total += got[day]
+			}
+			if math.Abs(total-test.hours) > 1e-9 {
+				t.Errorf("spread %v hours, want %v", total, test.hours)
+			}
+		})
+	}
+}
+
+func TestDayIndex(t *testing.T) {
+	if got := dayIndex(day(2), day(5).Add(13*time.Hour)); got != 3 {
+		t.Errorf("dayIndex = %d, want 3", got)
+	}
+	if got := dayIndex(day(5), day(2)); got != -3 {
+		t.Errorf("dayIndex before from = %d, want -3", got)
+	}
+}
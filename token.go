@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/san-data-systems/common/config"
+	"github.com/san-data-systems/common/utils"
+)
+
+// runToken runs the token subcommands. Tokens can only be issued in debug mode, so that a production
+// binary cannot be used to mint credentials.
+func runToken(args []string) error {
+	if len(args) == 0 || args[0] != "issue" {
+		fmt.Fprintf(os.Stderr, "Usage: %s token issue --email <email> [--name <name>]\n", os.Args[0])
+		return errors.New("expected the issue subcommand")
+	}
+
+	flags := flag.NewFlagSet("token issue", flag.ExitOnError)
+	flags.Usage = usageFor(flags, "token issue --email <email> [--name <name>]", "Print a JWT for a user. Only available in debug mode.")
+	email := flags.String("email", "", "email of the user the token is issued to (required)")
+	name := flags.String("name", "", "name of the user, the local part of the email by default")
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	if config.Config.Mode != gin.DebugMode {
+		return fmt.Errorf("tokens can only be issued in %s mode, the server runs in %q mode", gin.DebugMode, config.Config.Mode)
+	}
+	if _, err := mail.ParseAddress(*email); err != nil || *email == "" {
+		flags.Usage()
+		return errors.New("a valid --email is required")
+	}
+	if *name == "" {
+		*name = strings.SplitN(*email, "@", 2)[0]
+	}
+
+	token, err := utils.GenerateJWT(*email, *name, *email)
+	if err != nil {
+		return fmt.Errorf("failed to generate token: %w", err)
+	}
+	fmt.Println(token)
+	return nil
+}
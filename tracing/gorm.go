@@ -0,0 +1,90 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// spanKey is the statement setting holding the span of a query.
+const spanKey = "tracing:span"
+
+// GORMPlugin traces the queries run through a GORM database. Spans are children of the span in the
+// statement's context, so queries run on a database bound to a request's context with WithContext are
+// part of the request's trace.
+type GORMPlugin struct{}
+
+// Name returns the name of the plugin.
+func (GORMPlugin) Name() string {
+	return "tracing"
+}
+
+// Initialize registers the callbacks tracing each kind of query.
+func (GORMPlugin) Initialize(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, err := range []error{
+		callbacks.Create().Before("*").Register("tracing:before_create", before("create")),
+		callbacks.Create().After("*").Register("tracing:after_create", after),
+		callbacks.Query().Before("*").Register("tracing:before_query", before("query")),
+		callbacks.Query().After("*").Register("tracing:after_query", after),
+		callbacks.Update().Before("*").Register("tracing:before_update", before("update")),
+		callbacks.Update().After("*").Register("tracing:after_update", after),
+		callbacks.Delete().Before("*").Register("tracing:before_delete", before("delete")),
+		callbacks.Delete().After("*").Register("tracing:after_delete", after),
+		callbacks.Row().Before("*").Register("tracing:before_row", before("row")),
+		callbacks.Row().After("*").Register("tracing:after_row", after),
+		callbacks.Raw().Before("*").Register("tracing:before_raw", before("raw")),
+		callbacks.Raw().After("*").Register("tracing:after_raw", after),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// before starts the span of a query.
+func before(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		name := "gorm." + operation
+		if db.Statement.Table != "" {
+			name += " " + db.Statement.Table
+		}
+		ctx := db.Statement.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		_, span := Tracer().Start(ctx, name,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL, semconv.DBOperationName(operation), semconv.DBCollectionName(db.Statement.Table)),
+		)
+		db.InstanceSet(spanKey, span)
+	}
+}
+
+// after ends the span of a query with the statement it ran, without its parameters, and its outcome.
+func after(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		semconv.DBQueryText(db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
@@ -0,0 +1,95 @@
+// Package tracing sets up OpenTelemetry tracing: spans for HTTP requests, database queries and object
+// storage calls, W3C trace context propagation, and export to an OTLP collector. Export is configured
+// with the standard OTEL_* environment variables and is enabled when an OTLP endpoint is set.
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ServiceName is the service name reported on spans unless OTEL_SERVICE_NAME is set.
+const ServiceName = "project-management-api"
+
+// RequestIDHeader is the header carrying the ID RequestIDMiddleware assigns to a request.
+const RequestIDHeader = "X-Request-ID"
+
+// instrumentationName identifies the spans created by the service itself.
+const instrumentationName = "github.com/san-data-systems/project-management-api"
+
+// Tracer returns the tracer of the service's own spans.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Setup installs the W3C trace context and baggage propagators and, when OTEL_EXPORTER_OTLP_ENDPOINT or
+// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT is set, a tracer provider exporting spans over OTLP/HTTP. Sampling
+// follows OTEL_TRACES_SAMPLER, sampling every trace not sampled out upstream by default. It returns a
+// function flushing and stopping the exporter, to call on shutdown.
+func Setup(ctx context.Context) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter), sdktrace.WithResource(res))
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Middleware starts a span for each HTTP request, continuing the trace of the caller when the request
+// carries trace context, and names it after the route template.
+func Middleware() gin.HandlerFunc {
+	return otelgin.Middleware(ServiceName)
+}
+
+// RequestIDAttribute tags the span of a request with the ID RequestIDMiddleware assigned to it. It must
+// run after both RequestIDMiddleware and Middleware.
+func RequestIDAttribute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Writer.Header().Get(RequestIDHeader)
+		if id == "" {
+			id = c.GetHeader(RequestIDHeader)
+		}
+		if id != "" {
+			trace.SpanFromContext(c.Request.Context()).SetAttributes(attribute.String("request.id", id))
+		}
+		c.Next()
+	}
+}
+
+// Transport wraps an HTTP transport so that outgoing requests are traced and carry the trace context.
+func Transport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base)
+}